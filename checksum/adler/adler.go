@@ -0,0 +1,86 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+package adler
+
+//https://datatracker.ietf.org/doc/html/rfc1950 (section 8.2)
+//https://github.com/madler/zlib/blob/master/adler32.c (adler32_combine)
+
+import (
+	"encoding/binary"
+	"hash"
+
+	"github.com/gnabgib/gnablib-go/checksum"
+)
+
+const (
+	size = 4
+	mod  = 65521 //Largest prime smaller than 65536
+	//Largest n such that 255n(n+1)/2 + (n+1)(mod-1) <= 2^32-1
+	nmax = 5552
+)
+
+type digest uint32
+
+// A new Hash32 for computing the Adler-32 checksum
+func New() hash.Hash32 {
+	d := new(digest)
+	d.Reset()
+	return d
+}
+
+func (d *digest) update(p []byte) {
+	s1, s2 := uint32(*d&0xffff), uint32(*d>>16)
+	for len(p) > 0 {
+		n := len(p)
+		if n > nmax {
+			n = nmax
+		}
+		for _, b := range p[:n] {
+			s1 += uint32(b)
+			s2 += s1
+		}
+		s1 %= mod
+		s2 %= mod
+		p = p[n:]
+	}
+	*d = digest(s2<<16 | s1)
+}
+
+func (d *digest) Write(p []byte) (n int, err error) {
+	d.update(p)
+	return len(p), nil
+}
+
+func (d *digest) Sum(in []byte) []byte {
+	s := uint32(*d)
+	return append(in, byte(s>>24), byte(s>>16), byte(s>>8), byte(s))
+}
+
+func (d *digest) Reset() { *d = 1 }
+
+func (d *digest) Size() int { return size }
+
+func (d *digest) BlockSize() int { return 4 }
+
+func (d *digest) Sum32() uint32 { return uint32(*d) }
+
+// Update the state as if the `n` bytes summarized by `sum` had been written
+func (d *digest) Combine(sum []byte, n int64) error {
+	if len(sum) != size {
+		return checksum.ErrCombineSize
+	}
+	*d = digest(Combine(uint32(*d), binary.BigEndian.Uint32(sum), n))
+	return nil
+}
+
+// Adler-32 of A||B, from the checksums of A and B, and the length of B in bytes
+func Combine(sumA, sumB uint32, lenB int64) uint32 {
+	//Both sums start at 1, so: a=a1+a2-1, b=b1+b2+n(a1-1)
+	n := uint64(lenB % mod)
+	a1, b1 := uint64(sumA&0xffff), uint64(sumA>>16)
+	a2, b2 := uint64(sumB&0xffff), uint64(sumB>>16)
+	a := (a1 + a2 + mod - 1) % mod
+	b := (b1 + b2 + n*((a1+mod-1)%mod)) % mod
+	return uint32(b<<16 | a)
+}
@@ -0,0 +1,122 @@
+package adler
+
+import (
+	"bytes"
+	"fmt"
+	"hash"
+	"hash/adler32"
+	"math/rand"
+	"testing"
+
+	"github.com/gnabgib/gnablib-go/checksum"
+	"github.com/gnabgib/gnablib-go/test"
+)
+
+var adlerTests = []struct {
+	s string
+	c uint32
+}{
+	//Wiki
+	{"Wikipedia", 0x11E60398},
+	//Others
+	{"", 1},
+	{"\x00", 0x10001},
+	{"\x01\x02\x03", 0xD0007},
+	{"a", 0x00620062},
+	{"abc", 0x024D0127},
+	{"abcde", 0x05C801F0},
+	{"gnabgib", 0x0B4202CB},
+}
+
+func TestAdler(t *testing.T) {
+	for _, rec := range adlerTests {
+		h := New()
+		h.Write([]byte(rec.s))
+		if found := h.Sum32(); found != rec.c {
+			t.Errorf("Hashing %q, expecting %x, got %x", rec.s, rec.c, found)
+		}
+	}
+}
+
+func TestAdlerConformance(t *testing.T) {
+	vectors := []test.HashVector{}
+	for _, rec := range adlerTests {
+		vectors = append(vectors, test.HashVector{In: rec.s, Hex: hexOf(rec.c)})
+	}
+	test.HashConformance(t, func() hash.Hash { return New() }, vectors)
+}
+
+func hexOf(c uint32) string {
+	return fmt.Sprintf("%08X", c)
+}
+
+// Long runs of 0xff push the sums towards overflow, the stdlib is the reference
+func TestAdlerStdlib(t *testing.T) {
+	rnd := rand.New(rand.NewSource(0x61646c))
+	for _, n := range []int{nmax - 1, nmax, nmax + 1, 3*nmax + 17, 100000} {
+		data := bytes.Repeat([]byte{0xff}, n)
+		h := New()
+		h.Write(data)
+		if found, expect := h.Sum32(), adler32.Checksum(data); found != expect {
+			t.Errorf("%d x ff: expecting %x, got %x", n, expect, found)
+		}
+		rnd.Read(data)
+		h.Reset()
+		h.Write(data)
+		if found, expect := h.Sum32(), adler32.Checksum(data); found != expect {
+			t.Errorf("%d random: expecting %x, got %x", n, expect, found)
+		}
+	}
+}
+
+func TestCombine(t *testing.T) {
+	rnd := rand.New(rand.NewSource(0x636f6d))
+	data := make([]byte, 20000)
+	rnd.Read(data)
+	for _, split := range []int{0, 1, 2, 3, 4, 1000, nmax, 19999, 20000} {
+		for _, end := range []int{split, split + 1, 20000} {
+			if end > len(data) {
+				continue
+			}
+			a, b := data[:split], data[split:end]
+			expect := adler32.Checksum(data[:end])
+			found := Combine(adler32.Checksum(a), adler32.Checksum(b), int64(len(b)))
+			if found != expect {
+				t.Errorf("Combine(%d,%d): expecting %x, got %x", split, end-split, expect, found)
+			}
+
+			h := New()
+			h.Write(a)
+			hb := New()
+			hb.Write(b)
+			h.(checksum.Combiner).Combine(hb.Sum(nil), int64(len(b)))
+			if h.Sum32() != expect {
+				t.Errorf("Combiner(%d,%d): expecting %x, got %x", split, end-split, expect, h.Sum32())
+			}
+		}
+	}
+	if err := New().(checksum.Combiner).Combine([]byte{1}, 1); err != checksum.ErrCombineSize {
+		t.Errorf("Expecting ErrCombineSize, got %v", err)
+	}
+}
+
+func TestParallel(t *testing.T) {
+	rnd := rand.New(rand.NewSource(0x706172))
+	data := make([]byte, 100003)
+	rnd.Read(data)
+	expect := adler32.Checksum(data)
+	for _, chunk := range []int64{1, 8, 1000, 4096, 100000, 200000} {
+		h, err := checksum.ParallelChunks(bytes.NewReader(data), int64(len(data)), chunk, func() hash.Hash { return New() })
+		if err != nil {
+			t.Errorf("Chunk %d: unexpected error %v", chunk, err)
+			continue
+		}
+		if found := h.(hash.Hash32).Sum32(); found != expect {
+			t.Errorf("Chunk %d: expecting %x, got %x", chunk, expect, found)
+		}
+	}
+	h, _ := checksum.Parallel(bytes.NewReader(data), int64(len(data)), func() hash.Hash { return New() })
+	if found := h.(hash.Hash32).Sum32(); found != expect {
+		t.Errorf("Parallel: expecting %x, got %x", expect, found)
+	}
+}
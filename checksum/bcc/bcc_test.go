@@ -1,7 +1,10 @@
 package bcc
 
 import (
+	"hash"
 	"testing"
+
+	"github.com/gnabgib/gnablib-go/test"
 )
 
 
@@ -50,3 +53,10 @@ func TestBcc(t *testing.T) {
 		}
 	}
 }
+
+func TestBccConformance(t *testing.T) {
+	vectors := test.SumVectors(len(tests), 1, func(i int) (string, uint64) {
+		return tests[i].s, uint64(tests[i].b)
+	})
+	test.HashConformance(t, func() hash.Hash { return New() }, vectors)
+}
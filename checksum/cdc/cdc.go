@@ -0,0 +1,212 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+// Content defined chunking with FastCDC, boundaries depend on the content so
+// an insert or delete only changes nearby chunks (good for deduplication)
+package cdc
+
+//https://www.usenix.org/system/files/conference/atc16/atc16-paper-xia.pdf
+//Gear hash: fp = (fp<<1) + G[b], bit k of fp depends on the last k+1 bytes so
+//mask bits are spread over the top 48 bits (a 48 byte window)
+
+import (
+	"errors"
+	"hash"
+	"io"
+	"math"
+)
+
+// The chunk sizes aren't in order, or are out of range
+var ErrSizes = errors.New("chunk sizes must be 64 <= min <= avg <= max <= 1GiB")
+
+// The normalization level is more than MaxLevel
+var ErrLevel = errors.New("normalization level must be 0-3")
+
+const (
+	// Smallest allowed minimum chunk size
+	MinSize = 64
+	// Largest allowed maximum chunk size
+	MaxSize = 1 << 30
+	// Most mask bits normalization can move
+	MaxLevel = 3
+)
+
+// Chunk size limits and the normalization level
+type Params struct {
+	Min, Avg, Max int
+	//Normalized chunking: chunks shorter than Avg need Level more mask bits to
+	//cut, longer need Level fewer, so sizes cluster around Avg (0 disables)
+	Level uint
+}
+
+// The paper's suggested parameters: 2KiB, 8KiB, 64KiB with level 2
+var Default = Params{Min: 2 << 10, Avg: 8 << 10, Max: 64 << 10, Level: 2}
+
+// Gear table, generated from a fixed seed so boundaries never change
+var gear [256]uint64
+
+func init() {
+	//SplitMix64 seeded with "gnabgib\x00"
+	seed := uint64(0x676e616267696200)
+	for i := range gear {
+		seed += 0x9e3779b97f4a7c15
+		z := seed
+		z = (z ^ z>>30) * 0xbf58476d1ce4e5b9
+		z = (z ^ z>>27) * 0x94d049bb133111eb
+		gear[i] = z ^ z>>31
+	}
+}
+
+// A mask with `n` bits spread evenly over the top 48 bits
+func spreadMask(n int) uint64 {
+	var m uint64
+	step := 48 / n
+	for i := 0; i < n; i++ {
+		m |= 1 << (63 - i*step)
+	}
+	return m
+}
+
+// Check the sizes and level, and build the small/large masks
+func (p *Params) masks() (maskS, maskL uint64, err error) {
+	if p.Min < MinSize || p.Min > p.Avg || p.Avg > p.Max || p.Max > MaxSize {
+		return 0, 0, ErrSizes
+	}
+	if p.Level > MaxLevel {
+		return 0, 0, ErrLevel
+	}
+	bits := int(math.Round(math.Log2(float64(p.Avg))))
+	return spreadMask(bits + int(p.Level)), spreadMask(bits - int(p.Level)), nil
+}
+
+// The length of the first chunk of data (data shorter than Min is one chunk)
+func cut(data []byte, p *Params, maskS, maskL uint64) int {
+	n := len(data)
+	if n <= p.Min {
+		return n
+	}
+	if n > p.Max {
+		n = p.Max
+	}
+	center := p.Avg
+	if n < center {
+		center = n
+	}
+	//Cut point skipping: the first Min bytes can't be a boundary
+	var fp uint64
+	i := p.Min
+	for ; i < center; i++ {
+		fp = fp<<1 + gear[data[i]]
+		if fp&maskS == 0 {
+			return i
+		}
+	}
+	for ; i < n; i++ {
+		fp = fp<<1 + gear[data[i]]
+		if fp&maskL == 0 {
+			return i
+		}
+	}
+	return n
+}
+
+// The length of the first chunk of data. Returns ErrSizes/ErrLevel if the
+// params are invalid
+func Cut(data []byte, p Params) (int, error) {
+	maskS, maskL, err := p.masks()
+	if err != nil {
+		return 0, err
+	}
+	return cut(data, &p, maskS, maskL), nil
+}
+
+// Splits a stream into content defined chunks. The buffer is reused, so after
+// setup Next doesn't allocate
+type Chunker struct {
+	r            io.Reader
+	p            Params
+	maskS, maskL uint64
+	h            hash.Hash
+	buf          []byte
+	start, end   int   //Unconsumed data is buf[start:end]
+	off          int64 //Stream offset of buf[start]
+	eof          bool
+	chunk        []byte
+	sum          []byte
+}
+
+// A new Chunker reading from `r`. If `h` isn't nil, each chunk is fingerprinted
+// with it (eg. ripemd.New160()), see Sum. Returns ErrSizes/ErrLevel if the
+// params are invalid
+func NewChunker(r io.Reader, p Params, h hash.Hash) (*Chunker, error) {
+	maskS, maskL, err := p.masks()
+	if err != nil {
+		return nil, err
+	}
+	c := &Chunker{r: r, p: p, maskS: maskS, maskL: maskL, h: h, buf: make([]byte, 2*p.Max)}
+	if h != nil {
+		c.sum = make([]byte, 0, h.Size())
+	}
+	return c, nil
+}
+
+// Reads returning no data and no error before giving up (as bufio)
+const maxEmptyReads = 100
+
+// Top up the buffer so it holds at least Max bytes (unless the stream ends),
+// io.ErrNoProgress if the reader keeps returning nothing
+func (c *Chunker) fill() error {
+	if c.eof || c.end-c.start >= c.p.Max {
+		return nil
+	}
+	if c.start > 0 {
+		c.end = copy(c.buf, c.buf[c.start:c.end])
+		c.start = 0
+	}
+	empty := 0
+	for c.end < len(c.buf) {
+		n, err := c.r.Read(c.buf[c.end:])
+		c.end += n
+		if err == io.EOF {
+			c.eof = true
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			empty = 0
+		} else if empty++; empty >= maxEmptyReads {
+			return io.ErrNoProgress
+		}
+	}
+	return nil
+}
+
+// The offset and length of the next chunk, io.EOF once the stream is consumed
+func (c *Chunker) Next() (offset int64, length int, err error) {
+	if err = c.fill(); err != nil {
+		return 0, 0, err
+	}
+	if c.start == c.end {
+		return 0, 0, io.EOF
+	}
+	length = cut(c.buf[c.start:c.end], &c.p, c.maskS, c.maskL)
+	offset = c.off
+	c.chunk = c.buf[c.start : c.start+length]
+	c.start += length
+	c.off += int64(length)
+	if c.h != nil {
+		c.h.Reset()
+		c.h.Write(c.chunk)
+		c.sum = c.h.Sum(c.sum[:0])
+	}
+	return
+}
+
+// Content of the last chunk, only valid until the next call to Next
+func (c *Chunker) Bytes() []byte { return c.chunk }
+
+// Fingerprint of the last chunk (nil without a hash), only valid until the
+// next call to Next
+func (c *Chunker) Sum() []byte { return c.sum }
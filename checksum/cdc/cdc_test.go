@@ -0,0 +1,271 @@
+package cdc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/gnabgib/gnablib-go/checksum/crc"
+	"github.com/gnabgib/gnablib-go/hash/ripemd"
+)
+
+// Content from SplitMix64 (seed 1) as little endian words
+func testData(n int) []byte {
+	ret := make([]byte, n)
+	seed := uint64(1)
+	for i := 0; i+8 <= n; i += 8 {
+		seed += 0x9e3779b97f4a7c15
+		z := seed
+		z = (z ^ z>>30) * 0xbf58476d1ce4e5b9
+		z = (z ^ z>>27) * 0x94d049bb133111eb
+		binary.LittleEndian.PutUint64(ret[i:], z^z>>31)
+	}
+	return ret
+}
+
+type chunk struct {
+	off int64
+	l   int
+}
+
+func allChunks(t *testing.T, r io.Reader, p Params) []chunk {
+	c, err := NewChunker(r, p, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	ret := []chunk{}
+	for {
+		off, l, err := c.Next()
+		if err == io.EOF {
+			return ret
+		}
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+		ret = append(ret, chunk{off, l})
+	}
+}
+
+func TestChunker(t *testing.T) {
+	data := testData(200000)
+	//No published vector (the gear table is ours), regression only
+	tests := []struct {
+		p     Params
+		count int
+		first []chunk
+		last  chunk
+	}{
+		{Default, 20, []chunk{{0, 14164}, {14164, 8680}, {22844, 10706}, {33550, 8543}, {42093, 12568}, {54661, 8682}}, chunk{195356, 4644}},
+		{Params{256, 1024, 4096, 0}, 151, []chunk{{0, 1049}, {1049, 1659}, {2708, 310}, {3018, 4096}, {7114, 683}, {7797, 4096}}, chunk{197201, 2799}},
+	}
+	for _, rec := range tests {
+		found := allChunks(t, bytes.NewReader(data), rec.p)
+		if len(found) != rec.count {
+			t.Errorf("%v expecting %d chunks, got %d", rec.p, rec.count, len(found))
+			continue
+		}
+		for i, c := range rec.first {
+			if found[i] != c {
+				t.Errorf("%v chunk %d expecting %v, got %v", rec.p, i, c, found[i])
+			}
+		}
+		if last := found[len(found)-1]; last != rec.last {
+			t.Errorf("%v last expecting %v, got %v", rec.p, rec.last, last)
+		}
+	}
+}
+
+func TestChunkerBounds(t *testing.T) {
+	data := make([]byte, 1<<20)
+	rand.New(rand.NewSource(16)).Read(data)
+	for _, p := range []Params{Default, {64, 64, 64, 0}, {512, 4096, 8192, 3}, {4096, 16384, 65536, 1}} {
+		//A reader that returns small reads checks the buffer top up
+		chunks := allChunks(t, io.LimitReader(&shortReader{bytes.NewReader(data)}, int64(len(data))), p)
+		var off int64
+		for i, c := range chunks {
+			if c.off != off {
+				t.Fatalf("%v chunk %d expecting offset %d, got %d", p, i, off, c.off)
+			}
+			if c.l > p.Max || (c.l < p.Min && i != len(chunks)-1) {
+				t.Errorf("%v chunk %d length %d out of bounds", p, i, c.l)
+			}
+			off += int64(c.l)
+		}
+		if off != int64(len(data)) {
+			t.Errorf("%v expecting %d bytes chunked, got %d", p, len(data), off)
+		}
+		avg := len(data) / len(chunks)
+		if avg < p.Avg/2 || avg > p.Avg*2 {
+			t.Errorf("%v average chunk %d far from %d", p, avg, p.Avg)
+		}
+	}
+}
+
+type shortReader struct{ r io.Reader }
+
+func (s *shortReader) Read(p []byte) (int, error) {
+	if len(p) > 1000 {
+		p = p[:1000]
+	}
+	return s.r.Read(p)
+}
+
+// Returns (0, nil) for `empty` reads before each real read, forever when r is nil
+type emptyReader struct {
+	r     io.Reader
+	empty int
+	n     int
+}
+
+func (e *emptyReader) Read(p []byte) (int, error) {
+	if e.r == nil || e.n < e.empty {
+		e.n++
+		return 0, nil
+	}
+	e.n = 0
+	return e.r.Read(p)
+}
+
+func TestChunkerNoProgress(t *testing.T) {
+	c, _ := NewChunker(&emptyReader{}, Default, nil)
+	if _, _, err := c.Next(); err != io.ErrNoProgress {
+		t.Errorf("Expecting io.ErrNoProgress, got %v", err)
+	}
+	//Some empty reads between data are fine
+	data := testData(100000)
+	found := allChunks(t, &emptyReader{r: bytes.NewReader(data), empty: maxEmptyReads - 1}, Default)
+	expect := allChunks(t, bytes.NewReader(data), Default)
+	if len(found) != len(expect) {
+		t.Errorf("Expecting %d chunks, got %d", len(expect), len(found))
+	}
+}
+
+func TestChunkerMatchesCut(t *testing.T) {
+	data := testData(100000)
+	chunks := allChunks(t, bytes.NewReader(data), Default)
+	off := 0
+	for i, c := range chunks {
+		l, err := Cut(data[off:], Default)
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+		if l != c.l {
+			t.Errorf("Chunk %d expecting %d, got %d", i, c.l, l)
+		}
+		off += l
+	}
+}
+
+func TestChunkerShift(t *testing.T) {
+	//Inserting content only changes nearby chunks
+	data := testData(500000)
+	shifted := append([]byte("gnabgib was here"), data...)
+	a := allChunks(t, bytes.NewReader(data), Default)
+	b := allChunks(t, bytes.NewReader(shifted), Default)
+	ends := map[int64]bool{}
+	for _, c := range a {
+		ends[c.off+int64(c.l)] = true
+	}
+	same := 0
+	for _, c := range b {
+		if ends[c.off+int64(c.l)-16] {
+			same++
+		}
+	}
+	if same < len(b)-2 {
+		t.Errorf("Expecting most boundaries to survive, %d of %d did", same, len(b))
+	}
+}
+
+func TestChunkerEdit(t *testing.T) {
+	//Chunks before an insert are unchanged, and after it the boundaries resync
+	// so only the chunks near the edit differ
+	data := testData(1 << 20)
+	rng := rand.New(rand.NewSource(43))
+	a := allChunks(t, bytes.NewReader(data), Default)
+	for i := 0; i < 20; i++ {
+		at := rng.Intn(len(data))
+		ins := make([]byte, 1+rng.Intn(100))
+		rng.Read(ins)
+		edited := append(append(append([]byte{}, data[:at]...), ins...), data[at:]...)
+		b := allChunks(t, bytes.NewReader(edited), Default)
+		same := func(x, y chunk) bool {
+			return x.l == y.l && bytes.Equal(data[x.off:x.off+int64(x.l)], edited[y.off:y.off+int64(y.l)])
+		}
+		pre := 0
+		for pre < len(a) && pre < len(b) && same(a[pre], b[pre]) {
+			pre++
+		}
+		if pre < len(a) && a[pre].off+int64(a[pre].l) <= int64(at) {
+			t.Errorf("Insert @%d changed chunk %d before the edit", at, pre)
+		}
+		post := 0
+		for post < len(a)-pre && post < len(b)-pre && same(a[len(a)-1-post], b[len(b)-1-post]) {
+			post++
+		}
+		if changed := len(b) - pre - post; changed > 3 {
+			t.Errorf("Insert of %d @%d changed %d chunks", len(ins), at, changed)
+		}
+	}
+}
+
+func TestChunkerSum(t *testing.T) {
+	data := testData(50000)
+	c, _ := NewChunker(bytes.NewReader(data), Default, ripemd.New160())
+	for {
+		off, l, err := c.Next()
+		if err == io.EOF {
+			break
+		}
+		h := ripemd.New160()
+		h.Write(data[off : off+int64(l)])
+		if expect := h.Sum(nil); !bytes.Equal(c.Sum(), expect) {
+			t.Errorf("Chunk @%d expecting %x, got %x", off, expect, c.Sum())
+		}
+		if !bytes.Equal(c.Bytes(), data[off:off+int64(l)]) {
+			t.Errorf("Chunk @%d content mismatch", off)
+		}
+	}
+}
+
+func TestChunkerAllocs(t *testing.T) {
+	h, _ := crc.New(crc.CRC32)
+	c, _ := NewChunker(rand.New(rand.NewSource(1)), Default, h)
+	allocs := testing.AllocsPerRun(1000, func() {
+		c.Next()
+	})
+	if allocs != 0 {
+		t.Errorf("Expecting 0 allocations per chunk, got %v", allocs)
+	}
+}
+
+func TestParams(t *testing.T) {
+	tests := []struct {
+		p   Params
+		err error
+	}{
+		{Default, nil},
+		{Params{32, 64, 128, 0}, ErrSizes},
+		{Params{1024, 512, 2048, 0}, ErrSizes},
+		{Params{256, 4096, 2048, 0}, ErrSizes},
+		{Params{256, 4096, MaxSize + 1, 0}, ErrSizes},
+		{Params{256, 1024, 4096, 4}, ErrLevel},
+	}
+	for _, rec := range tests {
+		if _, err := NewChunker(bytes.NewReader(nil), rec.p, nil); err != rec.err {
+			t.Errorf("%v expecting %v, got %v", rec.p, rec.err, err)
+		}
+		if _, err := Cut(nil, rec.p); err != rec.err {
+			t.Errorf("%v expecting %v, got %v", rec.p, rec.err, err)
+		}
+	}
+	//Empty and tiny streams
+	if chunks := allChunks(t, bytes.NewReader(nil), Default); len(chunks) != 0 {
+		t.Errorf("Expecting no chunks, got %v", chunks)
+	}
+	if chunks := allChunks(t, bytes.NewReader([]byte("gnabgib")), Default); len(chunks) != 1 || chunks[0].l != 7 {
+		t.Errorf("Expecting one chunk, got %v", chunks)
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+package checksum
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Symbols check digit schemes ignore in input (unless they're part of the alphabet)
+const Separators = " -"
+
+// A character that isn't part of the scheme's alphabet
+var ErrInvalidChar = errors.New("invalid character")
+
+// An invalid character, at its position in the input (including separators)
+type InvalidCharError struct {
+	Scheme string //Name of the check scheme (eg. Luhn)
+	Byte   byte
+	At     int
+}
+
+func (e InvalidCharError) Error() string {
+	return fmt.Sprintf("Invalid %s character: %q @ %d", e.Scheme, e.Byte, e.At)
+}
+
+func (e InvalidCharError) Unwrap() error { return ErrInvalidChar }
+
+// An invalid character(b) found at position(at) in the input to `scheme`
+func InvalidCharAt(scheme string, b byte, at int) InvalidCharError {
+	return InvalidCharError{Scheme: scheme, Byte: b, At: at}
+}
+
+// Whether b is one of the Separators
+func IsSeparator(b byte) bool {
+	return b == Separators[0] || b == Separators[1]
+}
+
+// Upper case an ASCII letter, any other byte is returned unchanged
+func Upper(b byte) byte {
+	if b >= 'a' && b <= 'z' {
+		return b - ('a' - 'A')
+	}
+	return b
+}
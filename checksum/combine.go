@@ -0,0 +1,134 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+package checksum
+
+import (
+	"errors"
+	"hash"
+	"io"
+	"runtime"
+	"sync"
+)
+
+// The hash doesn't implement Combiner
+var ErrNotCombinable = errors.New("checksum can't be combined")
+
+// The sum given to Combine is the wrong size for the checksum
+var ErrCombineSize = errors.New("combine sum is the wrong size")
+
+// Word based checksums can only be combined on a word boundary
+var ErrCombineAlign = errors.New("combine requires the prior data to be whole words")
+
+// A checksum that can be computed from the sums of consecutive parts
+type Combiner interface {
+	hash.Hash
+
+	// Update the state as if the `n` bytes that produced `sum` (in a fresh
+	// instance of the same checksum) had been written
+	Combine(sum []byte, n int64) error
+}
+
+// Smallest part Parallel will hand to a goroutine
+const minParallelChunk = 1 << 20
+
+// Compute a combinable checksum over `size` bytes of `r` by splitting the
+// content across goroutines (one per CPU) and combining the parts. The returned
+// hash can be asserted to the type `factory` produces. Returns ErrNotCombinable
+// if `factory` doesn't produce a Combiner
+func Parallel(r io.ReaderAt, size int64, factory func() hash.Hash) (hash.Hash, error) {
+	chunk := (size + int64(runtime.NumCPU()) - 1) / int64(runtime.NumCPU())
+	if chunk < minParallelChunk {
+		chunk = minParallelChunk
+	}
+	return ParallelChunks(r, size, chunk, factory)
+}
+
+// Like Parallel, but the content is split into `chunk` byte parts (rounded up to
+// a multiple of 8 so word based checksums stay aligned) which are processed by a
+// pool of goroutines (one per CPU)
+func ParallelChunks(r io.ReaderAt, size, chunk int64, factory func() hash.Hash) (hash.Hash, error) {
+	first, ok := factory().(Combiner)
+	if !ok {
+		return nil, ErrNotCombinable
+	}
+	if chunk < 8 {
+		chunk = 8
+	}
+	chunk = (chunk + 7) &^ 7
+	n := int((size + chunk - 1) / chunk)
+	if n <= 1 {
+		m, err := io.Copy(first, io.NewSectionReader(r, 0, size))
+		if err == nil && m != size {
+			err = io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return nil, err
+		}
+		return first, nil
+	}
+
+	//The first part is written to `first`, the rest are summed by each worker's
+	//own (reset) hash
+	sums := make([][]byte, n)
+	errs := make([]error, n)
+	indexes := make(chan int)
+	workers := runtime.NumCPU()
+	if workers > n {
+		workers = n
+	}
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			h := factory()
+			buf := make([]byte, 32*1024)
+			for i := range indexes {
+				off := int64(i) * chunk
+				l := chunk
+				if off+l > size {
+					l = size - off
+				}
+				part := io.NewSectionReader(r, off, l)
+				dst := h
+				if i == 0 {
+					dst = first
+				} else {
+					h.Reset()
+				}
+				//A short reader would otherwise give a wrong sum
+				m, err := io.CopyBuffer(dst, part, buf)
+				if err == nil && m != l {
+					err = io.ErrUnexpectedEOF
+				}
+				errs[i] = err
+				if err == nil && i > 0 {
+					sums[i] = h.Sum(nil)
+				}
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			continue
+		}
+		l := chunk
+		if i == n-1 {
+			l = size - int64(i)*chunk
+		}
+		if err := first.Combine(sums[i], l); err != nil {
+			return nil, err
+		}
+	}
+	return first, nil
+}
@@ -0,0 +1,108 @@
+package checksum
+
+import (
+	"bytes"
+	"errors"
+	"hash"
+	"hash/crc32"
+	"io"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"testing/iotest"
+)
+
+func TestParallel_notCombinable(t *testing.T) {
+	_, err := Parallel(bytes.NewReader(nil), 0, func() hash.Hash { return crc32.NewIEEE() })
+	if err != ErrNotCombinable {
+		t.Errorf("Expecting ErrNotCombinable, got %v", err)
+	}
+}
+
+// Treats the content as a number, base 256 (mod 2^32).. enough to check the parts
+// are combined in order
+type base256 uint32
+
+func (d *base256) Write(p []byte) (int, error) {
+	for _, b := range p {
+		*d = *d<<8 | base256(b)
+	}
+	return len(p), nil
+}
+func (d *base256) Sum(in []byte) []byte {
+	return append(in, byte(*d>>24), byte(*d>>16), byte(*d>>8), byte(*d))
+}
+func (d *base256) Reset()         { *d = 0 }
+func (d *base256) Size() int      { return 4 }
+func (d *base256) BlockSize() int { return 1 }
+func (d *base256) Combine(sum []byte, n int64) error {
+	for i := int64(0); i < n && i < 4; i++ {
+		*d <<= 8
+	}
+	*d |= base256(uint32(sum[0])<<24 | uint32(sum[1])<<16 | uint32(sum[2])<<8 | uint32(sum[3]))
+	return nil
+}
+
+func TestParallelChunks(t *testing.T) {
+	data := []byte("0123456789abcdefghijklmnopqrstu")
+	for _, chunk := range []int64{0, 1, 8, 9, 16, 100} {
+		h, err := ParallelChunks(bytes.NewReader(data), int64(len(data)), chunk, func() hash.Hash { return new(base256) })
+		if err != nil {
+			t.Errorf("Chunk %d: unexpected error %v", chunk, err)
+			continue
+		}
+		if found := string(h.Sum(nil)); found != "rstu" {
+			t.Errorf("Chunk %d: expecting rstu, got %q", chunk, found)
+		}
+	}
+}
+
+func TestParallelChunks_bounded(t *testing.T) {
+	data := make([]byte, 64<<10)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	var made int32
+	h, err := ParallelChunks(bytes.NewReader(data), int64(len(data)), 8, func() hash.Hash {
+		atomic.AddInt32(&made, 1)
+		return new(base256)
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if found := h.Sum(nil); !bytes.Equal(found, data[len(data)-4:]) {
+		t.Errorf("Expecting %x, got %x", data[len(data)-4:], found)
+	}
+	//8K chunks, but only a hash for the first part and one per worker
+	if max := int32(runtime.NumCPU() + 1); made > max {
+		t.Errorf("Expecting at most %d hashes, got %d", max, made)
+	}
+}
+
+type errReaderAt struct{ err error }
+
+func (e errReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	return iotest.ErrReader(e.err).Read(p)
+}
+
+func TestParallelChunks_readError(t *testing.T) {
+	bad := errors.New("bad read")
+	for _, chunk := range []int64{8, 100} {
+		_, err := ParallelChunks(errReaderAt{bad}, 64, chunk, func() hash.Hash { return new(base256) })
+		if err != bad {
+			t.Errorf("Chunk %d: expecting read error, got %v", chunk, err)
+		}
+	}
+}
+
+func TestParallelChunks_shortReader(t *testing.T) {
+	//The reader holds less than the claimed size, both on the single part path
+	//and when split
+	data := make([]byte, 800)
+	for _, chunk := range []int64{64, 2000} {
+		_, err := ParallelChunks(bytes.NewReader(data), 1000, chunk, func() hash.Hash { return new(base256) })
+		if err != io.ErrUnexpectedEOF {
+			t.Errorf("Chunk %d: expecting io.ErrUnexpectedEOF, got %v", chunk, err)
+		}
+	}
+}
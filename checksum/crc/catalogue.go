@@ -0,0 +1,298 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+package crc
+
+//https://reveng.sourceforge.io/crc-catalogue/all.htm (CRCs of width 3-64)
+
+// The RevEng catalogue, as values so each use works on its own copy -- --
+var (
+	CRC3Gsm            = Params{"CRC-3/GSM", 3, 0x3, 0x0, false, false, 0x7, 0x4}
+	CRC3Rohc           = Params{"CRC-3/ROHC", 3, 0x3, 0x7, true, true, 0x0, 0x6}
+	CRC4G704           = Params{"CRC-4/G-704", 4, 0x3, 0x0, true, true, 0x0, 0x7}
+	CRC4Interlaken     = Params{"CRC-4/INTERLAKEN", 4, 0x3, 0xf, false, false, 0xf, 0xb}
+	CRC5EpcC1g2        = Params{"CRC-5/EPC-C1G2", 5, 0x09, 0x09, false, false, 0x00, 0x00}
+	CRC5G704           = Params{"CRC-5/G-704", 5, 0x15, 0x00, true, true, 0x00, 0x07}
+	CRC5Usb            = Params{"CRC-5/USB", 5, 0x05, 0x1f, true, true, 0x1f, 0x19}
+	CRC6Cdma2000A      = Params{"CRC-6/CDMA2000-A", 6, 0x27, 0x3f, false, false, 0x00, 0x0d}
+	CRC6Cdma2000B      = Params{"CRC-6/CDMA2000-B", 6, 0x07, 0x3f, false, false, 0x00, 0x3b}
+	CRC6Darc           = Params{"CRC-6/DARC", 6, 0x19, 0x00, true, true, 0x00, 0x26}
+	CRC6G704           = Params{"CRC-6/G-704", 6, 0x03, 0x00, true, true, 0x00, 0x06}
+	CRC6Gsm            = Params{"CRC-6/GSM", 6, 0x2f, 0x00, false, false, 0x3f, 0x13}
+	CRC7Mmc            = Params{"CRC-7/MMC", 7, 0x09, 0x00, false, false, 0x00, 0x75}
+	CRC7Rohc           = Params{"CRC-7/ROHC", 7, 0x4f, 0x7f, true, true, 0x00, 0x53}
+	CRC7Umts           = Params{"CRC-7/UMTS", 7, 0x45, 0x00, false, false, 0x00, 0x61}
+	CRC8Autosar        = Params{"CRC-8/AUTOSAR", 8, 0x2f, 0xff, false, false, 0xff, 0xdf}
+	CRC8Bluetooth      = Params{"CRC-8/BLUETOOTH", 8, 0xa7, 0x00, true, true, 0x00, 0x26}
+	CRC8Cdma2000       = Params{"CRC-8/CDMA2000", 8, 0x9b, 0xff, false, false, 0x00, 0xda}
+	CRC8Darc           = Params{"CRC-8/DARC", 8, 0x39, 0x00, true, true, 0x00, 0x15}
+	CRC8DvbS2          = Params{"CRC-8/DVB-S2", 8, 0xd5, 0x00, false, false, 0x00, 0xbc}
+	CRC8GsmA           = Params{"CRC-8/GSM-A", 8, 0x1d, 0x00, false, false, 0x00, 0x37}
+	CRC8GsmB           = Params{"CRC-8/GSM-B", 8, 0x49, 0x00, false, false, 0xff, 0x94}
+	CRC8Hitag          = Params{"CRC-8/HITAG", 8, 0x1d, 0xff, false, false, 0x00, 0xb4}
+	CRC8I4321          = Params{"CRC-8/I-432-1", 8, 0x07, 0x00, false, false, 0x55, 0xa1}
+	CRC8ICode          = Params{"CRC-8/I-CODE", 8, 0x1d, 0xfd, false, false, 0x00, 0x7e}
+	CRC8Lte            = Params{"CRC-8/LTE", 8, 0x9b, 0x00, false, false, 0x00, 0xea}
+	CRC8MaximDow       = Params{"CRC-8/MAXIM-DOW", 8, 0x31, 0x00, true, true, 0x00, 0xa1}
+	CRC8MifareMad      = Params{"CRC-8/MIFARE-MAD", 8, 0x1d, 0xc7, false, false, 0x00, 0x99}
+	CRC8Nrsc5          = Params{"CRC-8/NRSC-5", 8, 0x31, 0xff, false, false, 0x00, 0xf7}
+	CRC8Opensafety     = Params{"CRC-8/OPENSAFETY", 8, 0x2f, 0x00, false, false, 0x00, 0x3e}
+	CRC8Rohc           = Params{"CRC-8/ROHC", 8, 0x07, 0xff, true, true, 0x00, 0xd0}
+	CRC8SaeJ1850       = Params{"CRC-8/SAE-J1850", 8, 0x1d, 0xff, false, false, 0xff, 0x4b}
+	CRC8Smbus          = Params{"CRC-8/SMBUS", 8, 0x07, 0x00, false, false, 0x00, 0xf4}
+	CRC8Tech3250       = Params{"CRC-8/TECH-3250", 8, 0x1d, 0xff, true, true, 0x00, 0x97}
+	CRC8Wcdma          = Params{"CRC-8/WCDMA", 8, 0x9b, 0x00, true, true, 0x00, 0x25}
+	CRC10Atm           = Params{"CRC-10/ATM", 10, 0x233, 0x000, false, false, 0x000, 0x199}
+	CRC10Cdma2000      = Params{"CRC-10/CDMA2000", 10, 0x3d9, 0x3ff, false, false, 0x000, 0x233}
+	CRC10Gsm           = Params{"CRC-10/GSM", 10, 0x175, 0x000, false, false, 0x3ff, 0x12a}
+	CRC11Flexray       = Params{"CRC-11/FLEXRAY", 11, 0x385, 0x01a, false, false, 0x000, 0x5a3}
+	CRC11Umts          = Params{"CRC-11/UMTS", 11, 0x307, 0x000, false, false, 0x000, 0x061}
+	CRC12Cdma2000      = Params{"CRC-12/CDMA2000", 12, 0xf13, 0xfff, false, false, 0x000, 0xd4d}
+	CRC12Dect          = Params{"CRC-12/DECT", 12, 0x80f, 0x000, false, false, 0x000, 0xf5b}
+	CRC12Gsm           = Params{"CRC-12/GSM", 12, 0xd31, 0x000, false, false, 0xfff, 0xb34}
+	CRC12Umts          = Params{"CRC-12/UMTS", 12, 0x80f, 0x000, false, true, 0x000, 0xdaf}
+	CRC13Bbc           = Params{"CRC-13/BBC", 13, 0x1cf5, 0x0000, false, false, 0x0000, 0x04fa}
+	CRC14Darc          = Params{"CRC-14/DARC", 14, 0x0805, 0x0000, true, true, 0x0000, 0x082d}
+	CRC14Gsm           = Params{"CRC-14/GSM", 14, 0x202d, 0x0000, false, false, 0x3fff, 0x30ae}
+	CRC15Can           = Params{"CRC-15/CAN", 15, 0x4599, 0x0000, false, false, 0x0000, 0x059e}
+	CRC15Mpt1327       = Params{"CRC-15/MPT1327", 15, 0x6815, 0x0000, false, false, 0x0001, 0x2566}
+	CRC16Arc           = Params{"CRC-16/ARC", 16, 0x8005, 0x0000, true, true, 0x0000, 0xbb3d}
+	CRC16Cdma2000      = Params{"CRC-16/CDMA2000", 16, 0xc867, 0xffff, false, false, 0x0000, 0x4c06}
+	CRC16Cms           = Params{"CRC-16/CMS", 16, 0x8005, 0xffff, false, false, 0x0000, 0xaee7}
+	CRC16Dds110        = Params{"CRC-16/DDS-110", 16, 0x8005, 0x800d, false, false, 0x0000, 0x9ecf}
+	CRC16DectR         = Params{"CRC-16/DECT-R", 16, 0x0589, 0x0000, false, false, 0x0001, 0x007e}
+	CRC16DectX         = Params{"CRC-16/DECT-X", 16, 0x0589, 0x0000, false, false, 0x0000, 0x007f}
+	CRC16Dnp           = Params{"CRC-16/DNP", 16, 0x3d65, 0x0000, true, true, 0xffff, 0xea82}
+	CRC16En13757       = Params{"CRC-16/EN-13757", 16, 0x3d65, 0x0000, false, false, 0xffff, 0xc2b7}
+	CRC16Genibus       = Params{"CRC-16/GENIBUS", 16, 0x1021, 0xffff, false, false, 0xffff, 0xd64e}
+	CRC16Gsm           = Params{"CRC-16/GSM", 16, 0x1021, 0x0000, false, false, 0xffff, 0xce3c}
+	CRC16Ibm3740       = Params{"CRC-16/IBM-3740", 16, 0x1021, 0xffff, false, false, 0x0000, 0x29b1}
+	CRC16IbmSdlc       = Params{"CRC-16/IBM-SDLC", 16, 0x1021, 0xffff, true, true, 0xffff, 0x906e}
+	CRC16IsoIec144433A = Params{"CRC-16/ISO-IEC-14443-3-A", 16, 0x1021, 0xc6c6, true, true, 0x0000, 0xbf05}
+	CRC16Kermit        = Params{"CRC-16/KERMIT", 16, 0x1021, 0x0000, true, true, 0x0000, 0x2189}
+	CRC16Lj1200        = Params{"CRC-16/LJ1200", 16, 0x6f63, 0x0000, false, false, 0x0000, 0xbdf4}
+	CRC16M17           = Params{"CRC-16/M17", 16, 0x5935, 0xffff, false, false, 0x0000, 0x772b}
+	CRC16MaximDow      = Params{"CRC-16/MAXIM-DOW", 16, 0x8005, 0x0000, true, true, 0xffff, 0x44c2}
+	CRC16Mcrf4xx       = Params{"CRC-16/MCRF4XX", 16, 0x1021, 0xffff, true, true, 0x0000, 0x6f91}
+	CRC16Modbus        = Params{"CRC-16/MODBUS", 16, 0x8005, 0xffff, true, true, 0x0000, 0x4b37}
+	CRC16Nrsc5         = Params{"CRC-16/NRSC-5", 16, 0x080b, 0xffff, true, true, 0x0000, 0xa066}
+	CRC16OpensafetyA   = Params{"CRC-16/OPENSAFETY-A", 16, 0x5935, 0x0000, false, false, 0x0000, 0x5d38}
+	CRC16OpensafetyB   = Params{"CRC-16/OPENSAFETY-B", 16, 0x755b, 0x0000, false, false, 0x0000, 0x20fe}
+	CRC16Profibus      = Params{"CRC-16/PROFIBUS", 16, 0x1dcf, 0xffff, false, false, 0xffff, 0xa819}
+	CRC16Riello        = Params{"CRC-16/RIELLO", 16, 0x1021, 0xb2aa, true, true, 0x0000, 0x63d0}
+	CRC16SpiFujitsu    = Params{"CRC-16/SPI-FUJITSU", 16, 0x1021, 0x1d0f, false, false, 0x0000, 0xe5cc}
+	CRC16T10Dif        = Params{"CRC-16/T10-DIF", 16, 0x8bb7, 0x0000, false, false, 0x0000, 0xd0db}
+	CRC16Teledisk      = Params{"CRC-16/TELEDISK", 16, 0xa097, 0x0000, false, false, 0x0000, 0x0fb3}
+	CRC16Tms37157      = Params{"CRC-16/TMS37157", 16, 0x1021, 0x89ec, true, true, 0x0000, 0x26b1}
+	CRC16Umts          = Params{"CRC-16/UMTS", 16, 0x8005, 0x0000, false, false, 0x0000, 0xfee8}
+	CRC16Usb           = Params{"CRC-16/USB", 16, 0x8005, 0xffff, true, true, 0xffff, 0xb4c8}
+	CRC16Xmodem        = Params{"CRC-16/XMODEM", 16, 0x1021, 0x0000, false, false, 0x0000, 0x31c3}
+	CRC17CanFd         = Params{"CRC-17/CAN-FD", 17, 0x1685b, 0x00000, false, false, 0x00000, 0x04f03}
+	CRC21CanFd         = Params{"CRC-21/CAN-FD", 21, 0x102899, 0x000000, false, false, 0x000000, 0x0ed841}
+	CRC24Ble           = Params{"CRC-24/BLE", 24, 0x00065b, 0x555555, true, true, 0x000000, 0xc25a56}
+	CRC24FlexrayA      = Params{"CRC-24/FLEXRAY-A", 24, 0x5d6dcb, 0xfedcba, false, false, 0x000000, 0x7979bd}
+	CRC24FlexrayB      = Params{"CRC-24/FLEXRAY-B", 24, 0x5d6dcb, 0xabcdef, false, false, 0x000000, 0x1f23b8}
+	CRC24Interlaken    = Params{"CRC-24/INTERLAKEN", 24, 0x328b63, 0xffffff, false, false, 0xffffff, 0xb4f3e6}
+	CRC24LteA          = Params{"CRC-24/LTE-A", 24, 0x864cfb, 0x000000, false, false, 0x000000, 0xcde703}
+	CRC24LteB          = Params{"CRC-24/LTE-B", 24, 0x800063, 0x000000, false, false, 0x000000, 0x23ef52}
+	CRC24Openpgp       = Params{"CRC-24/OPENPGP", 24, 0x864cfb, 0xb704ce, false, false, 0x000000, 0x21cf02}
+	CRC24Os9           = Params{"CRC-24/OS-9", 24, 0x800063, 0xffffff, false, false, 0xffffff, 0x200fa5}
+	CRC30Cdma          = Params{"CRC-30/CDMA", 30, 0x2030b9c7, 0x3fffffff, false, false, 0x3fffffff, 0x04c34abf}
+	CRC31Philips       = Params{"CRC-31/PHILIPS", 31, 0x04c11db7, 0x7fffffff, false, false, 0x7fffffff, 0x0ce9e46c}
+	CRC32Aixm          = Params{"CRC-32/AIXM", 32, 0x814141ab, 0x00000000, false, false, 0x00000000, 0x3010bf7f}
+	CRC32Autosar       = Params{"CRC-32/AUTOSAR", 32, 0xf4acfb13, 0xffffffff, true, true, 0xffffffff, 0x1697d06a}
+	CRC32Base91D       = Params{"CRC-32/BASE91-D", 32, 0xa833982b, 0xffffffff, true, true, 0xffffffff, 0x87315576}
+	CRC32Bzip2         = Params{"CRC-32/BZIP2", 32, 0x04c11db7, 0xffffffff, false, false, 0xffffffff, 0xfc891918}
+	CRC32CdRomEdc      = Params{"CRC-32/CD-ROM-EDC", 32, 0x8001801b, 0x00000000, true, true, 0x00000000, 0x6ec2edc4}
+	CRC32Cksum         = Params{"CRC-32/CKSUM", 32, 0x04c11db7, 0x00000000, false, false, 0xffffffff, 0x765e7680}
+	CRC32Iscsi         = Params{"CRC-32/ISCSI", 32, 0x1edc6f41, 0xffffffff, true, true, 0xffffffff, 0xe3069283}
+	CRC32IsoHdlc       = Params{"CRC-32/ISO-HDLC", 32, 0x04c11db7, 0xffffffff, true, true, 0xffffffff, 0xcbf43926}
+	CRC32Jamcrc        = Params{"CRC-32/JAMCRC", 32, 0x04c11db7, 0xffffffff, true, true, 0x00000000, 0x340bc6d9}
+	CRC32Mef           = Params{"CRC-32/MEF", 32, 0x741b8cd7, 0xffffffff, true, true, 0x00000000, 0xd2c22f51}
+	CRC32Mpeg2         = Params{"CRC-32/MPEG-2", 32, 0x04c11db7, 0xffffffff, false, false, 0x00000000, 0x0376e6e7}
+	CRC32Xfer          = Params{"CRC-32/XFER", 32, 0x000000af, 0x00000000, false, false, 0x00000000, 0xbd0be338}
+	CRC40Gsm           = Params{"CRC-40/GSM", 40, 0x0004820009, 0x0000000000, false, false, 0xffffffffff, 0xd4164fc646}
+	CRC64Ecma182       = Params{"CRC-64/ECMA-182", 64, 0x42f0e1eba9ea3693, 0x0000000000000000, false, false, 0x0000000000000000, 0x6c40df5f0b497347}
+	CRC64GoIso         = Params{"CRC-64/GO-ISO", 64, 0x000000000000001b, 0xffffffffffffffff, true, true, 0xffffffffffffffff, 0xb90956c775a41001}
+	CRC64Ms            = Params{"CRC-64/MS", 64, 0x259c84cba6426349, 0xffffffffffffffff, true, true, 0x0000000000000000, 0x75d4b74f024eceea}
+	CRC64Redis         = Params{"CRC-64/REDIS", 64, 0xad93d23594c935a9, 0x0000000000000000, true, true, 0x0000000000000000, 0xe9c6d914c4b8d9ca}
+	CRC64We            = Params{"CRC-64/WE", 64, 0x42f0e1eba9ea3693, 0xffffffffffffffff, false, false, 0xffffffffffffffff, 0x62ec59e3f1a4f00a}
+	CRC64Xz            = Params{"CRC-64/XZ", 64, 0x42f0e1eba9ea3693, 0xffffffffffffffff, true, true, 0xffffffffffffffff, 0x995dc9bbdf1939fa}
+)
+
+// Common names for catalogue entries
+var (
+	CRC32      = CRC32IsoHdlc //IEEE 802.3, zip, png (hash/crc32.IEEE)
+	CRC32C     = CRC32Iscsi   //Castagnoli (hash/crc32.Castagnoli)
+	CRC16CCITT = CRC16Kermit  //As named by reveng, some use this for CRC16IBM3740
+	CRC64      = CRC64Ecma182
+)
+
+// Every catalogue entry, ordered by width then name
+var Catalogue = []Params{
+	CRC3Gsm,
+	CRC3Rohc,
+	CRC4G704,
+	CRC4Interlaken,
+	CRC5EpcC1g2,
+	CRC5G704,
+	CRC5Usb,
+	CRC6Cdma2000A,
+	CRC6Cdma2000B,
+	CRC6Darc,
+	CRC6G704,
+	CRC6Gsm,
+	CRC7Mmc,
+	CRC7Rohc,
+	CRC7Umts,
+	CRC8Autosar,
+	CRC8Bluetooth,
+	CRC8Cdma2000,
+	CRC8Darc,
+	CRC8DvbS2,
+	CRC8GsmA,
+	CRC8GsmB,
+	CRC8Hitag,
+	CRC8I4321,
+	CRC8ICode,
+	CRC8Lte,
+	CRC8MaximDow,
+	CRC8MifareMad,
+	CRC8Nrsc5,
+	CRC8Opensafety,
+	CRC8Rohc,
+	CRC8SaeJ1850,
+	CRC8Smbus,
+	CRC8Tech3250,
+	CRC8Wcdma,
+	CRC10Atm,
+	CRC10Cdma2000,
+	CRC10Gsm,
+	CRC11Flexray,
+	CRC11Umts,
+	CRC12Cdma2000,
+	CRC12Dect,
+	CRC12Gsm,
+	CRC12Umts,
+	CRC13Bbc,
+	CRC14Darc,
+	CRC14Gsm,
+	CRC15Can,
+	CRC15Mpt1327,
+	CRC16Arc,
+	CRC16Cdma2000,
+	CRC16Cms,
+	CRC16Dds110,
+	CRC16DectR,
+	CRC16DectX,
+	CRC16Dnp,
+	CRC16En13757,
+	CRC16Genibus,
+	CRC16Gsm,
+	CRC16Ibm3740,
+	CRC16IbmSdlc,
+	CRC16IsoIec144433A,
+	CRC16Kermit,
+	CRC16Lj1200,
+	CRC16M17,
+	CRC16MaximDow,
+	CRC16Mcrf4xx,
+	CRC16Modbus,
+	CRC16Nrsc5,
+	CRC16OpensafetyA,
+	CRC16OpensafetyB,
+	CRC16Profibus,
+	CRC16Riello,
+	CRC16SpiFujitsu,
+	CRC16T10Dif,
+	CRC16Teledisk,
+	CRC16Tms37157,
+	CRC16Umts,
+	CRC16Usb,
+	CRC16Xmodem,
+	CRC17CanFd,
+	CRC21CanFd,
+	CRC24Ble,
+	CRC24FlexrayA,
+	CRC24FlexrayB,
+	CRC24Interlaken,
+	CRC24LteA,
+	CRC24LteB,
+	CRC24Openpgp,
+	CRC24Os9,
+	CRC30Cdma,
+	CRC31Philips,
+	CRC32Aixm,
+	CRC32Autosar,
+	CRC32Base91D,
+	CRC32Bzip2,
+	CRC32CdRomEdc,
+	CRC32Cksum,
+	CRC32Iscsi,
+	CRC32IsoHdlc,
+	CRC32Jamcrc,
+	CRC32Mef,
+	CRC32Mpeg2,
+	CRC32Xfer,
+	CRC40Gsm,
+	CRC64Ecma182,
+	CRC64GoIso,
+	CRC64Ms,
+	CRC64Redis,
+	CRC64We,
+	CRC64Xz,
+}
+
+// Alternate names (upper case) mapped to catalogue names
+var aliases = map[string]string{
+	"ARC":                "CRC-16/ARC",
+	"B-CRC-32":           "CRC-32/BZIP2",
+	"CKSUM":              "CRC-32/CKSUM",
+	"CRC-16":             "CRC-16/ARC",
+	"CRC-16/ACORN":       "CRC-16/XMODEM",
+	"CRC-16/AUG-CCITT":   "CRC-16/SPI-FUJITSU",
+	"CRC-16/AUTOSAR":     "CRC-16/IBM-3740",
+	"CRC-16/BUYPASS":     "CRC-16/UMTS",
+	"CRC-16/CCITT":       "CRC-16/KERMIT",
+	"CRC-16/CCITT-FALSE": "CRC-16/IBM-3740",
+	"CRC-16/DARC":        "CRC-16/GENIBUS",
+	"CRC-16/EPC":         "CRC-16/GENIBUS",
+	"CRC-16/I-CODE":      "CRC-16/GENIBUS",
+	"CRC-16/IEC-61158-2": "CRC-16/PROFIBUS",
+	"CRC-16/ISO-HDLC":    "CRC-16/IBM-SDLC",
+	"CRC-16/LTE":         "CRC-16/XMODEM",
+	"CRC-16/MAXIM":       "CRC-16/MAXIM-DOW",
+	"CRC-16/V-41-LSB":    "CRC-16/KERMIT",
+	"CRC-16/V-41-MSB":    "CRC-16/XMODEM",
+	"CRC-16/VERIFONE":    "CRC-16/UMTS",
+	"CRC-16/X-25":        "CRC-16/IBM-SDLC",
+	"CRC-24":             "CRC-24/OPENPGP",
+	"CRC-32":             "CRC-32/ISO-HDLC",
+	"CRC-32/AAL5":        "CRC-32/BZIP2",
+	"CRC-32/ADCCP":       "CRC-32/ISO-HDLC",
+	"CRC-32/BASE91-C":    "CRC-32/ISCSI",
+	"CRC-32/CASTAGNOLI":  "CRC-32/ISCSI",
+	"CRC-32/DECT-B":      "CRC-32/BZIP2",
+	"CRC-32/INTERLAKEN":  "CRC-32/ISCSI",
+	"CRC-32/POSIX":       "CRC-32/CKSUM",
+	"CRC-32/V-42":        "CRC-32/ISO-HDLC",
+	"CRC-32/XZ":          "CRC-32/ISO-HDLC",
+	"CRC-32C":            "CRC-32/ISCSI",
+	"CRC-32Q":            "CRC-32/AIXM",
+	"CRC-64":             "CRC-64/ECMA-182",
+	"CRC-64/GO-ECMA":     "CRC-64/XZ",
+	"CRC-8":              "CRC-8/SMBUS",
+	"CRC-8/EBU":          "CRC-8/TECH-3250",
+	"CRC-8/ITU":          "CRC-8/I-432-1",
+	"CRC-8/MAXIM":        "CRC-8/MAXIM-DOW",
+	"CRC-A":              "CRC-16/ISO-IEC-14443-3-A",
+	"DOW-CRC":            "CRC-8/MAXIM-DOW",
+	"JAMCRC":             "CRC-32/JAMCRC",
+	"KERMIT":             "CRC-16/KERMIT",
+	"MODBUS":             "CRC-16/MODBUS",
+	"PKZIP":              "CRC-32/ISO-HDLC",
+	"X-25":               "CRC-16/IBM-SDLC",
+	"XFER":               "CRC-32/XFER",
+	"XMODEM":             "CRC-16/XMODEM",
+	"ZMODEM":             "CRC-16/XMODEM",
+}
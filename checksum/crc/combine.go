@@ -0,0 +1,106 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+package crc
+
+//https://github.com/madler/zlib/blob/master/crc32.c (crc32_combine)
+
+import "github.com/gnabgib/gnablib-go/checksum"
+
+// A linear operator on the (up to) 64bit register, column i is the result for bit i
+type gf2Matrix [64]uint64
+
+func (m *gf2Matrix) apply(v uint64) uint64 {
+	var r uint64
+	for i := 0; v != 0; i, v = i+1, v>>1 {
+		if v&1 == 1 {
+			r ^= m[i]
+		}
+	}
+	return r
+}
+
+// m*m
+func (m *gf2Matrix) square() *gf2Matrix {
+	sq := new(gf2Matrix)
+	for i := range m {
+		sq[i] = m.apply(m[i])
+	}
+	return sq
+}
+
+// Advance the register `reg` by `n` zero bytes in log(n) steps
+func (d *digest) zeros(reg uint64, n int64) uint64 {
+	//The single zero byte operator
+	op := new(gf2Matrix)
+	for i := range op {
+		if d.p.RefIn {
+			op[i] = d.tab.updateRef(uint64(1)<<i, []byte{0})
+		} else {
+			op[i] = d.tab.update(uint64(1)<<i, []byte{0})
+		}
+	}
+	for n > 0 {
+		if n&1 == 1 {
+			reg = op.apply(reg)
+		}
+		n >>= 1
+		if n > 0 {
+			op = op.square()
+		}
+	}
+	return reg
+}
+
+// The register that produces CRC `v` (the opposite of digest.sum)
+func (d *digest) register(v uint64) uint64 {
+	w := d.p.Width
+	v ^= d.p.XorOut
+	if d.p.RefIn {
+		if !d.p.RefOut {
+			v = reflect(v, w)
+		}
+		return v
+	}
+	if d.p.RefOut {
+		v = reflect(v, w)
+	}
+	return v << (64 - w)
+}
+
+// The initial register
+func (d *digest) initial() uint64 {
+	t := *d
+	t.Reset()
+	return t.reg
+}
+
+// A CRC is linear in its register, so: reg(A||B) = zeros(reg(A)^init, len(B))^reg(B)
+func (d *digest) combine(sumB uint64, lenB int64) {
+	d.reg = d.zeros(d.reg^d.initial(), lenB) ^ d.register(sumB)
+}
+
+// Update the state as if the `n` bytes summarized by `sum` had been written
+func (d *digest) Combine(sum []byte, n int64) error {
+	if len(sum) != d.p.Size() {
+		return checksum.ErrCombineSize
+	}
+	var s uint64
+	for _, b := range sum {
+		s = s<<8 | uint64(b)
+	}
+	d.combine(s, n)
+	return nil
+}
+
+// The CRC of A||B, from the CRC of A and B and the length of B in bytes, panics
+// if the params aren't valid
+func Combine(p Params, crcA, crcB uint64, lenB int64) uint64 {
+	d, err := newDigest(p, false)
+	if err != nil {
+		panic(err)
+	}
+	d.reg = d.register(crcA)
+	d.combine(crcB, lenB)
+	return d.sum()
+}
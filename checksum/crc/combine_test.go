@@ -0,0 +1,71 @@
+package crc
+
+import (
+	"bytes"
+	"hash"
+	"math/rand"
+	"testing"
+
+	"github.com/gnabgib/gnablib-go/checksum"
+)
+
+func TestCombine(t *testing.T) {
+	rnd := rand.New(rand.NewSource(0x636f6d))
+	data := make([]byte, 3000)
+	rnd.Read(data)
+	splits := []struct{ a, b int }{{0, 0}, {0, 9}, {9, 0}, {1, 1}, {5, 3}, {17, 1000}, {1000, 2000}}
+	for _, p := range Catalogue {
+		for _, s := range splits {
+			a, b := data[:s.a], data[s.a:s.a+s.b]
+			expect := p.Checksum(data[:s.a+s.b])
+			if found := Combine(p, p.Checksum(a), p.Checksum(b), int64(len(b))); found != expect {
+				t.Errorf("%s(%d,%d): expecting %x, got %x", p.Name, s.a, s.b, expect, found)
+			}
+		}
+	}
+}
+
+func TestCombiner(t *testing.T) {
+	data := []byte(checkInput)
+	for _, p := range Catalogue {
+		h, _ := New(p)
+		h.Write(data[:4])
+		hb, _ := NewTable(p)
+		hb.Write(data[4:])
+		c := h.(checksum.Combiner)
+		if err := c.Combine(hb.Sum(nil), 5); err != nil {
+			t.Errorf("%s: unexpected error %v", p.Name, err)
+		}
+		if found := sumOf(h); found != p.Check {
+			t.Errorf("%s: expecting %x, got %x", p.Name, p.Check, found)
+		}
+		//Writing can continue after a combine
+		h.Write(data)
+		if found, expect := sumOf(h), p.Checksum(append(data, data...)); found != expect {
+			t.Errorf("%s: write after combine expecting %x, got %x", p.Name, expect, found)
+		}
+		if err := c.Combine(make([]byte, p.Size()+1), 1); err != checksum.ErrCombineSize {
+			t.Errorf("%s: expecting ErrCombineSize, got %v", p.Name, err)
+		}
+	}
+}
+
+func TestParallel(t *testing.T) {
+	rnd := rand.New(rand.NewSource(0x706172))
+	data := make([]byte, 10007)
+	rnd.Read(data)
+	for _, p := range []Params{CRC8Smbus, CRC16Modbus, CRC24Openpgp, CRC32C, CRC40Gsm, CRC64Xz} {
+		p := p
+		expect := p.Checksum(data)
+		for _, chunk := range []int64{8, 1000, 4096, 100000} {
+			h, err := checksum.ParallelChunks(bytes.NewReader(data), int64(len(data)), chunk, func() hash.Hash { h, _ := New(p); return h })
+			if err != nil {
+				t.Errorf("%s(%d): unexpected error %v", p.Name, chunk, err)
+				continue
+			}
+			if found := sumOf(h); found != expect {
+				t.Errorf("%s(%d): expecting %x, got %x", p.Name, chunk, expect, found)
+			}
+		}
+	}
+}
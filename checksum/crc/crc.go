@@ -0,0 +1,269 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+// Parameterised cyclic redundancy checks using the Rocksoft model
+package crc
+
+//http://www.ross.net/crc/download/crc_v3.txt (A painless guide to CRC error detection algorithms)
+//https://reveng.sourceforge.io/crc-catalogue/all.htm
+//https://www.intel.com/content/dam/www/public/us/en/documents/white-papers/fast-crc-computation-paper.pdf (Slicing-by-8)
+
+import (
+	"errors"
+	"hash"
+	"math/bits"
+	"strings"
+
+	"github.com/gnabgib/gnablib-go/checksum"
+)
+
+const (
+	MinWidth = 3
+	MaxWidth = 64
+)
+
+// Width is outside MinWidth-MaxWidth
+var ErrWidth = errors.New("CRC width must be 3-64 bits")
+
+// Poly, Init or XorOut have bits set beyond the width
+var ErrParamWidth = errors.New("CRC parameter exceeds width")
+
+// The CRC width isn't supported by the requested return type
+var ErrReturnWidth = errors.New("CRC width doesn't fit the requested type")
+
+// A CRC described by the Rocksoft model
+type Params struct {
+	Name   string
+	Width  uint   //Width in bits (3-64)
+	Poly   uint64 //Polynomial, normal form (without the top bit)
+	Init   uint64 //Initial register value, unreflected
+	RefIn  bool   //Reflect each input byte
+	RefOut bool   //Reflect the register before XorOut
+	XorOut uint64 //Value xor'd with the output
+	Check  uint64 //CRC of the ASCII "123456789", zero when unknown
+}
+
+// Make sure the width is supported, and each parameter fits in it
+func (p Params) Validate() error {
+	if p.Width < MinWidth || p.Width > MaxWidth {
+		return ErrWidth
+	}
+	mask := p.mask()
+	if p.Poly&^mask != 0 || p.Init&^mask != 0 || p.XorOut&^mask != 0 {
+		return ErrParamWidth
+	}
+	return nil
+}
+
+func (p Params) mask() uint64 {
+	return ^uint64(0) >> (64 - p.Width)
+}
+
+// Number of bytes the CRC takes when written (by Sum)
+func (p Params) Size() int {
+	return int(p.Width+7) / 8
+}
+
+// Calculate the CRC of `data` in one call, panics if the params aren't valid
+func (p Params) Checksum(data []byte) uint64 {
+	d, err := newDigest(p, true)
+	if err != nil {
+		panic(err)
+	}
+	d.update(data)
+	return d.sum()
+}
+
+// Find a catalogue entry by name or common alias (case insensitive), false if
+// there's no match
+func Lookup(name string) (Params, bool) {
+	name = strings.ToUpper(name)
+	if alias, ok := aliases[name]; ok {
+		name = alias
+	}
+	for _, p := range Catalogue {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Params{}, false
+}
+
+// Rocksoft model CRC -- --
+type digest struct {
+	p      Params
+	tab    *table
+	slices *tables //Slicing-by-8 tables, nil when only using tab
+	reg    uint64  //Reflected and right aligned when RefIn, otherwise left aligned
+}
+
+func newDigest(p Params, slice bool) (*digest, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	d := &digest{p: p}
+	if slice {
+		d.slices = tablesFor(p)
+		d.tab = &d.slices[0]
+	} else {
+		d.tab = tableFor(p)
+	}
+	d.Reset()
+	return d, nil
+}
+
+// wrap the digest in the type appropriate for its width
+func (d *digest) typed() hash.Hash {
+	switch {
+	case d.p.Width <= 8:
+		return &digest8{d}
+	case d.p.Width <= 16:
+		return &digest16{d}
+	case d.p.Width <= 32:
+		return &digest32{d}
+	}
+	return &digest64{d}
+}
+
+// A new CRC using slicing-by-8. Depending on the width this returns a
+// checksum.Hash8 (3-8), checksum.Hash16 (9-16), hash.Hash32 (17-32) or hash.Hash64
+func New(p Params) (hash.Hash, error) {
+	d, err := newDigest(p, true)
+	if err != nil {
+		return nil, err
+	}
+	return d.typed(), nil
+}
+
+// A new CRC using a single 256 entry table (less memory, slower than New).
+// The return type depends on the width, as with New
+func NewTable(p Params) (hash.Hash, error) {
+	d, err := newDigest(p, false)
+	if err != nil {
+		return nil, err
+	}
+	return d.typed(), nil
+}
+
+// A new CRC of width 3-8 using slicing-by-8
+func New8(p Params) (checksum.Hash8, error) {
+	h, err := newTyped(p, MinWidth, 8)
+	if err != nil {
+		return nil, err
+	}
+	return h.(checksum.Hash8), nil
+}
+
+// A new CRC of width 9-16 using slicing-by-8
+func New16(p Params) (checksum.Hash16, error) {
+	h, err := newTyped(p, 9, 16)
+	if err != nil {
+		return nil, err
+	}
+	return h.(checksum.Hash16), nil
+}
+
+// A new CRC of width 17-32 using slicing-by-8
+func New32(p Params) (hash.Hash32, error) {
+	h, err := newTyped(p, 17, 32)
+	if err != nil {
+		return nil, err
+	}
+	return h.(hash.Hash32), nil
+}
+
+// A new CRC of width 33-64 using slicing-by-8
+func New64(p Params) (hash.Hash64, error) {
+	h, err := newTyped(p, 33, 64)
+	if err != nil {
+		return nil, err
+	}
+	return h.(hash.Hash64), nil
+}
+
+func newTyped(p Params, minWidth, maxWidth uint) (hash.Hash, error) {
+	if p.Width < minWidth || p.Width > maxWidth {
+		return nil, ErrReturnWidth
+	}
+	return New(p)
+}
+
+func (d *digest) update(p []byte) {
+	if d.p.RefIn {
+		if d.slices != nil {
+			d.reg = d.slices.updateRef(d.reg, p)
+		} else {
+			d.reg = d.tab.updateRef(d.reg, p)
+		}
+	} else {
+		if d.slices != nil {
+			d.reg = d.slices.update(d.reg, p)
+		} else {
+			d.reg = d.tab.update(d.reg, p)
+		}
+	}
+}
+
+// The CRC value (without changing state)
+func (d *digest) sum() uint64 {
+	w := d.p.Width
+	v := d.reg
+	if d.p.RefIn {
+		//The register is already reflected
+		if !d.p.RefOut {
+			v = reflect(v, w)
+		}
+	} else {
+		v >>= 64 - w
+		if d.p.RefOut {
+			v = reflect(v, w)
+		}
+	}
+	return v ^ d.p.XorOut
+}
+
+func (d *digest) Write(p []byte) (n int, err error) {
+	d.update(p)
+	return len(p), nil
+}
+
+func (d *digest) Sum(in []byte) []byte {
+	s := d.sum()
+	for i := d.p.Size() - 1; i >= 0; i-- {
+		in = append(in, byte(s>>(8*i)))
+	}
+	return in
+}
+
+func (d *digest) Reset() {
+	if d.p.RefIn {
+		d.reg = reflect(d.p.Init, d.p.Width)
+	} else {
+		d.reg = d.p.Init << (64 - d.p.Width)
+	}
+}
+
+func (d *digest) Size() int { return d.p.Size() }
+
+func (d *digest) BlockSize() int { return 1 }
+
+type digest8 struct{ *digest }
+
+func (d *digest8) Sum8() uint8 { return uint8(d.sum()) }
+
+type digest16 struct{ *digest }
+
+func (d *digest16) Sum16() uint16 { return uint16(d.sum()) }
+
+type digest32 struct{ *digest }
+
+func (d *digest32) Sum32() uint32 { return uint32(d.sum()) }
+
+type digest64 struct{ *digest }
+
+func (d *digest64) Sum64() uint64 { return d.sum() }
+
+// Reverse the bottom `w` bits of `v`
+func reflect(v uint64, w uint) uint64 {
+	return bits.Reverse64(v) >> (64 - w)
+}
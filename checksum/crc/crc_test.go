@@ -0,0 +1,283 @@
+package crc
+
+import (
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"hash/crc64"
+	"math/rand"
+	"testing"
+
+	"github.com/gnabgib/gnablib-go/checksum"
+	"github.com/gnabgib/gnablib-go/test"
+)
+
+const checkInput = "123456789"
+
+// Bit at a time Rocksoft model, straight from the guide (slow, but obviously right)
+func bitwise(p Params, data []byte) uint64 {
+	top := uint64(1) << (p.Width - 1)
+	mask := p.mask()
+	reg := p.Init
+	for _, b := range data {
+		if p.RefIn {
+			b = byte(reflect(uint64(b), 8))
+		}
+		for i := 7; i >= 0; i-- {
+			bit := (uint64(b>>i)&1 == 1) != (reg&top != 0)
+			reg = reg << 1 & mask
+			if bit {
+				reg ^= p.Poly
+			}
+		}
+	}
+	if p.RefOut {
+		reg = reflect(reg, p.Width)
+	}
+	return reg ^ p.XorOut
+}
+
+// The numeric sum from whichever type New returned
+func sumOf(h hash.Hash) uint64 {
+	switch v := h.(type) {
+	case checksum.Hash8:
+		return uint64(v.Sum8())
+	case checksum.Hash16:
+		return uint64(v.Sum16())
+	case hash.Hash32:
+		return uint64(v.Sum32())
+	case hash.Hash64:
+		return v.Sum64()
+	}
+	panic("unknown type")
+}
+
+func checkHex(p Params) string {
+	return fmt.Sprintf("%0*X", p.Size()*2, p.Check)
+}
+
+func TestCatalogue_check(t *testing.T) {
+	for _, p := range Catalogue {
+		if err := p.Validate(); err != nil {
+			t.Errorf("%s: unexpected error %v", p.Name, err)
+			continue
+		}
+		if found := bitwise(p, []byte(checkInput)); found != p.Check {
+			t.Errorf("%s: bitwise expecting %x, got %x", p.Name, p.Check, found)
+		}
+		if found := p.Checksum([]byte(checkInput)); found != p.Check {
+			t.Errorf("%s: expecting %x, got %x", p.Name, p.Check, found)
+		}
+	}
+}
+
+func TestCatalogue_conformance(t *testing.T) {
+	for _, p := range Catalogue {
+		p := p
+		vectors := []test.HashVector{{In: checkInput, Hex: checkHex(p)}}
+		test.HashConformance(t, func() hash.Hash { h, _ := New(p); return h }, vectors)
+		test.HashConformance(t, func() hash.Hash { h, _ := NewTable(p); return h }, vectors)
+	}
+}
+
+func TestSliceMatchesBitwise(t *testing.T) {
+	//Long enough to exercise the 8 byte path with every tail length
+	rnd := rand.New(rand.NewSource(0x637263))
+	data := make([]byte, 100)
+	rnd.Read(data)
+	for _, p := range Catalogue {
+		for n := 0; n <= len(data); n += 7 {
+			expect := bitwise(p, data[:n])
+			h, _ := New(p)
+			h.Write(data[:n])
+			ht, _ := NewTable(p)
+			ht.Write(data[:n])
+			if found := sumOf(h); found != expect {
+				t.Errorf("%s(%d bytes): slicing expecting %x, got %x", p.Name, n, expect, found)
+			}
+			if found := sumOf(ht); found != expect {
+				t.Errorf("%s(%d bytes): table expecting %x, got %x", p.Name, n, expect, found)
+			}
+		}
+	}
+}
+
+func TestNewTable_single(t *testing.T) {
+	//A poly no catalogue entry shares, so the caches start empty for it
+	p := Params{Name: "CRC-12/TEST", Width: 12, Poly: 0x5a3}
+	ht, _ := NewTable(p)
+	ht.Write([]byte("123456789"))
+	if _, ok := slicedCache.Load(keyFor(p)); ok {
+		t.Errorf("NewTable built slicing tables")
+	}
+	h, _ := New(p)
+	h.Write([]byte("123456789"))
+	if _, ok := slicedCache.Load(keyFor(p)); !ok {
+		t.Errorf("New didn't build slicing tables")
+	}
+	if sumOf(h) != sumOf(ht) {
+		t.Errorf("Expecting %x, got %x", sumOf(h), sumOf(ht))
+	}
+}
+
+func TestNew_type(t *testing.T) {
+	for _, p := range Catalogue {
+		h, _ := New(p)
+		var ok bool
+		switch {
+		case p.Width <= 8:
+			_, ok = h.(checksum.Hash8)
+		case p.Width <= 16:
+			_, ok = h.(checksum.Hash16)
+		case p.Width <= 32:
+			_, ok = h.(hash.Hash32)
+		default:
+			_, ok = h.(hash.Hash64)
+		}
+		if !ok {
+			t.Errorf("%s: unexpected type %T", p.Name, h)
+		}
+	}
+}
+
+func TestNewTyped(t *testing.T) {
+	if h, err := New8(CRC8Smbus); err != nil || h.Sum8() != 0 {
+		t.Errorf("New8 expecting empty sum, got %v", err)
+	}
+	h16, err := New16(CRC16Modbus)
+	if err != nil {
+		t.Fatalf("New16 unexpected error %v", err)
+	}
+	h16.Write([]byte(checkInput))
+	if h16.Sum16() != 0x4b37 {
+		t.Errorf("New16 expecting 4b37, got %x", h16.Sum16())
+	}
+	h32, _ := New32(CRC32C)
+	h32.Write([]byte(checkInput))
+	if h32.Sum32() != 0xe3069283 {
+		t.Errorf("New32 expecting e3069283, got %x", h32.Sum32())
+	}
+	h64, _ := New64(CRC64Xz)
+	h64.Write([]byte(checkInput))
+	if h64.Sum64() != 0x995dc9bbdf1939fa {
+		t.Errorf("New64 expecting 995dc9bbdf1939fa, got %x", h64.Sum64())
+	}
+
+	//Each constructor only accepts its own widths
+	if _, err := New8(CRC16Modbus); err != ErrReturnWidth {
+		t.Errorf("New8 expecting ErrReturnWidth, got %v", err)
+	}
+	if _, err := New16(CRC8Smbus); err != ErrReturnWidth {
+		t.Errorf("New16 expecting ErrReturnWidth, got %v", err)
+	}
+	if _, err := New32(CRC64Xz); err != ErrReturnWidth {
+		t.Errorf("New32 expecting ErrReturnWidth, got %v", err)
+	}
+	if _, err := New64(CRC32); err != ErrReturnWidth {
+		t.Errorf("New64 expecting ErrReturnWidth, got %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		p   Params
+		err error
+	}{
+		{Params{Width: 2, Poly: 0x3}, ErrWidth},
+		{Params{Width: 65, Poly: 0x3}, ErrWidth},
+		{Params{Width: 8, Poly: 0x107}, ErrParamWidth},
+		{Params{Width: 8, Poly: 0x07, Init: 0x100}, ErrParamWidth},
+		{Params{Width: 8, Poly: 0x07, XorOut: 0x1ff}, ErrParamWidth},
+		{Params{Width: 64, Poly: 0x1b, Init: ^uint64(0)}, nil},
+	}
+	for _, rec := range tests {
+		if err := rec.p.Validate(); err != rec.err {
+			t.Errorf("%+v: expecting %v, got %v", rec.p, rec.err, err)
+		}
+		if rec.err == nil {
+			continue
+		}
+		if _, err := New(rec.p); err != rec.err {
+			t.Errorf("New(%+v): expecting %v, got %v", rec.p, rec.err, err)
+		}
+		if _, err := NewTable(rec.p); err != rec.err {
+			t.Errorf("NewTable(%+v): expecting %v, got %v", rec.p, rec.err, err)
+		}
+	}
+}
+
+// The standard library implements a few of these, they should agree
+func TestStdlib(t *testing.T) {
+	rnd := rand.New(rand.NewSource(0x737464))
+	data := make([]byte, 1000)
+	rnd.Read(data)
+	tests := []struct {
+		p      Params
+		expect uint64
+	}{
+		{CRC32IsoHdlc, uint64(crc32.ChecksumIEEE(data))},
+		{CRC32Iscsi, uint64(crc32.Checksum(data, crc32.MakeTable(crc32.Castagnoli)))},
+		{CRC32Mef, uint64(crc32.Checksum(data, crc32.MakeTable(crc32.Koopman))) ^ 0xffffffff},
+		{CRC64GoIso, crc64.Checksum(data, crc64.MakeTable(crc64.ISO))},
+		{CRC64Xz, crc64.Checksum(data, crc64.MakeTable(crc64.ECMA))},
+	}
+	for _, rec := range tests {
+		if found := rec.p.Checksum(data); found != rec.expect {
+			t.Errorf("%s: expecting %x, got %x", rec.p.Name, rec.expect, found)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name   string
+		expect Params
+		ok     bool
+	}{
+		{"CRC-16/MODBUS", CRC16Modbus, true},
+		{"crc-16/modbus", CRC16Modbus, true},
+		{"CRC-32C", CRC32C, true},
+		{"CRC-16/CCITT-FALSE", CRC16Ibm3740, true},
+		{"x-25", CRC16IbmSdlc, true},
+		{"CRC-8", CRC8Smbus, true},
+		{"CRC-64/XZ", CRC64Xz, true},
+		{"CRC-99/NOPE", Params{}, false},
+	}
+	for _, rec := range tests {
+		if found, ok := Lookup(rec.name); found != rec.expect || ok != rec.ok {
+			t.Errorf("%s: expecting %v %v, got %v %v", rec.name, rec.expect, rec.ok, found, ok)
+		}
+	}
+	//Every alias should resolve
+	for alias, name := range aliases {
+		if _, ok := Lookup(alias); !ok {
+			t.Errorf("Alias %s: %s isn't in the catalogue", alias, name)
+		}
+	}
+	//Names should be unique
+	seen := map[string]bool{}
+	for _, p := range Catalogue {
+		if seen[p.Name] {
+			t.Errorf("Duplicate name %s", p.Name)
+		}
+		seen[p.Name] = true
+	}
+}
+
+func BenchmarkTable(b *testing.B) {
+	data := make([]byte, 4096)
+	h, _ := NewTable(CRC32C)
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		h.Write(data)
+	}
+}
+
+func BenchmarkSlicing8(b *testing.B) {
+	data := make([]byte, 4096)
+	h, _ := New(CRC32C)
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		h.Write(data)
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+package crc
+
+import (
+	"encoding/binary"
+	"sync"
+)
+
+// A byte-at-a-time table
+type table [256]uint64
+
+// Slicing-by-8 tables, [0] is the regular byte-at-a-time table, [k] advances
+// [k-1] by a further zero byte
+type tables [8]table
+
+type tableKey struct {
+	width uint
+	poly  uint64
+	ref   bool
+}
+
+// Tables only depend on width, poly and reflection so many params can share them
+var (
+	tableCache  sync.Map //tableKey -> *table
+	slicedCache sync.Map //tableKey -> *tables
+)
+
+func keyFor(p Params) tableKey {
+	return tableKey{p.Width, p.Poly, p.RefIn}
+}
+
+// The byte-at-a-time table for `p`, only the 2KiB single table is built
+func tableFor(p Params) *table {
+	key := keyFor(p)
+	if t, ok := tableCache.Load(key); ok {
+		return t.(*table)
+	}
+	//If the slicing tables exist, the first is the one we need
+	if t, ok := slicedCache.Load(key); ok {
+		return &t.(*tables)[0]
+	}
+	t := new(table)
+	if p.RefIn {
+		t.buildRef(reflect(p.Poly, p.Width))
+	} else {
+		t.build(p.Poly << (64 - p.Width))
+	}
+	actual, _ := tableCache.LoadOrStore(key, t)
+	return actual.(*table)
+}
+
+// The slicing-by-8 tables for `p` (16KiB)
+func tablesFor(p Params) *tables {
+	key := keyFor(p)
+	if t, ok := slicedCache.Load(key); ok {
+		return t.(*tables)
+	}
+	t := new(tables)
+	t[0] = *tableFor(p)
+	if p.RefIn {
+		t.extendRef()
+	} else {
+		t.extend()
+	}
+	actual, _ := slicedCache.LoadOrStore(key, t)
+	return actual.(*tables)
+}
+
+// Build an MSB-first table for a left aligned poly (any width fits in the top
+// of a 64bit register, the unused low bits stay zero)
+func (t *table) build(poly uint64) {
+	for i := 0; i < 256; i++ {
+		c := uint64(i) << 56
+		for j := 0; j < 8; j++ {
+			if c&(1<<63) != 0 {
+				c = c<<1 ^ poly
+			} else {
+				c <<= 1
+			}
+		}
+		t[i] = c
+	}
+}
+
+// Fill the MSB-first slicing tables from t[0]
+func (t *tables) extend() {
+	for k := 1; k < 8; k++ {
+		for i := 0; i < 256; i++ {
+			c := t[k-1][i]
+			t[k][i] = c<<8 ^ t[0][c>>56]
+		}
+	}
+}
+
+// Build an LSB-first table for a reflected (right aligned) poly
+func (t *table) buildRef(poly uint64) {
+	for i := 0; i < 256; i++ {
+		c := uint64(i)
+		for j := 0; j < 8; j++ {
+			if c&1 != 0 {
+				c = c>>1 ^ poly
+			} else {
+				c >>= 1
+			}
+		}
+		t[i] = c
+	}
+}
+
+// Fill the LSB-first slicing tables from t[0]
+func (t *tables) extendRef() {
+	for k := 1; k < 8; k++ {
+		for i := 0; i < 256; i++ {
+			c := t[k-1][i]
+			t[k][i] = c>>8 ^ t[0][c&0xff]
+		}
+	}
+}
+
+func (t *table) update(reg uint64, p []byte) uint64 {
+	for _, b := range p {
+		reg = reg<<8 ^ t[byte(reg>>56)^b]
+	}
+	return reg
+}
+
+func (t *tables) update(reg uint64, p []byte) uint64 {
+	for len(p) >= 8 {
+		reg ^= binary.BigEndian.Uint64(p)
+		reg = t[7][reg>>56] ^ t[6][byte(reg>>48)] ^
+			t[5][byte(reg>>40)] ^ t[4][byte(reg>>32)] ^
+			t[3][byte(reg>>24)] ^ t[2][byte(reg>>16)] ^
+			t[1][byte(reg>>8)] ^ t[0][byte(reg)]
+		p = p[8:]
+	}
+	return t[0].update(reg, p)
+}
+
+func (t *table) updateRef(reg uint64, p []byte) uint64 {
+	for _, b := range p {
+		reg = reg>>8 ^ t[byte(reg)^b]
+	}
+	return reg
+}
+
+func (t *tables) updateRef(reg uint64, p []byte) uint64 {
+	for len(p) >= 8 {
+		reg ^= binary.LittleEndian.Uint64(p)
+		reg = t[7][byte(reg)] ^ t[6][byte(reg>>8)] ^
+			t[5][byte(reg>>16)] ^ t[4][byte(reg>>24)] ^
+			t[3][byte(reg>>32)] ^ t[2][byte(reg>>40)] ^
+			t[1][byte(reg>>48)] ^ t[0][reg>>56]
+		p = p[8:]
+	}
+	return t[0].updateRef(reg, p)
+}
@@ -0,0 +1,233 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+// Damm check digit, detects all single symbol errors and adjacent transpositions
+package damm
+
+// https://en.wikipedia.org/wiki/Damm_algorithm
+// https://archiv.ub.uni-marburg.de/diss/z2004/0516/pdf/dhmd.pdf (H. Michael Damm, 2004)
+
+import (
+	"errors"
+
+	"github.com/gnabgib/gnablib-go/checksum"
+)
+
+// Too few symbols to generate (1) or validate (2) a check symbol
+var ErrTooShort = errors.New("Too few symbols for Damm")
+
+// The check symbol doesn't match the content
+var ErrCheckFailed = errors.New("Damm check failed")
+
+// The table isn't a weakly totally anti-symmetric quasigroup with a zero
+// diagonal, or doesn't match the alphabet
+var ErrTable = errors.New("Invalid Damm table")
+
+// Damm's order 10 quasigroup
+var table10 = [][]uint8{
+	{0, 3, 1, 7, 5, 9, 8, 6, 4, 2},
+	{7, 0, 9, 2, 1, 5, 4, 8, 6, 3},
+	{4, 2, 0, 6, 8, 7, 1, 3, 5, 9},
+	{1, 7, 5, 0, 9, 8, 3, 4, 2, 6},
+	{6, 1, 2, 3, 0, 4, 5, 9, 7, 8},
+	{3, 6, 7, 4, 2, 0, 9, 5, 8, 1},
+	{5, 8, 6, 9, 7, 2, 0, 1, 3, 4},
+	{8, 9, 4, 5, 3, 6, 2, 0, 1, 7},
+	{9, 4, 3, 8, 6, 1, 7, 2, 0, 5},
+	{2, 5, 8, 1, 4, 3, 6, 7, 9, 0},
+}
+
+// An order 16 quasigroup: x∘y = 2(x⊕y) in GF(2^4) (with x^4+x+1)
+var table16 = [][]uint8{
+	{0, 2, 4, 6, 8, 10, 12, 14, 3, 1, 7, 5, 11, 9, 15, 13},
+	{2, 0, 6, 4, 10, 8, 14, 12, 1, 3, 5, 7, 9, 11, 13, 15},
+	{4, 6, 0, 2, 12, 14, 8, 10, 7, 5, 3, 1, 15, 13, 11, 9},
+	{6, 4, 2, 0, 14, 12, 10, 8, 5, 7, 1, 3, 13, 15, 9, 11},
+	{8, 10, 12, 14, 0, 2, 4, 6, 11, 9, 15, 13, 3, 1, 7, 5},
+	{10, 8, 14, 12, 2, 0, 6, 4, 9, 11, 13, 15, 1, 3, 5, 7},
+	{12, 14, 8, 10, 4, 6, 0, 2, 15, 13, 11, 9, 7, 5, 3, 1},
+	{14, 12, 10, 8, 6, 4, 2, 0, 13, 15, 9, 11, 5, 7, 1, 3},
+	{3, 1, 7, 5, 11, 9, 15, 13, 0, 2, 4, 6, 8, 10, 12, 14},
+	{1, 3, 5, 7, 9, 11, 13, 15, 2, 0, 6, 4, 10, 8, 14, 12},
+	{7, 5, 3, 1, 15, 13, 11, 9, 4, 6, 0, 2, 12, 14, 8, 10},
+	{5, 7, 1, 3, 13, 15, 9, 11, 6, 4, 2, 0, 14, 12, 10, 8},
+	{11, 9, 15, 13, 3, 1, 7, 5, 8, 10, 12, 14, 0, 2, 4, 6},
+	{9, 11, 13, 15, 1, 3, 5, 7, 10, 8, 14, 12, 2, 0, 6, 4},
+	{15, 13, 11, 9, 7, 5, 3, 1, 12, 14, 8, 10, 4, 6, 0, 2},
+	{13, 15, 9, 11, 5, 7, 1, 3, 14, 12, 10, 8, 6, 4, 2, 0},
+}
+
+// Damm over a quasigroup table, symbols are mapped through an alphabet
+type Damm struct {
+	table    [][]uint8
+	alphabet string
+	index    [256]int16 //-1 when not in the alphabet
+}
+
+// Decimal digits with Damm's order 10 table
+var Decimal = MustNew(table10, "0123456789")
+
+// Hexadecimal (case insensitive) with an order 16 table
+var Hex = MustNew(table16, "0123456789ABCDEF")
+
+// Build a Damm check using `table` (which must be an n x n weakly totally
+// anti-symmetric quasigroup with a zero diagonal) over an alphabet of n unique
+// symbols. If the alphabet has no lower case letters, lower case input is treated
+// as upper case
+func New(table [][]uint8, alphabet string) (*Damm, error) {
+	n := len(alphabet)
+	if n < 2 || n > 256 || len(table) != n || !validTable(table) {
+		return nil, ErrTable
+	}
+	d := &Damm{table: table, alphabet: alphabet}
+	for i := range d.index {
+		d.index[i] = -1
+	}
+	hasLower := false
+	for i := 0; i < n; i++ {
+		b := alphabet[i]
+		if d.index[b] >= 0 {
+			return nil, ErrTable
+		}
+		d.index[b] = int16(i)
+		hasLower = hasLower || (b >= 'a' && b <= 'z')
+	}
+	if !hasLower {
+		for b := 'a'; b <= 'z'; b++ {
+			d.index[b] = d.index[b-'a'+'A']
+		}
+	}
+	return d, nil
+}
+
+// Like New but panics on an invalid table (for package level vars)
+func MustNew(table [][]uint8, alphabet string) *Damm {
+	d, err := New(table, alphabet)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func validTable(t [][]uint8) bool {
+	n := len(t)
+	for i := 0; i < n; i++ {
+		if len(t[i]) != n || t[i][i] != 0 {
+			return false
+		}
+	}
+	//Each row and column is a permutation (a Latin square)
+	for i := 0; i < n; i++ {
+		row := make([]bool, n)
+		col := make([]bool, n)
+		for j := 0; j < n; j++ {
+			r, c := int(t[i][j]), int(t[j][i])
+			if r >= n || c >= n || row[r] || col[c] {
+				return false
+			}
+			row[r], col[c] = true, true
+		}
+	}
+	//(c∘x)∘y = (c∘y)∘x only when x = y
+	for c := 0; c < n; c++ {
+		for x := 0; x < n; x++ {
+			for y := x + 1; y < n; y++ {
+				if t[t[c][x]][y] == t[t[c][y]][x] {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// Order of the quasigroup (size of the alphabet)
+func (d *Damm) N() int { return len(d.alphabet) }
+
+// Generate a check value (0 to N-1) for the given number (written in base N)
+func (d *Damm) Checksum(i uint64) uint8 {
+	n := uint64(len(d.alphabet))
+	//Most significant digit first
+	var ds []uint8
+	for {
+		ds = append(ds, uint8(i%n))
+		i /= n
+		if i == 0 {
+			break
+		}
+	}
+	c := uint8(0)
+	for j := len(ds) - 1; j >= 0; j-- {
+		c = d.table[c][ds[j]]
+	}
+	return c
+}
+
+// The interim digit after processing s, skipping separators
+func (d *Damm) interim(s string) (c uint8, count int, err error) {
+	for i := 0; i < len(s); i++ {
+		b := s[i]
+		v := d.index[b]
+		if v < 0 {
+			if checksum.IsSeparator(b) {
+				continue
+			}
+			return 0, 0, checksum.InvalidCharAt("Damm", b, i)
+		}
+		c = d.table[c][v]
+		count++
+	}
+	return
+}
+
+// Generate the check symbol for s
+func (d *Damm) Generate(s string) (byte, error) {
+	c, count, err := d.interim(s)
+	if err != nil {
+		return 0, err
+	}
+	if count < 1 {
+		return 0, ErrTooShort
+	}
+	return d.alphabet[c], nil
+}
+
+// Check the last symbol of s is the correct check symbol. Returns
+// ErrCheckFailed if it isn't, a checksum.InvalidCharError for unknown symbols
+func (d *Damm) Validate(s string) error {
+	c, count, err := d.interim(s)
+	if err != nil {
+		return err
+	}
+	if count < 2 {
+		return ErrTooShort
+	}
+	if c != 0 {
+		return ErrCheckFailed
+	}
+	return nil
+}
+
+// Append the check symbol to s
+func (d *Damm) Append(s string) (string, error) {
+	c, err := d.Generate(s)
+	if err != nil {
+		return "", err
+	}
+	return s + string(c), nil
+}
+
+// Generate a single digit (0-9) checksum for the given number
+func Checksum(i uint64) uint8 { return Decimal.Checksum(i) }
+
+// Generate the check digit ('0'-'9') for a string of digits of any length,
+// spaces and dashes are ignored
+func Generate(s string) (byte, error) { return Decimal.Generate(s) }
+
+// Check the last digit of s is the correct Damm check digit, spaces and dashes
+// are ignored. Returns ErrCheckFailed if it isn't, a checksum.InvalidCharError for
+// anything that isn't a digit
+func Validate(s string) error { return Decimal.Validate(s) }
+
+// Append the check digit to a string of digits
+func Append(s string) (string, error) { return Decimal.Append(s) }
@@ -0,0 +1,166 @@
+package damm
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/gnabgib/gnablib-go/checksum"
+)
+
+var dammTests = []struct {
+	d *Damm
+	s string
+	c byte
+}{
+	//Wiki
+	{Decimal, "572", '4'},
+	//Others
+	{Decimal, "12345", '9'},
+	{Decimal, "0", '0'},
+	{Decimal, "123456789", '4'},
+	{Decimal, "123-456 789", '4'},
+	{Decimal, "8473643095483728456789", '6'},
+	{Hex, "DEADBEEF", 'A'},
+	{Hex, "deadbeef", 'A'},
+	{Hex, "C0FFEE", 'E'},
+	{Hex, "0", '0'},
+	{Hex, "123456789ABCDEF", '5'},
+}
+
+func TestGenerate(t *testing.T) {
+	for _, rec := range dammTests {
+		found, err := rec.d.Generate(rec.s)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", rec.s, err)
+			continue
+		}
+		if found != rec.c {
+			t.Errorf("%s: expecting %c, got %c", rec.s, rec.c, found)
+		}
+		full, _ := rec.d.Append(rec.s)
+		if err = rec.d.Validate(full); err != nil {
+			t.Errorf("%s: unexpected error %v", full, err)
+		}
+	}
+}
+
+func TestChecksum(t *testing.T) {
+	for _, rec := range dammTests {
+		if rec.d != Decimal {
+			continue
+		}
+		n, err := strconv.ParseUint(rec.s, 10, 64)
+		if err != nil {
+			continue
+		}
+		if found := Checksum(n); found != rec.c-'0' {
+			t.Errorf("%d: expecting %c, got %d", n, rec.c, found)
+		}
+	}
+	if found := Hex.Checksum(0xDEADBEEF); found != 0xA {
+		t.Errorf("Hex: expecting A, got %X", found)
+	}
+}
+
+func TestPackageFuncs(t *testing.T) {
+	full, err := Append("572")
+	if full != "5724" || err != nil {
+		t.Errorf("Expecting 5724, got %s, %v", full, err)
+	}
+	if c, _ := Generate("572"); c != '4' {
+		t.Errorf("Expecting 4, got %c", c)
+	}
+	if err := Validate("5724"); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
+
+func TestValidate_errors(t *testing.T) {
+	tests := []struct {
+		d   *Damm
+		s   string
+		err error
+	}{
+		{Decimal, "5725", ErrCheckFailed},
+		{Decimal, "57A4", checksum.InvalidCharAt("Damm", 'A', 2)},
+		{Decimal, "5", ErrTooShort},
+		{Hex, "DEADBEEG", checksum.InvalidCharAt("Damm", 'G', 7)},
+		{Hex, "--", ErrTooShort},
+	}
+	for _, rec := range tests {
+		if err := rec.d.Validate(rec.s); err != rec.err {
+			t.Errorf("%q: expecting %v, got %v", rec.s, rec.err, err)
+		}
+	}
+	if _, err := Hex.Generate(""); err != ErrTooShort {
+		t.Errorf("Expecting ErrTooShort, got %v", err)
+	}
+}
+
+func TestNew_errors(t *testing.T) {
+	//Not a quasigroup (repeat in a row)
+	bad := [][]uint8{{0, 1, 1}, {1, 0, 2}, {2, 2, 0}}
+	//Quasigroup, zero diagonal, not anti-symmetric (x⊕y)
+	xor := [][]uint8{{0, 1, 2, 3}, {1, 0, 3, 2}, {2, 3, 0, 1}, {3, 2, 1, 0}}
+	tests := []struct {
+		table    [][]uint8
+		alphabet string
+	}{
+		{table10, "0123456789A"},
+		{table10, "0123456780"},
+		{bad, "abc"},
+		{xor, "abcd"},
+		{[][]uint8{{1, 0}, {0, 1}}, "01"},
+		{[][]uint8{{0}}, "0"},
+	}
+	for _, rec := range tests {
+		if _, err := New(rec.table, rec.alphabet); err != ErrTable {
+			t.Errorf("%q: expecting ErrTable, got %v", rec.alphabet, err)
+		}
+	}
+}
+
+// Every payload of `size` symbols: each single symbol error and adjacent
+// transposition (including with the check symbol) must be detected
+func exhaustive(t *testing.T, d *Damm, size int) {
+	n := d.N()
+	total := 1
+	for i := 0; i < size; i++ {
+		total *= n
+	}
+	payload := make([]byte, size)
+	for v := 0; v < total; v++ {
+		for i, x := size-1, v; i >= 0; i, x = i-1, x/n {
+			payload[i] = d.alphabet[x%n]
+		}
+		full, _ := d.Append(string(payload))
+		b := []byte(full)
+		for i := range b {
+			orig := b[i]
+			for j := 0; j < n; j++ {
+				if d.alphabet[j] == orig {
+					continue
+				}
+				b[i] = d.alphabet[j]
+				if d.Validate(string(b)) == nil {
+					t.Fatalf("%s: single error %s not detected", full, b)
+				}
+			}
+			b[i] = orig
+		}
+		for i := 0; i < len(b)-1; i++ {
+			if b[i] == b[i+1] {
+				continue
+			}
+			b[i], b[i+1] = b[i+1], b[i]
+			if d.Validate(string(b)) == nil {
+				t.Fatalf("%s: transposition %s not detected", full, b)
+			}
+			b[i], b[i+1] = b[i+1], b[i]
+		}
+	}
+}
+
+func TestExhaustive10(t *testing.T) { exhaustive(t, Decimal, 4) }
+
+func TestExhaustive16(t *testing.T) { exhaustive(t, Hex, 3) }
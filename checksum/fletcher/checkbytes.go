@@ -0,0 +1,120 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+package fletcher
+
+//https://datatracker.ietf.org/doc/html/rfc1008 (section 7, ISO 8473 checksum)
+//https://datatracker.ietf.org/doc/html/rfc905 (Annex B, X.224 checksum)
+//Check values X,Y at (1 based) word n of an L word PDU, with C0,C1 the sums
+//when X,Y are zero: X=(L-n)C0-C1, Y=C1-(L-n+1)C0 (mod m) so both sums are zero
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// The check bytes don't fit in the PDU at the offset, or aren't word aligned
+var ErrCheckOffset = errors.New("check bytes offset out of range")
+
+// Set the two check bytes at `offset` (and offset+1) so the Fletcher 16 sum of
+// the whole PDU is zero (ISO 8473, IS-IS, X.224). Zero check values are stored
+// as 255 (zero check bytes mean "not computed" in these protocols)
+func InsertCheckBytes(pdu []byte, offset int) error {
+	if offset < 0 || offset+2 > len(pdu) {
+		return ErrCheckOffset
+	}
+	pdu[offset], pdu[offset+1] = 0, 0
+	d := digest16{}
+	d.update(pdu)
+	x, y := checkValues(uint64(d.a), uint64(d.b), int64(len(pdu)), int64(offset), 0xff)
+	pdu[offset], pdu[offset+1] = byte(x), byte(y)
+	return nil
+}
+
+// Whether the Fletcher 16 sum of the PDU, including check bytes, is zero.
+// Verify doesn't know where the check bytes are, so it can't treat zero check
+// bytes as "not computed": ISO 8473 and X.224 receivers should skip Verify when
+// both check bytes are zero (IS-IS instead treats a zero LSP checksum as an
+// error, which Verify reports)
+func Verify(pdu []byte) bool {
+	return fletcher16(pdu) == 0
+}
+
+// Set the two 16bit check words (little endian, like New32) at byte `offset`
+// so the Fletcher 32 sum of the whole PDU is zero. The offset must be even,
+// a trailing odd byte is zero padded. Zero check values are stored as 0xffff
+func InsertCheckBytes32(pdu []byte, offset int) error {
+	if offset < 0 || offset&1 != 0 || offset+4 > len(pdu) {
+		return ErrCheckOffset
+	}
+	binary.LittleEndian.PutUint32(pdu[offset:], 0)
+	d := digest32{}
+	d.update(pdu)
+	t := d.final()
+	x, y := checkValues(uint64(t.a), uint64(t.b), int64(len(pdu)+1)/2, int64(offset/2), 0xffff)
+	binary.LittleEndian.PutUint16(pdu[offset:], uint16(x))
+	binary.LittleEndian.PutUint16(pdu[offset+2:], uint16(y))
+	return nil
+}
+
+// Whether the Fletcher 32 sum of the PDU, including check words, is zero
+func Verify32(pdu []byte) bool {
+	return fletcher32(pdu) == 0
+}
+
+// Set the two 32bit check words (little endian, like New64) at byte `offset`
+// so the Fletcher 64 sum of the whole PDU is zero. The offset must be a
+// multiple of 4, a trailing partial word is zero padded. Zero check values are
+// stored as 0xffffffff
+func InsertCheckBytes64(pdu []byte, offset int) error {
+	if offset < 0 || offset&3 != 0 || offset+8 > len(pdu) {
+		return ErrCheckOffset
+	}
+	binary.LittleEndian.PutUint64(pdu[offset:], 0)
+	d := digest64{}
+	d.update(pdu)
+	t := d.final()
+	x, y := checkValues(uint64(t.a), uint64(t.b), int64(len(pdu)+3)/4, int64(offset/4), 0xffffffff)
+	binary.LittleEndian.PutUint32(pdu[offset:], uint32(x))
+	binary.LittleEndian.PutUint32(pdu[offset+4:], uint32(y))
+	return nil
+}
+
+// Whether the Fletcher 64 sum of the PDU, including check words, is zero
+func Verify64(pdu []byte) bool {
+	return fletcher64(pdu) == 0
+}
+
+// Check values for sums c0,c1 (of a PDU with zeroed check words) of `words`
+// words, with the check words at (0 based) word `at`
+func checkValues(c0, c1 uint64, words, at int64, mod uint64) (x, y uint64) {
+	//Words after X (including Y), all values are below 2^32 so products fit
+	after := uint64(words-at-1) % mod
+	x = (after*c0%mod + mod - c1) % mod
+	y = (c1 + mod - (after+1)%mod*c0%mod) % mod
+	if x == 0 {
+		x = mod
+	}
+	if y == 0 {
+		y = mod
+	}
+	return
+}
+
+func fletcher16(p []byte) uint16 {
+	d := digest16{}
+	d.update(p)
+	return d.Sum16()
+}
+
+func fletcher32(p []byte) uint32 {
+	d := digest32{}
+	d.update(p)
+	return d.Sum32()
+}
+
+func fletcher64(p []byte) uint64 {
+	d := digest64{}
+	d.update(p)
+	return d.Sum64()
+}
@@ -0,0 +1,183 @@
+package fletcher
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"math/rand"
+	"testing"
+)
+
+// A hand built IS-IS level 1 LSP (hostname gnabgib), the checksum covers from
+// the LSP ID to the end, and is at PDU offset 24 (12 into the covered part)
+const lspHex = "831b010012010000003304b0" +
+	"192168000001000000000001e8dc03" +
+	"010403490001" + "8101cc" + "8907676e6162676962" + "84040a000001"
+
+func TestInsertCheckBytes(t *testing.T) {
+	lsp, _ := hex.DecodeString(lspHex)
+	covered := lsp[12:]
+	if !Verify(covered) {
+		t.Errorf("Expecting LSP to verify")
+	}
+	expect := append([]byte{}, covered[12:14]...)
+	covered[12], covered[13] = 0, 0
+	//Zero (not computed) check bytes don't verify
+	if Verify(covered) {
+		t.Errorf("Expecting LSP with zero checksum to fail")
+	}
+	if err := InsertCheckBytes(covered, 12); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if covered[12] != expect[0] || covered[13] != expect[1] {
+		t.Errorf("Expecting %x, got %x", expect, covered[12:14])
+	}
+	covered[20] ^= 1
+	if Verify(covered) {
+		t.Errorf("Expecting a changed LSP to fail")
+	}
+}
+
+// Search every pair of check bytes (1-255) for those that zero the sum,
+// without using the ISO 8473 formula
+func searchCheckBytes(pdu []byte, offset int) (x, y byte, n int) {
+	p := append([]byte{}, pdu...)
+	for i := 1; i < 256; i++ {
+		for j := 1; j < 256; j++ {
+			p[offset], p[offset+1] = byte(i), byte(j)
+			if fletcher16(p) == 0 {
+				x, y = byte(i), byte(j)
+				n++
+			}
+		}
+	}
+	return
+}
+
+func TestInsertCheckBytes_search(t *testing.T) {
+	lsp, _ := hex.DecodeString(lspHex)
+	rnd := rand.New(rand.NewSource(10589))
+	pdus := [][]byte{lsp[12:], []byte("gnabgib"), {0, 0}, {0, 0, 0xff}}
+	for i := 0; i < 10; i++ {
+		p := make([]byte, 2+rnd.Intn(30))
+		rnd.Read(p)
+		pdus = append(pdus, p)
+	}
+	for _, p := range pdus {
+		for off := 0; off+2 <= len(p); off += 1 + len(p)/4 {
+			x, y, n := searchCheckBytes(p, off)
+			if n != 1 {
+				t.Errorf("%x@%d expecting one solution, found %d", p, off, n)
+				continue
+			}
+			InsertCheckBytes(p, off)
+			if p[off] != x || p[off+1] != y {
+				t.Errorf("%x@%d expecting %02x%02x, got %02x%02x", p, off, x, y, p[off], p[off+1])
+			}
+		}
+	}
+}
+
+func TestInsertCheckBytes_random(t *testing.T) {
+	rnd := rand.New(rand.NewSource(8473))
+	for i := 0; i < 1000; i++ {
+		p := make([]byte, 2+rnd.Intn(100))
+		rnd.Read(p)
+		off := rnd.Intn(len(p) - 1)
+		if err := InsertCheckBytes(p, off); err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+		if !Verify(p) {
+			t.Errorf("%x@%d expected to verify", p, off)
+		}
+		if p[off] == 0 || p[off+1] == 0 {
+			t.Errorf("%x@%d has zero check bytes", p, off)
+		}
+	}
+}
+
+func TestInsertCheckBytes32(t *testing.T) {
+	p := []byte("gnabgib gnabgib!")
+	if err := InsertCheckBytes32(p, 4); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	//Y is fixed by the first sum being zero, so searching X finds the only
+	// check words that zero both (without using the formula)
+	q := append([]byte{}, p...)
+	found := 0
+	for x := 1; x <= 0xffff; x++ {
+		binary.LittleEndian.PutUint16(q[4:], uint16(x))
+		binary.LittleEndian.PutUint16(q[6:], 0)
+		y := 0xffff - fletcher32(q)&0xffff
+		if y == 0 {
+			y = 0xffff
+		}
+		binary.LittleEndian.PutUint16(q[6:], uint16(y))
+		if fletcher32(q) == 0 {
+			found++
+			if !bytes.Equal(q, p) {
+				t.Errorf("Expecting %x, got %x", q, p)
+			}
+		}
+	}
+	if found != 1 {
+		t.Errorf("Expecting one solution, found %d", found)
+	}
+	if found := hex.EncodeToString(p); found != "676e6162efe4b4ee676e616267696221" {
+		t.Errorf("Expecting 676e6162efe4b4ee676e616267696221, got %s", found)
+	}
+	if !Verify32(p) {
+		t.Errorf("Expecting to verify")
+	}
+	rnd := rand.New(rand.NewSource(1146))
+	for i := 0; i < 1000; i++ {
+		p := make([]byte, 4+rnd.Intn(100))
+		rnd.Read(p)
+		off := rnd.Intn(len(p)/2-1) * 2
+		if err := InsertCheckBytes32(p, off); err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+		if !Verify32(p) {
+			t.Errorf("%x@%d expected to verify", p, off)
+		}
+	}
+}
+
+func TestInsertCheckBytes64(t *testing.T) {
+	rnd := rand.New(rand.NewSource(905))
+	for i := 0; i < 1000; i++ {
+		p := make([]byte, 8+rnd.Intn(100))
+		rnd.Read(p)
+		off := rnd.Intn(len(p)/4-1) * 4
+		if err := InsertCheckBytes64(p, off); err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+		if !Verify64(p) {
+			t.Errorf("%x@%d expected to verify", p, off)
+		}
+		p[rnd.Intn(len(p))] ^= 0x10
+		if Verify64(p) {
+			t.Errorf("%x@%d expected to fail after change", p, off)
+		}
+	}
+}
+
+func TestInsertCheckBytes_offset(t *testing.T) {
+	p := make([]byte, 10)
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"16 past end", InsertCheckBytes(p, 9)},
+		{"16 negative", InsertCheckBytes(p, -1)},
+		{"32 odd", InsertCheckBytes32(p, 3)},
+		{"32 past end", InsertCheckBytes32(p, 8)},
+		{"64 unaligned", InsertCheckBytes64(p, 2)},
+		{"64 past end", InsertCheckBytes64(p, 4)},
+	}
+	for _, rec := range tests {
+		if rec.err != ErrCheckOffset {
+			t.Errorf("%s expecting ErrCheckOffset, got %v", rec.name, rec.err)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+package fletcher
+
+//Given A=(a1,b1) and B=(a2,b2) over n words: A||B = (a1+a2, b1+b2+n*a1)
+
+import (
+	"encoding/binary"
+
+	"github.com/gnabgib/gnablib-go/checksum"
+)
+
+// Fletcher 16 of A||B, from the checksums of A and B, and the length of B in bytes
+func Combine16(sumA, sumB uint16, lenB int64) uint16 {
+	a, b := combine(uint64(sumA&0xff), uint64(sumA>>8), uint64(sumB&0xff), uint64(sumB>>8), lenB, 0xff)
+	return uint16(b)<<8 | uint16(a)
+}
+
+// Fletcher 32 of A||B, from the checksums of A and B, and the length of B in bytes.
+// A must be a whole number of 16bit words (an even length)
+func Combine32(sumA, sumB uint32, lenB int64) uint32 {
+	a, b := combine(uint64(sumA&0xffff), uint64(sumA>>16), uint64(sumB&0xffff), uint64(sumB>>16), (lenB+1)/2, 0xffff)
+	return uint32(b)<<16 | uint32(a)
+}
+
+// Fletcher 64 of A||B, from the checksums of A and B, and the length of B in bytes.
+// A must be a whole number of 32bit words (a length that's a multiple of 4)
+func Combine64(sumA, sumB uint64, lenB int64) uint64 {
+	a, b := combine(sumA&0xffffffff, sumA>>32, sumB&0xffffffff, sumB>>32, (lenB+3)/4, 0xffffffff)
+	return b<<32 | a
+}
+
+// A partial trailing word in B is zero padded, which is the same as it being
+// the end of A||B, so `words` rounds up
+func combine(a1, b1, a2, b2 uint64, words int64, mod uint64) (a, b uint64) {
+	n := uint64(words) % mod
+	a = (a1 + a2) % mod
+	//All values are below 2^32, so each product fits in 64bits
+	b = (b1 + b2 + n*a1%mod) % mod
+	return
+}
+
+// Update the state as if the `n` bytes summarized by `sum` had been written
+func (d *digest16) Combine(sum []byte, n int64) error {
+	if len(sum) != size16 {
+		return checksum.ErrCombineSize
+	}
+	s := Combine16(d.Sum16(), binary.BigEndian.Uint16(sum), n)
+	(*d).a = byte(s)
+	(*d).b = byte(s >> 8)
+	return nil
+}
+
+// Update the state as if the `n` bytes summarized by `sum` had been written. The
+// digest must hold whole words (ErrCombineAlign otherwise), if `n` isn't whole
+// words the padded state means only Sum is meaningful after
+func (d *digest32) Combine(sum []byte, n int64) error {
+	if len(sum) != size32 {
+		return checksum.ErrCombineSize
+	}
+	if (*d).pLen != 0 {
+		return checksum.ErrCombineAlign
+	}
+	s := Combine32(d.Sum32(), binary.BigEndian.Uint32(sum), n)
+	(*d).a = uint16(s)
+	(*d).b = uint16(s >> 16)
+	return nil
+}
+
+// Update the state as if the `n` bytes summarized by `sum` had been written. The
+// digest must hold whole words (ErrCombineAlign otherwise), if `n` isn't whole
+// words the padded state means only Sum is meaningful after
+func (d *digest64) Combine(sum []byte, n int64) error {
+	if len(sum) != size64 {
+		return checksum.ErrCombineSize
+	}
+	if (*d).pLen != 0 {
+		return checksum.ErrCombineAlign
+	}
+	s := Combine64(d.Sum64(), binary.BigEndian.Uint64(sum), n)
+	(*d).a = uint32(s)
+	(*d).b = uint32(s >> 32)
+	return nil
+}
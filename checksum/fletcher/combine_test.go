@@ -0,0 +1,135 @@
+package fletcher
+
+import (
+	"bytes"
+	"hash"
+	"math/rand"
+	"testing"
+
+	"github.com/gnabgib/gnablib-go/checksum"
+)
+
+func combineData() []byte {
+	rnd := rand.New(rand.NewSource(0x666c65))
+	data := make([]byte, 20000)
+	rnd.Read(data)
+	//Some runs of 0xff to push the sums near the modulus
+	for i := 5000; i < 9000; i++ {
+		data[i] = 0xff
+	}
+	return data
+}
+
+// Splits of A (which must be whole words for 32/64) and lengths of B
+var combineSplits = []int{0, 4, 8, 1000, 5000, 7000, 19996, 20000}
+var combineLens = []int{0, 1, 2, 3, 4, 5, 7, 100, 4001}
+
+func TestCombine16(t *testing.T) {
+	data := combineData()
+	sum := func(p []byte) uint16 { h := New16(); h.Write(p); return h.Sum16() }
+	for _, split := range combineSplits {
+		for _, l := range combineLens {
+			if split+l > len(data) {
+				continue
+			}
+			a, b := data[:split], data[split:split+l]
+			expect := sum(data[:split+l])
+			if found := Combine16(sum(a), sum(b), int64(l)); found != expect {
+				t.Errorf("Combine16(%d,%d): expecting %x, got %x", split, l, expect, found)
+			}
+		}
+	}
+}
+
+func TestCombine32(t *testing.T) {
+	data := combineData()
+	sum := func(p []byte) uint32 { h := New32(); h.Write(p); return h.Sum32() }
+	for _, split := range combineSplits {
+		for _, l := range combineLens {
+			if split+l > len(data) {
+				continue
+			}
+			a, b := data[:split], data[split:split+l]
+			expect := sum(data[:split+l])
+			if found := Combine32(sum(a), sum(b), int64(l)); found != expect {
+				t.Errorf("Combine32(%d,%d): expecting %x, got %x", split, l, expect, found)
+			}
+		}
+	}
+}
+
+func TestCombine64(t *testing.T) {
+	data := combineData()
+	sum := func(p []byte) uint64 { h := New64(); h.Write(p); return h.Sum64() }
+	for _, split := range combineSplits {
+		for _, l := range combineLens {
+			if split+l > len(data) {
+				continue
+			}
+			a, b := data[:split], data[split:split+l]
+			expect := sum(data[:split+l])
+			if found := Combine64(sum(a), sum(b), int64(l)); found != expect {
+				t.Errorf("Combine64(%d,%d): expecting %x, got %x", split, l, expect, found)
+			}
+		}
+	}
+}
+
+func TestCombiner(t *testing.T) {
+	data := combineData()
+	factories := map[string]func() hash.Hash{
+		"fletcher16": func() hash.Hash { return New16() },
+		"fletcher32": func() hash.Hash { return New32() },
+		"fletcher64": func() hash.Hash { return New64() },
+	}
+	for name, factory := range factories {
+		expect := factory()
+		expect.Write(data)
+		a := factory()
+		a.Write(data[:1000])
+		b := factory()
+		b.Write(data[1000:])
+		if err := a.(checksum.Combiner).Combine(b.Sum(nil), int64(len(data)-1000)); err != nil {
+			t.Errorf("%s: unexpected error %v", name, err)
+		}
+		if !bytes.Equal(a.Sum(nil), expect.Sum(nil)) {
+			t.Errorf("%s: expecting %x, got %x", name, expect.Sum(nil), a.Sum(nil))
+		}
+		if err := a.(checksum.Combiner).Combine([]byte{1}, 1); err != checksum.ErrCombineSize {
+			t.Errorf("%s: expecting ErrCombineSize, got %v", name, err)
+		}
+	}
+
+	//Word based versions can't combine onto a partial word
+	for _, h := range []hash.Hash{New32(), New64()} {
+		h.Write([]byte{1})
+		if err := h.(checksum.Combiner).Combine(h.Sum(nil), 0); err != checksum.ErrCombineAlign {
+			t.Errorf("%T: expecting ErrCombineAlign, got %v", h, err)
+		}
+	}
+}
+
+func TestParallel(t *testing.T) {
+	data := combineData()
+	//An odd length means the final chunk ends part way through a word
+	data = data[:len(data)-3]
+	factories := map[string]func() hash.Hash{
+		"fletcher16": func() hash.Hash { return New16() },
+		"fletcher32": func() hash.Hash { return New32() },
+		"fletcher64": func() hash.Hash { return New64() },
+	}
+	for name, factory := range factories {
+		expect := factory()
+		expect.Write(data)
+		for _, chunk := range []int64{1, 8, 12, 1000, 4096, 100000} {
+			h, err := checksum.ParallelChunks(bytes.NewReader(data), int64(len(data)), chunk, factory)
+			if err != nil {
+				t.Errorf("%s(%d): unexpected error %v", name, chunk, err)
+				continue
+			}
+			if !bytes.Equal(h.Sum(nil), expect.Sum(nil)) {
+				t.Errorf("%s(%d): expecting %x, got %x", name, chunk, expect.Sum(nil), h.Sum(nil))
+			}
+		}
+	}
+}
@@ -33,8 +33,9 @@ func (d *digest16) update(p []byte) {
 	c0 = uint32((*d).a)
 	c1 = uint32((*d).b)
 
-	for i := 0; i < len(p); {
-		len := len(p)
+	n := len(p)
+	for i := 0; i < n; {
+		len := n - i
 		if len > c1Overflow {
 			len = c1Overflow
 		}
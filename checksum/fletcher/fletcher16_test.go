@@ -1,9 +1,11 @@
 package fletcher
 
 import (
+	"hash"
 	"testing"
 
 	"github.com/gnabgib/gnablib-go/endian"
+	"github.com/gnabgib/gnablib-go/test"
 )
 
 var fletcher16tests = []struct {
@@ -53,3 +55,14 @@ func TestFletcher16(t *testing.T) {
 		}
 	}
 }
+
+func TestFletcher16Conformance(t *testing.T) {
+	vectors := test.SumVectors(len(fletcher16tests), 2, func(i int) (string, uint64) {
+		return fletcher16tests[i].s, uint64(fletcher16tests[i].c)
+	})
+	test.HashConformance(t, func() hash.Hash { return New16() }, vectors)
+}
+
+func TestFletcher16MillionA(t *testing.T) {
+	test.HashLongTest(t, New16(), "a", 1000000, "7328")
+}
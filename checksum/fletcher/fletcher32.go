@@ -14,6 +14,8 @@ const size32=4
 
 type digest32 struct {
 	a, b uint16
+	part byte //A trailing byte waiting for the rest of its word
+	pLen int  //Number of bytes in part (0-1)
 }
 
 // A new Hash32 for computing the Fletcher 32 checksum
@@ -31,7 +33,17 @@ func (d *digest32) update(p []byte) {
 	c0 = uint64((*d).a)
 	c1 = uint64((*d).b)
 
-	n := len(p)
+	//Complete a word started in a prior write
+	if (*d).pLen > 0 && len(p) > 0 {
+		//Fletcher is little endian
+		c0 += uint64((*d).part) | uint64(p[0])<<8
+		c1 += c0
+		p = p[1:]
+		(*d).pLen = 0
+	}
+
+	//Only process whole words, a trailing byte is kept for the next write
+	n := len(p) &^ 1
 	for i := 0; i < n; {
 		len := n - i
 		if len > c1Overflow {
@@ -41,20 +53,28 @@ func (d *digest32) update(p []byte) {
 			//We only use j for the batch-count (incrementing i each loop)
 
 			//Fletcher is little endian
-			add := uint64(p[i])
-			i++
-			if i < n {
-				add |= uint64(p[i]) << 8
-				i++
-			}
-			c0 += add
+			c0 += uint64(p[i]) | uint64(p[i+1])<<8
 			c1 += c0
+			i += 2
 		}
 		c0 %= mod
 		c1 %= mod
 	}
-	(*d).a = uint16(c0)
-	(*d).b = uint16(c1)
+	if n < len(p) {
+		(*d).part = p[n]
+		(*d).pLen = 1
+	}
+	(*d).a = uint16(c0 % mod)
+	(*d).b = uint16(c1 % mod)
+}
+
+// The final state, with any partial word zero padded (doesn't mutate d)
+func (d *digest32) final() digest32 {
+	t := *d
+	if t.pLen > 0 {
+		t.update([]byte{0})
+	}
+	return t
 }
 
 func (d *digest32) Write(p []byte) (n int, err error) {
@@ -63,16 +83,21 @@ func (d *digest32) Write(p []byte) (n int, err error) {
 }
 
 func (d *digest32) Sum(in []byte) []byte {
-	return append(in, byte((*d).b>>8), byte((*d).b), byte((*d).a>>8), byte((*d).a))
+	t := d.final()
+	return append(in, byte(t.b>>8), byte(t.b), byte(t.a>>8), byte(t.a))
 }
 
 func (d *digest32) Reset() {
 	(*d).a = 0
 	(*d).b = 0
+	(*d).pLen = 0
 }
 
 func (d *digest32) Size() int { return size32 }
 
 func (d *digest32) BlockSize() int { return size32/2 }
 
-func (d *digest32) Sum32() uint32 { return uint32((*d).b)<<16 | uint32((*d).a) }
+func (d *digest32) Sum32() uint32 {
+	t := d.final()
+	return uint32(t.b)<<16 | uint32(t.a)
+}
@@ -1,9 +1,11 @@
 package fletcher
 
 import (
+	"hash"
 	"testing"
 
 	"github.com/gnabgib/gnablib-go/endian"
+	"github.com/gnabgib/gnablib-go/test"
 )
 
 var fletcher32tests = []struct {
@@ -55,3 +57,14 @@ func TestFletcher32(t *testing.T) {
 		}
 	}
 }
+
+func TestFletcher32Conformance(t *testing.T) {
+	vectors := test.SumVectors(len(fletcher32tests), 4, func(i int) (string, uint64) {
+		return fletcher32tests[i].s, uint64(fletcher32tests[i].c)
+	})
+	test.HashConformance(t, func() hash.Hash { return New32() }, vectors)
+}
+
+func TestFletcher32MillionA(t *testing.T) {
+	test.HashLongTest(t, New32(), "a", 1000000, "E1E11414")
+}
@@ -7,6 +7,7 @@ package fletcher
 //https://datatracker.ietf.org/doc/html/rfc1146 (Appendix I)
 
 import (
+	"encoding/binary"
 	"hash"
 )
 
@@ -14,6 +15,8 @@ const size64 = 8
 
 type digest64 struct {
 	a, b uint32
+	part [4]byte //Trailing bytes waiting for the rest of their word
+	pLen int     //Number of bytes in part (0-3)
 }
 
 // A new Hash64 for computing the Fletcher 64 checksum
@@ -31,7 +34,21 @@ func (d *digest64) update(p []byte) {
 	c0 = uint64((*d).a)
 	c1 = uint64((*d).b)
 
-	n := len(p)
+	//Complete a word started in a prior write
+	if (*d).pLen > 0 {
+		c := copy((*d).part[(*d).pLen:], p)
+		(*d).pLen += c
+		p = p[c:]
+		if (*d).pLen == 4 {
+			//Fletcher is little endian
+			c0 += uint64(binary.LittleEndian.Uint32((*d).part[:]))
+			c1 += c0
+			(*d).pLen = 0
+		}
+	}
+
+	//Only process whole words, trailing bytes are kept for the next write
+	n := len(p) &^ 3
 	for i := 0; i < n; {
 		len := n - i
 		if len > c1Overflow {
@@ -41,28 +58,27 @@ func (d *digest64) update(p []byte) {
 			//We only use j for the batch-count (incrementing i each loop)
 
 			//Fletcher is little endian
-			add := uint64(p[i])
-			i++
-			if i < n {
-				add |= uint64(p[i]) << 8
-				i++
-				if i < n {
-					add |= uint64(p[i]) << 16
-					i++
-					if i < n {
-						add |= uint64(p[i]) << 24
-						i++
-					}
-				}
-			}
-			c0 += add
+			c0 += uint64(binary.LittleEndian.Uint32(p[i:]))
 			c1 += c0
+			i += 4
 		}
 		c0 %= mod
 		c1 %= mod
 	}
-	(*d).a = uint32(c0)
-	(*d).b = uint32(c1)
+	if n < len(p) {
+		(*d).pLen = copy((*d).part[:], p[n:])
+	}
+	(*d).a = uint32(c0 % mod)
+	(*d).b = uint32(c1 % mod)
+}
+
+// The final state, with any partial word zero padded (doesn't mutate d)
+func (d *digest64) final() digest64 {
+	t := *d
+	if t.pLen > 0 {
+		t.update(make([]byte, 4-t.pLen))
+	}
+	return t
 }
 
 func (d *digest64) Write(p []byte) (n int, err error) {
@@ -71,17 +87,22 @@ func (d *digest64) Write(p []byte) (n int, err error) {
 }
 
 func (d *digest64) Sum(in []byte) []byte {
-	return append(in, byte((*d).b>>24), byte((*d).b>>16), byte((*d).b>>8), byte((*d).b),
-		byte((*d).a>>24), byte((*d).a>>16), byte((*d).a>>8), byte((*d).a))
+	t := d.final()
+	return append(in, byte(t.b>>24), byte(t.b>>16), byte(t.b>>8), byte(t.b),
+		byte(t.a>>24), byte(t.a>>16), byte(t.a>>8), byte(t.a))
 }
 
 func (d *digest64) Reset() {
 	(*d).a = 0
 	(*d).b = 0
+	(*d).pLen = 0
 }
 
 func (d *digest64) Size() int { return size64 }
 
 func (d *digest64) BlockSize() int { return size64 / 2 }
 
-func (d *digest64) Sum64() uint64 { return uint64((*d).b)<<32 | uint64((*d).a) }
+func (d *digest64) Sum64() uint64 {
+	t := d.final()
+	return uint64(t.b)<<32 | uint64(t.a)
+}
@@ -1,9 +1,11 @@
 package fletcher
 
 import (
+	"hash"
 	"testing"
 
 	"github.com/gnabgib/gnablib-go/endian"
+	"github.com/gnabgib/gnablib-go/test"
 )
 
 var fletcher64tests = []struct {
@@ -55,3 +57,14 @@ func TestFletcher64(t *testing.T) {
 		}
 	}
 }
+
+func TestFletcher64Conformance(t *testing.T) {
+	vectors := test.SumVectors(len(fletcher64tests), 8, func(i int) (string, uint64) {
+		return fletcher64tests[i].s, uint64(fletcher64tests[i].c)
+	})
+	test.HashConformance(t, func() hash.Hash { return New64() }, vectors)
+}
+
+func TestFletcher64MillionA(t *testing.T) {
+	test.HashLongTest(t, New64(), "a", 1000000, "FAFAFAFA0A0A0A0A")
+}
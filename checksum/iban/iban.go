@@ -0,0 +1,165 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+// International Bank Account Number (ISO 13616), check digits are ISO 7064 MOD 97-10
+package iban
+
+//https://en.wikipedia.org/wiki/International_Bank_Account_Number#Validating_the_IBAN
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gnabgib/gnablib-go/checksum"
+	"github.com/gnabgib/gnablib-go/checksum/iso7064"
+)
+
+// The country code isn't in the registry
+var ErrCountry = errors.New("Unknown IBAN country")
+
+// The IBAN is the wrong length for the country
+var ErrLength = errors.New("Wrong IBAN length for country")
+
+// The BBAN doesn't match the country's structure (use errors.Is to test for this)
+var ErrStructure = errors.New("BBAN doesn't match the country structure")
+
+// The first BBAN character that doesn't match the country's structure, At is
+// its index in the BBAN (electronic format)
+type StructureError struct {
+	Byte byte
+	At   int
+}
+
+func (e StructureError) Error() string {
+	return fmt.Sprintf("BBAN doesn't match the country structure: %q @ %d", e.Byte, e.At)
+}
+
+func (e StructureError) Unwrap() error { return ErrStructure }
+
+// The check digits don't match the content
+var ErrCheckFailed = errors.New("IBAN check failed")
+
+// The electronic format: spaces removed and upper case. Returns a
+// checksum.InvalidCharError (position in `s`) for anything other than letters,
+// digits and spaces
+func Electronic(s string) (string, error) {
+	ret := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		b := s[i]
+		switch {
+		case b == ' ':
+			continue
+		case isLower(b):
+			b = checksum.Upper(b)
+		case !isDigit(b) && !isUpper(b):
+			return "", checksum.InvalidCharAt("IBAN", b, i)
+		}
+		ret = append(ret, b)
+	}
+	return string(ret), nil
+}
+
+// The print format: electronic format in groups of four, separated by spaces
+func Format(iban string) string {
+	e, err := Electronic(iban)
+	if err != nil {
+		return iban
+	}
+	var sb strings.Builder
+	for i := 0; i < len(e); i += 4 {
+		if i > 0 {
+			sb.WriteByte(' ')
+		}
+		end := i + 4
+		if end > len(e) {
+			end = len(e)
+		}
+		sb.WriteString(e[i:end])
+	}
+	return sb.String()
+}
+
+// Letters become two digits (A=10..Z=35), the value can be much larger than
+// 64bits, but MOD 97-10 works a digit at a time
+func numeric(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		b := s[i]
+		if isUpper(b) {
+			v := b - 'A' + 10
+			sb.WriteByte('0' + v/10)
+			sb.WriteByte('0' + v%10)
+		} else {
+			sb.WriteByte(b)
+		}
+	}
+	return sb.String()
+}
+
+// Compute the two check digits for a country and BBAN (which must match the
+// country's structure)
+func CheckDigits(country, bban string) (string, error) {
+	spec, ok := Lookup(country)
+	if !ok {
+		return "", ErrCountry
+	}
+	bban, err := Electronic(bban)
+	if err != nil {
+		return "", err
+	}
+	if len(bban)+4 != spec.Length {
+		return "", ErrLength
+	}
+	if at := spec.mismatch(bban); at >= 0 {
+		return "", StructureError{bban[at], at}
+	}
+	c, err := iso7064.Mod97_10.Compute(numeric(bban + country))
+	if err != nil {
+		return "", err
+	}
+	//ISO 7064 gives 00-96, IBAN uses 02-98 (97 and 98 are equivalent to 00 and 01)
+	if c == "00" || c == "01" {
+		c = string([]byte{'9', c[1] + 7})
+	}
+	return c, nil
+}
+
+// Build an IBAN (electronic format) from a country and BBAN
+func Generate(country, bban string) (string, error) {
+	c, err := CheckDigits(country, bban)
+	if err != nil {
+		return "", err
+	}
+	bban, _ = Electronic(bban)
+	return country + c + bban, nil
+}
+
+// Validate an IBAN, spaces are ignored and letters can be either case
+func Validate(iban string) error {
+	e, err := Electronic(iban)
+	if err != nil {
+		return err
+	}
+	if len(e) < 4 {
+		return ErrLength
+	}
+	spec, ok := Lookup(e[:2])
+	if !ok {
+		return ErrCountry
+	}
+	if len(e) != spec.Length {
+		return ErrLength
+	}
+	//Check digits are 02-98
+	if !isDigit(e[2]) || !isDigit(e[3]) || e[2:4] < "02" || e[2:4] > "98" {
+		return ErrCheckFailed
+	}
+	if at := spec.mismatch(e[4:]); at >= 0 {
+		return StructureError{e[4+at], at}
+	}
+	if iso7064.Mod97_10.Validate(numeric(e[4:]+e[:4])) != nil {
+		return ErrCheckFailed
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package iban
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gnabgib/gnablib-go/checksum"
+)
+
+var validIbans = []string{
+	//Wiki / registry examples
+	"GB82 WEST 1234 5698 7654 32",
+	"DE89 3704 0044 0532 0130 00",
+	"FR14 2004 1010 0505 0001 3M02 606",
+	"NL91 ABNA 0417 1643 00",
+	"BE68 5390 0754 7034",
+	"CH93 0076 2011 6238 5295 7",
+	"NO93 8601 1117 947",
+	"AT61 1904 3002 3457 3201",
+	"IT60 X054 2811 1010 0000 0123 456",
+	"ES91 2100 0418 4502 0005 1332",
+	"MT84 MALT 0110 0001 2345 MTLC AST0 01S",
+	"SA03 8000 0000 6080 1016 7519",
+	"SC18 SSCB 1101 0000 0000 0000 1497 USD",
+	"BR97 0036 0305 0000 1000 9795 493P 1",
+	"MU17 BOMM 0101 1010 3030 0200 000M UR",
+	//ISO 7064 gives 01, IBAN uses 98
+	"GB98 NWBK 6016 1300 0000 64",
+	"gb82west12345698765432",
+}
+
+func TestValidate(t *testing.T) {
+	for _, iban := range validIbans {
+		if err := Validate(iban); err != nil {
+			t.Errorf("%s: unexpected error %v", iban, err)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	for _, iban := range validIbans {
+		e, _ := Electronic(iban)
+		found, err := Generate(e[:2], e[4:])
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", iban, err)
+			continue
+		}
+		if found != e {
+			t.Errorf("Expecting %s, got %s", e, found)
+		}
+		c, _ := CheckDigits(e[:2], e[4:])
+		if c != e[2:4] {
+			t.Errorf("%s: expecting check %s, got %s", iban, e[2:4], c)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		in, expect string
+	}{
+		{"GB82WEST12345698765432", "GB82 WEST 1234 5698 7654 32"},
+		{"gb82 west 1234 5698 7654 32", "GB82 WEST 1234 5698 7654 32"},
+		{"BE68539007547034", "BE68 5390 0754 7034"},
+		{"", ""},
+		{"bad!", "bad!"},
+	}
+	for _, rec := range tests {
+		if found := Format(rec.in); found != rec.expect {
+			t.Errorf("%q: expecting %q, got %q", rec.in, rec.expect, found)
+		}
+	}
+}
+
+func TestValidate_errors(t *testing.T) {
+	tests := []struct {
+		in  string
+		err error
+	}{
+		{"GB83 WEST 1234 5698 7654 32", ErrCheckFailed},
+		{"GB82 WEST 1234 5698 7654 23", ErrCheckFailed},
+		{"GB82 WETS 1234 5698 7654 32", ErrCheckFailed},
+		{"GB00 NWBK 6016 1300 0000 64", ErrCheckFailed},
+		{"GB99 NWBK 6016 1300 0000 64", ErrCheckFailed},
+		{"GBXX WEST 1234 5698 7654 32", ErrCheckFailed},
+		{"GB82 WEST 1234 5698 7654 3", ErrLength},
+		{"GB82 WEST 1234 5698 7654 321", ErrLength},
+		{"GB82 WES1 1234 5698 7654 32", StructureError{'1', 3}},
+		{"GB82 WEST 1234 5698 7654 3A", StructureError{'A', 17}},
+		{"ZZ82 WEST 1234 5698 7654 32", ErrCountry},
+		{"GB8", ErrLength},
+		{"GB82-WEST", checksum.InvalidCharAt("IBAN", '-', 4)},
+	}
+	for _, rec := range tests {
+		if err := Validate(rec.in); err != rec.err {
+			t.Errorf("%s: expecting %v, got %v", rec.in, rec.err, err)
+		}
+	}
+	if _, err := CheckDigits("ZZ", "1234"); err != ErrCountry {
+		t.Errorf("Expecting ErrCountry, got %v", err)
+	}
+	if _, err := CheckDigits("GB", "WEST123456987654"); err != ErrLength {
+		t.Errorf("Expecting ErrLength, got %v", err)
+	}
+	if _, err := CheckDigits("GB", "wes712345698765432"); err != (StructureError{'7', 3}) || !errors.Is(err, ErrStructure) {
+		t.Errorf("Expecting StructureError, got %v", err)
+	}
+	if _, err := Generate("GB", "WEST.2345698765432"); err != checksum.InvalidCharAt("IBAN", '.', 4) {
+		t.Errorf("Expecting invalid char, got %v", err)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	spec, ok := Lookup("GB")
+	if !ok || spec.Length != 22 || spec.BBAN != "4!a6!n8!n" {
+		t.Errorf("GB: unexpected spec %+v", spec)
+	}
+	if _, ok := Lookup("ZZ"); ok {
+		t.Errorf("ZZ: expecting no spec")
+	}
+	//The registry is consistent
+	registryLock.RLock()
+	for c, s := range registry {
+		if _, n, err := parseBBAN(s.BBAN); err != nil || n+4 != s.Length || c != s.Country {
+			t.Errorf("%s: inconsistent spec %+v", c, s)
+		}
+	}
+	registryLock.RUnlock()
+
+	bad := []struct {
+		c    string
+		n    int
+		bban string
+	}{
+		{"X", 8, "4!n"},
+		{"xx", 8, "4!n"},
+		{"XX", 9, "4!n"},
+		{"XX", 8, "4n"},
+		{"XX", 8, "4!x"},
+		{"XX", 8, "!n"},
+		{"XX", 8, ""},
+	}
+	for _, rec := range bad {
+		if err := Register(rec.c, rec.n, rec.bban); err != ErrSpec {
+			t.Errorf("%s %d %s: expecting ErrSpec, got %v", rec.c, rec.n, rec.bban, err)
+		}
+	}
+	//A new country can be added
+	if err := Register("XX", 10, "2!a4!n"); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+	iban, err := Generate("XX", "AB1234")
+	if err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+	if err := Validate(iban); err != nil {
+		t.Errorf("%s: unexpected error %v", iban, err)
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+package iban
+
+//https://www.swift.com/standards/data-standards/iban-international-bank-account-number (IBAN registry)
+
+import (
+	"errors"
+	"sync"
+)
+
+// The BBAN format isn't valid, or doesn't match the length
+var ErrSpec = errors.New("Invalid IBAN country specification")
+
+// How a country structures its IBANs
+type Spec struct {
+	Country string //ISO 3166 alpha-2 code
+	Length  int    //Total IBAN length (electronic format)
+	//BBAN structure as SWIFT writes it: a sequence of length!type where type
+	//is n (digits), a (upper case letters) or c (alphanumeric) eg. 4!a6!n8!n
+	BBAN  string
+	parts []part
+}
+
+type part struct {
+	n    int
+	kind byte
+}
+
+var (
+	registryLock sync.RWMutex
+	registry     = map[string]*Spec{}
+)
+
+func init() {
+	for _, s := range []struct {
+		c string
+		n int
+		b string
+	}{
+		{"AD", 24, "4!n4!n12!c"},
+		{"AE", 23, "3!n16!n"},
+		{"AL", 28, "8!n16!c"},
+		{"AT", 20, "5!n11!n"},
+		{"AZ", 28, "4!a20!c"},
+		{"BA", 20, "3!n3!n8!n2!n"},
+		{"BE", 16, "3!n7!n2!n"},
+		{"BG", 22, "4!a4!n2!n8!c"},
+		{"BH", 22, "4!a14!c"},
+		{"BR", 29, "8!n5!n10!n1!a1!c"},
+		{"BY", 28, "4!c4!n16!c"},
+		{"CH", 21, "5!n12!c"},
+		{"CR", 22, "4!n14!n"},
+		{"CY", 28, "3!n5!n16!c"},
+		{"CZ", 24, "4!n6!n10!n"},
+		{"DE", 22, "8!n10!n"},
+		{"DK", 18, "4!n9!n1!n"},
+		{"DO", 28, "4!c20!n"},
+		{"EE", 20, "2!n2!n11!n1!n"},
+		{"EG", 29, "4!n4!n17!n"},
+		{"ES", 24, "4!n4!n1!n1!n10!n"},
+		{"FI", 18, "3!n11!n"},
+		{"FO", 18, "4!n9!n1!n"},
+		{"FR", 27, "5!n5!n11!c2!n"},
+		{"GB", 22, "4!a6!n8!n"},
+		{"GE", 22, "2!a16!n"},
+		{"GI", 23, "4!a15!c"},
+		{"GL", 18, "4!n9!n1!n"},
+		{"GR", 27, "3!n4!n16!c"},
+		{"GT", 28, "4!c20!c"},
+		{"HR", 21, "7!n10!n"},
+		{"HU", 28, "3!n4!n1!n15!n1!n"},
+		{"IE", 22, "4!a6!n8!n"},
+		{"IL", 23, "3!n3!n13!n"},
+		{"IQ", 23, "4!a3!n12!n"},
+		{"IS", 26, "4!n2!n6!n10!n"},
+		{"IT", 27, "1!a5!n5!n12!c"},
+		{"JO", 30, "4!a4!n18!c"},
+		{"KW", 30, "4!a22!c"},
+		{"KZ", 20, "3!n13!c"},
+		{"LB", 28, "4!n20!c"},
+		{"LC", 32, "4!a24!c"},
+		{"LI", 21, "5!n12!c"},
+		{"LT", 20, "5!n11!n"},
+		{"LU", 20, "3!n13!c"},
+		{"LV", 21, "4!a13!c"},
+		{"MC", 27, "5!n5!n11!c2!n"},
+		{"MD", 24, "2!c18!c"},
+		{"ME", 22, "3!n13!n2!n"},
+		{"MK", 19, "3!n10!c2!n"},
+		{"MR", 27, "5!n5!n11!n2!n"},
+		{"MT", 31, "4!a5!n18!c"},
+		{"MU", 30, "4!a2!n2!n12!n3!n3!a"},
+		{"NL", 18, "4!a10!n"},
+		{"NO", 15, "4!n6!n1!n"},
+		{"PK", 24, "4!a16!c"},
+		{"PL", 28, "8!n16!n"},
+		{"PS", 29, "4!a21!c"},
+		{"PT", 25, "4!n4!n11!n2!n"},
+		{"QA", 29, "4!a21!c"},
+		{"RO", 24, "4!a16!c"},
+		{"RS", 22, "3!n13!n2!n"},
+		{"SA", 24, "2!n18!c"},
+		{"SC", 31, "4!a2!n2!n16!n3!a"},
+		{"SE", 24, "3!n16!n1!n"},
+		{"SI", 19, "5!n8!n2!n"},
+		{"SK", 24, "4!n6!n10!n"},
+		{"SM", 27, "1!a5!n5!n12!c"},
+		{"ST", 25, "4!n4!n11!n2!n"},
+		{"SV", 28, "4!a20!n"},
+		{"TL", 23, "3!n14!n2!n"},
+		{"TN", 24, "2!n3!n13!n2!n"},
+		{"TR", 26, "5!n1!n16!c"},
+		{"UA", 29, "6!n19!c"},
+		{"VA", 22, "3!n15!n"},
+		{"VG", 24, "4!a16!n"},
+		{"XK", 20, "4!n10!n2!n"},
+	} {
+		if err := Register(s.c, s.n, s.b); err != nil {
+			panic(s.c + ": " + err.Error())
+		}
+	}
+}
+
+func parseBBAN(format string) ([]part, int, error) {
+	var parts []part
+	total := 0
+	for i := 0; i < len(format); {
+		n := 0
+		for i < len(format) && format[i] >= '0' && format[i] <= '9' {
+			n = n*10 + int(format[i]-'0')
+			i++
+		}
+		if n == 0 || i+1 >= len(format) || format[i] != '!' {
+			return nil, 0, ErrSpec
+		}
+		kind := format[i+1]
+		if kind != 'n' && kind != 'a' && kind != 'c' {
+			return nil, 0, ErrSpec
+		}
+		parts = append(parts, part{n, kind})
+		total += n
+		i += 2
+	}
+	return parts, total, nil
+}
+
+// Add (or replace) a country's IBAN specification
+func Register(country string, length int, bban string) error {
+	if len(country) != 2 || !isUpper(country[0]) || !isUpper(country[1]) {
+		return ErrSpec
+	}
+	parts, n, err := parseBBAN(bban)
+	if err != nil {
+		return err
+	}
+	if n+4 != length {
+		return ErrSpec
+	}
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	registry[country] = &Spec{Country: country, Length: length, BBAN: bban, parts: parts}
+	return nil
+}
+
+// The specification for a country, if registered
+func Lookup(country string) (Spec, bool) {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	s, ok := registry[country]
+	if !ok {
+		return Spec{}, false
+	}
+	return *s, true
+}
+
+// Check a BBAN matches the structure, returns the index of the first
+// mismatch or -1. The length must already match
+func (s *Spec) mismatch(bban string) int {
+	i := 0
+	for _, p := range s.parts {
+		for j := 0; j < p.n; j++ {
+			b := bban[i]
+			ok := false
+			switch p.kind {
+			case 'n':
+				ok = isDigit(b)
+			case 'a':
+				ok = isUpper(b)
+			case 'c':
+				ok = isDigit(b) || isUpper(b)
+			}
+			if !ok {
+				return i
+			}
+			i++
+		}
+	}
+	return -1
+}
+
+func isDigit(b byte) bool { return b >= '0' && b <= '9' }
+func isUpper(b byte) bool { return b >= 'A' && b <= 'Z' }
+func isLower(b byte) bool { return b >= 'a' && b <= 'z' }
@@ -0,0 +1,157 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+// The Internet checksum: the ones' complement of the ones' complement sum of
+// big endian 16bit words
+package inet
+
+//https://datatracker.ietf.org/doc/html/rfc1071
+//https://datatracker.ietf.org/doc/html/rfc1624 (incremental update)
+//https://datatracker.ietf.org/doc/html/rfc768 (UDP pseudo header)
+//https://datatracker.ietf.org/doc/html/rfc793#section-3.1 (TCP pseudo header)
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/bits"
+	"net"
+
+	"github.com/gnabgib/gnablib-go/checksum"
+)
+
+// The address isn't an IPv4 address
+var ErrNotIPv4 = errors.New("not an IPv4 address")
+
+const size = 2
+
+// The sum is kept in 64bits, since 2^16=1 (mod 2^16-1) a 64bit end-around
+// carry sum folds to the same 16bit ones' complement sum
+type digest struct {
+	seed uint64 //Initial sum (restored by Reset)
+	s    uint64
+	part byte //A trailing byte waiting for the rest of its word
+	odd  bool
+}
+
+// A new Hash16 for computing the Internet checksum
+func New() checksum.Hash16 {
+	return new(digest)
+}
+
+// A new Hash16 for computing the Internet checksum, starting from a ones'
+// complement `sum` of prior data (eg. from PseudoHeaderSum), Reset returns to
+// this sum
+func NewWithSum(sum uint16) checksum.Hash16 {
+	return &digest{seed: uint64(sum), s: uint64(sum)}
+}
+
+// The Internet checksum of p
+func Checksum(p []byte) uint16 {
+	var d digest
+	d.update(p)
+	return d.Sum16()
+}
+
+func (d *digest) update(p []byte) {
+	s := d.s
+	var c uint64
+	//Complete a word started in a prior write
+	if d.odd && len(p) > 0 {
+		s, c = bits.Add64(s, uint64(d.part)<<8|uint64(p[0]), 0)
+		s += c
+		p = p[1:]
+		d.odd = false
+	}
+	for len(p) >= 32 {
+		s, c = bits.Add64(s, binary.BigEndian.Uint64(p), 0)
+		s, c = bits.Add64(s, binary.BigEndian.Uint64(p[8:]), c)
+		s, c = bits.Add64(s, binary.BigEndian.Uint64(p[16:]), c)
+		s, c = bits.Add64(s, binary.BigEndian.Uint64(p[24:]), c)
+		s += c
+		p = p[32:]
+	}
+	for len(p) >= 8 {
+		s, c = bits.Add64(s, binary.BigEndian.Uint64(p), 0)
+		s += c
+		p = p[8:]
+	}
+	for len(p) >= 2 {
+		s, c = bits.Add64(s, uint64(binary.BigEndian.Uint16(p)), 0)
+		s += c
+		p = p[2:]
+	}
+	if len(p) > 0 {
+		d.part = p[0]
+		d.odd = true
+	}
+	d.s = s
+}
+
+// Fold a 64bit end-around carry sum to 16bits
+func fold(s uint64) uint16 {
+	s = s>>32 + s&0xffffffff
+	s = s>>32 + s&0xffffffff
+	s = s>>16 + s&0xffff
+	s = s>>16 + s&0xffff
+	return uint16(s)
+}
+
+func (d *digest) Write(p []byte) (n int, err error) {
+	d.update(p)
+	return len(p), nil
+}
+
+func (d *digest) Sum(in []byte) []byte {
+	s := d.Sum16()
+	return append(in, byte(s>>8), byte(s))
+}
+
+func (d *digest) Reset() { *d = digest{seed: d.seed, s: d.seed} }
+
+func (d *digest) Size() int { return size }
+
+func (d *digest) BlockSize() int { return size }
+
+// A trailing odd byte is padded with zero (RFC1071)
+func (d *digest) Sum16() uint16 {
+	s := d.s
+	if d.odd {
+		var c uint64
+		s, c = bits.Add64(s, uint64(d.part)<<8, 0)
+		s += c
+	}
+	return ^fold(s)
+}
+
+// Ones' complement addition
+func add(a, b uint16) uint16 {
+	s := uint32(a) + uint32(b)
+	return uint16(s>>16 + s&0xffff)
+}
+
+// Update a checksum when a 16bit word changes from `oldWord` to `newWord`
+// without summing all the data again (RFC1624 eqn 3: HC' = ~(~HC + ~m + m'))
+func Update(oldChecksum, oldWord, newWord uint16) uint16 {
+	return ^add(add(^oldChecksum, ^oldWord), newWord)
+}
+
+// Update a checksum when a 32bit value (eg. an IPv4 address) changes from
+// `oldValue` to `newValue`
+func Update32(oldChecksum uint16, oldValue, newValue uint32) uint16 {
+	c := Update(oldChecksum, uint16(oldValue>>16), uint16(newValue>>16))
+	return Update(c, uint16(oldValue), uint16(newValue))
+}
+
+// The ones' complement sum (not complemented) of the TCP/UDP IPv4 pseudo
+// header: source and destination address, protocol (6 for TCP, 17 for UDP) and
+// the TCP/UDP length (header plus data). Use it to seed NewWithSum before
+// writing the segment. Returns ErrNotIPv4 if either address isn't IPv4
+func PseudoHeaderSum(src, dst net.IP, proto uint8, length uint16) (uint16, error) {
+	s4, d4 := src.To4(), dst.To4()
+	if s4 == nil || d4 == nil {
+		return 0, ErrNotIPv4
+	}
+	s := uint64(binary.BigEndian.Uint32(s4)) + uint64(binary.BigEndian.Uint32(d4)) +
+		uint64(proto) + uint64(length)
+	return fold(s), nil
+}
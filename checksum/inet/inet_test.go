@@ -0,0 +1,134 @@
+package inet
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"hash"
+	"math/rand"
+	"net"
+	"testing"
+
+	"github.com/gnabgib/gnablib-go/checksum/sum"
+	"github.com/gnabgib/gnablib-go/endian"
+	"github.com/gnabgib/gnablib-go/test"
+)
+
+var inetTests = []struct {
+	s string
+	c uint16
+}{
+	//RFC1071 (section 3) example, sum ddf2
+	{"\x00\x01\xf2\x03\xf4\xf5\xf6\xf7", 0x220d},
+	//No published vector, regression only
+	{"", 0xffff},
+	{"a", 0x9eff},
+	{"ab", 0x9e9d},
+	{"abc", 0x3b9d},
+	{"abcde", 0xd638},
+	{"123456789", 0xf62a},
+	{"gnabgib", 0x6dc5},
+	{"\xff\xff\xff\xff\xff", 0x00ff},
+	{"foo bar baz٪☃🍣", 0xb709},
+}
+
+func TestInet(t *testing.T) {
+	for _, rec := range inetTests {
+		d := New()
+		d.Write([]byte(rec.s))
+		if found := d.Sum16(); found != rec.c {
+			t.Errorf("Hashing %q expecting %x, got %x", rec.s, rec.c, found)
+		}
+		if found := Checksum([]byte(rec.s)); found != rec.c {
+			t.Errorf("Checksum %q expecting %x, got %x", rec.s, rec.c, found)
+		}
+	}
+}
+
+func TestInetConformance(t *testing.T) {
+	vectors := test.SumVectors(len(inetTests), 2, func(i int) (string, uint64) {
+		return inetTests[i].s, uint64(inetTests[i].c)
+	})
+	test.HashConformance(t, func() hash.Hash { return New() }, vectors)
+}
+
+func TestInetMatchesWordSum(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1071))
+	for l := 0; l < 300; l++ {
+		b := make([]byte, l)
+		rnd.Read(b)
+		ref := sum.NewOnesComplement16(endian.Network)
+		ref.Write(b)
+		if found, expect := Checksum(b), ref.Sum16(); found != expect {
+			t.Errorf("Length %d expecting %x, got %x", l, expect, found)
+		}
+	}
+	//Long runs of 0xff push the 64bit sum through many carries
+	b := make([]byte, 1<<16)
+	for i := range b {
+		b[i] = 0xff
+	}
+	if found := Checksum(b); found != 0 {
+		t.Errorf("Expecting 0, got %x", found)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	//RFC1624 (section 4) example
+	if found := Update(0xdd2f, 0x5555, 0x3285); found != 0 {
+		t.Errorf("Expecting 0, got %x", found)
+	}
+	rnd := rand.New(rand.NewSource(1624))
+	b := make([]byte, 40)
+	rnd.Read(b)
+	c := Checksum(b)
+	for i := 0; i < 1000; i++ {
+		at := rnd.Intn(len(b)/2) * 2
+		old := binary.BigEndian.Uint16(b[at:])
+		nw := uint16(rnd.Intn(0x10000))
+		binary.BigEndian.PutUint16(b[at:], nw)
+		c = Update(c, old, nw)
+		if expect := Checksum(b); c != expect {
+			t.Fatalf("Update %d expecting %x, got %x", i, expect, c)
+		}
+	}
+	//Rewrite an address (NAT)
+	at := 12
+	old := binary.BigEndian.Uint32(b[at:])
+	binary.BigEndian.PutUint32(b[at:], 0xc0a80001)
+	if found, expect := Update32(c, old, 0xc0a80001), Checksum(b); found != expect {
+		t.Errorf("Update32 expecting %x, got %x", expect, found)
+	}
+}
+
+func TestPseudoHeader(t *testing.T) {
+	udp, _ := hex.DecodeString("30390035000f0000676e6162676962")
+	src, dst := net.ParseIP("192.168.0.1"), net.ParseIP("192.168.0.199")
+	ph, err := PseudoHeaderSum(src, dst, 17, uint16(len(udp)))
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if ph != 0x8239 {
+		t.Errorf("Expecting 8239, got %x", ph)
+	}
+	d := NewWithSum(ph)
+	d.Write(udp)
+	c := d.Sum16()
+	if c != 0xbb0e {
+		t.Errorf("Expecting bb0e, got %x", c)
+	}
+	//With the checksum in place, the sum verifies as 0
+	binary.BigEndian.PutUint16(udp[6:], c)
+	d = NewWithSum(ph)
+	d.Write(udp)
+	if found := d.Sum16(); found != 0 {
+		t.Errorf("Expecting 0, got %x", found)
+	}
+	//Reset keeps the seed
+	d.Reset()
+	if found := d.Sum16(); found != ^ph {
+		t.Errorf("Expecting %x, got %x", ^ph, found)
+	}
+	if _, err := PseudoHeaderSum(net.ParseIP("::1"), dst, 6, 20); err != ErrNotIPv4 {
+		t.Errorf("Expecting ErrNotIPv4, got %v", err)
+	}
+}
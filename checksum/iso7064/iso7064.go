@@ -0,0 +1,195 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+// ISO/IEC 7064 check character systems
+package iso7064
+
+//https://www.iso.org/standard/31531.html
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gnabgib/gnablib-go/checksum"
+)
+
+// Too few characters to compute (1) or validate (1 + check length) a check
+var ErrTooShort = errors.New("Too few characters for ISO 7064")
+
+// The check characters don't match the content
+var ErrCheckFailed = errors.New("ISO 7064 check failed")
+
+// A check character system
+type System interface {
+	// Compute the check character(s) for s
+	Compute(s string) (string, error)
+	// Check the trailing check character(s) of s are correct
+	Validate(s string) error
+	// Append the check character(s) to s
+	Append(s string) (string, error)
+}
+
+const (
+	digits       = "0123456789"
+	letters      = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	alphanumeric = digits + letters
+)
+
+// Pure system, check characters ≡ 1 - data*radix^k (mod M)
+type Pure struct {
+	Name       string
+	Modulus    int
+	Radix      int
+	Alphabet   string //Data characters, each has the value of its index
+	CheckChars string //Check characters, each has the value of its index
+	CheckLen   int    //Number of check characters (1 or 2)
+}
+
+var (
+	Mod11_2    = &Pure{"MOD 11-2", 11, 2, digits, digits + "X", 1}
+	Mod37_2    = &Pure{"MOD 37-2", 37, 2, alphanumeric, alphanumeric + "*", 1}
+	Mod97_10   = &Pure{"MOD 97-10", 97, 10, digits, digits, 2}
+	Mod661_26  = &Pure{"MOD 661-26", 661, 26, letters, letters, 2}
+	Mod1271_36 = &Pure{"MOD 1271-36", 1271, 36, alphanumeric, alphanumeric, 2}
+)
+
+// Hybrid system, (M+1, M) arithmetic over an alphabet of M characters
+type Hybrid struct {
+	Name     string
+	Alphabet string //Each character has the value of its index, M is the length
+}
+
+var (
+	Mod11_10 = &Hybrid{"MOD 11,10", digits}
+	Mod37_36 = &Hybrid{"MOD 37,36", alphanumeric}
+)
+
+// Upper case letters, skip separators and find the value of each character in
+// `alphabet`. The last `checkLen` characters are instead valued by `checkChars`
+func values(s, alphabet, checkChars string, checkLen int) ([]int, error) {
+	type sym struct {
+		b  byte
+		at int
+	}
+	syms := make([]sym, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		b := checksum.Upper(s[i])
+		if checksum.IsSeparator(b) && strings.IndexByte(checkChars, b) < 0 &&
+			strings.IndexByte(alphabet, b) < 0 {
+			continue
+		}
+		syms = append(syms, sym{b, i})
+	}
+	ret := make([]int, len(syms))
+	for i, sy := range syms {
+		set := alphabet
+		if i >= len(syms)-checkLen {
+			set = checkChars
+		}
+		v := strings.IndexByte(set, sy.b)
+		if v < 0 {
+			return nil, checksum.InvalidCharAt("ISO 7064", s[sy.at], sy.at)
+		}
+		ret[i] = v
+	}
+	return ret, nil
+}
+
+func (p *Pure) Compute(s string) (string, error) {
+	vals, err := values(s, p.Alphabet, "", 0)
+	if err != nil {
+		return "", err
+	}
+	if len(vals) < 1 {
+		return "", ErrTooShort
+	}
+	m := 0
+	for _, v := range vals {
+		m = (m*p.Radix + v) % p.Modulus
+	}
+	for i := 0; i < p.CheckLen; i++ {
+		m = m * p.Radix % p.Modulus
+	}
+	c := (p.Modulus + 1 - m) % p.Modulus
+	if p.CheckLen == 1 {
+		return string(p.CheckChars[c]), nil
+	}
+	return string([]byte{p.CheckChars[c/p.Radix], p.CheckChars[c%p.Radix]}), nil
+}
+
+func (p *Pure) Validate(s string) error {
+	vals, err := values(s, p.Alphabet, p.CheckChars, p.CheckLen)
+	if err != nil {
+		return err
+	}
+	if len(vals) <= p.CheckLen {
+		return ErrTooShort
+	}
+	//Check characters are worth their index, like data: so the whole value ≡ 1
+	m := 0
+	for _, v := range vals {
+		m = (m*p.Radix + v) % p.Modulus
+	}
+	if m != 1 {
+		return ErrCheckFailed
+	}
+	return nil
+}
+
+func (p *Pure) Append(s string) (string, error) {
+	c, err := p.Compute(s)
+	if err != nil {
+		return "", err
+	}
+	return s + c, nil
+}
+
+// The running product after processing vals
+func (h *Hybrid) product(vals []int) int {
+	m := len(h.Alphabet)
+	p := m
+	for _, v := range vals {
+		s := (p + v) % m
+		if s == 0 {
+			s = m
+		}
+		p = s * 2 % (m + 1)
+	}
+	return p
+}
+
+func (h *Hybrid) Compute(s string) (string, error) {
+	vals, err := values(s, h.Alphabet, "", 0)
+	if err != nil {
+		return "", err
+	}
+	if len(vals) < 1 {
+		return "", ErrTooShort
+	}
+	m := len(h.Alphabet)
+	return string(h.Alphabet[(m+1-h.product(vals))%m]), nil
+}
+
+func (h *Hybrid) Validate(s string) error {
+	vals, err := values(s, h.Alphabet, h.Alphabet, 1)
+	if err != nil {
+		return err
+	}
+	if len(vals) < 2 {
+		return ErrTooShort
+	}
+	m := len(h.Alphabet)
+	last := len(vals) - 1
+	if (h.product(vals[:last])+vals[last])%m != 1 {
+		return ErrCheckFailed
+	}
+	return nil
+}
+
+func (h *Hybrid) Append(s string) (string, error) {
+	c, err := h.Compute(s)
+	if err != nil {
+		return "", err
+	}
+	return s + c, nil
+}
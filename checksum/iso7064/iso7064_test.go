@@ -0,0 +1,122 @@
+package iso7064
+
+import (
+	"testing"
+
+	"github.com/gnabgib/gnablib-go/checksum"
+)
+
+var computeTests = []struct {
+	sys   System
+	in    string
+	check string
+}{
+	//ISO 7064 examples
+	{Mod11_2, "079", "X"},
+	{Mod11_2, "0794", "0"},
+	{Mod37_2, "G123498654321", "H"},
+	{Mod97_10, "794", "44"},
+	{Mod1271_36, "ISO79", "3W"},
+	{Mod11_10, "0794", "5"},
+	//ORCID iDs use MOD 11-2
+	{Mod11_2, "0000-0002-1825-009", "7"},
+	//No published vector, regression only
+	{Mod661_26, "ALPHA", "KN"},
+	{Mod37_36, "A12425GH", "D"},
+	{Mod1271_36, "iso79", "3W"},
+}
+
+func TestCompute(t *testing.T) {
+	for _, rec := range computeTests {
+		found, err := rec.sys.Compute(rec.in)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", rec.in, err)
+			continue
+		}
+		if found != rec.check {
+			t.Errorf("%s: expecting %s, got %s", rec.in, rec.check, found)
+		}
+		full, _ := rec.sys.Append(rec.in)
+		if full != rec.in+rec.check {
+			t.Errorf("%s: expecting %s, got %s", rec.in, rec.in+rec.check, full)
+		}
+		if err := rec.sys.Validate(full); err != nil {
+			t.Errorf("%s: unexpected error %v", full, err)
+		}
+	}
+}
+
+var allSystems = []System{Mod11_2, Mod37_2, Mod97_10, Mod661_26, Mod1271_36, Mod11_10, Mod37_36}
+
+// Every single substitution and adjacent transposition of data is detected
+func TestDetection(t *testing.T) {
+	payloads := map[System]string{
+		Mod11_2: "12345678", Mod97_10: "12345678", Mod11_10: "12345678",
+		Mod37_2: "A1B2C3D4", Mod1271_36: "A1B2C3D4", Mod37_36: "A1B2C3D4",
+		Mod661_26: "ABCDEFGH",
+	}
+	for _, sys := range allSystems {
+		var alphabet string
+		switch s := sys.(type) {
+		case *Pure:
+			alphabet = s.Alphabet
+		case *Hybrid:
+			alphabet = s.Alphabet
+		}
+		full, _ := sys.Append(payloads[sys])
+		b := []byte(full)
+		n := len(payloads[sys])
+		for i := 0; i < n; i++ {
+			orig := b[i]
+			for j := 0; j < len(alphabet); j++ {
+				if alphabet[j] == orig {
+					continue
+				}
+				b[i] = alphabet[j]
+				if sys.Validate(string(b)) == nil {
+					t.Errorf("%s: single error %s not detected", full, b)
+				}
+			}
+			b[i] = orig
+		}
+		for i := 0; i < n-1; i++ {
+			b[i], b[i+1] = b[i+1], b[i]
+			if sys.Validate(string(b)) == nil {
+				t.Errorf("%s: transposition %s not detected", full, b)
+			}
+			b[i], b[i+1] = b[i+1], b[i]
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		sys System
+		in  string
+		err error
+	}{
+		{Mod11_2, "079Y", checksum.InvalidCharAt("ISO 7064", 'Y', 3)},
+		{Mod11_2, "07X0", checksum.InvalidCharAt("ISO 7064", 'X', 2)},
+		{Mod11_2, "0791", ErrCheckFailed},
+		{Mod11_2, "X", ErrTooShort},
+		{Mod97_10, "44", ErrTooShort},
+		{Mod97_10, "79445", ErrCheckFailed},
+		{Mod661_26, "ALPHA1", checksum.InvalidCharAt("ISO 7064", '1', 5)},
+		{Mod11_10, "07946", ErrCheckFailed},
+		{Mod11_10, "5", ErrTooShort},
+		{Mod37_36, "A12425GH*", checksum.InvalidCharAt("ISO 7064", '*', 8)},
+	}
+	for _, rec := range tests {
+		if err := rec.sys.Validate(rec.in); err != rec.err {
+			t.Errorf("%s: expecting %v, got %v", rec.in, rec.err, err)
+		}
+	}
+	for _, sys := range allSystems {
+		if _, err := sys.Compute(" "); err != ErrTooShort {
+			t.Errorf("%T: expecting ErrTooShort, got %v", sys, err)
+		}
+		if _, err := sys.Append("!"); err != checksum.InvalidCharAt("ISO 7064", '!', 0) {
+			t.Errorf("%T: expecting invalid !, got %v", sys, err)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+package lrc
+
+//Two-dimensional parity: each 7bit character carries a parity bit (VRC) in bit 7,
+//and the block ends with a character holding the XOR of the columns (LRC) with
+//its own parity bit. This column XOR "LRC" isn't the two's complement sum that
+//New computes. A single bit error fails exactly one row and at most one
+//column, which locates it
+//https://en.wikipedia.org/wiki/Multidimensional_parity-check_code
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+// Parity sense of the per character (VRC) bit
+type Parity byte
+
+const (
+	Even Parity = iota
+	Odd
+)
+
+// The column holding the parity bit
+const ParityCol = 7
+
+// Block data must be 7bit (the top bit carries parity)
+var ErrSevenBit = errors.New("block data must be 7 bit")
+
+// The block is too short to hold the LRC character
+var ErrBlockLength = errors.New("block must include an LRC character")
+
+// More than a single bit is wrong (use errors.Is to test for this)
+var ErrUncorrectable = errors.New("uncorrectable parity error")
+
+// The rows (characters, the last is the LRC) and columns (bits 0-6) that
+// failed parity, when the pattern can't be corrected
+type BlockError struct {
+	Rows []int
+	Cols []int
+}
+
+func (e BlockError) Error() string {
+	return fmt.Sprintf("Uncorrectable parity error: rows %v, columns %v", e.Rows, e.Cols)
+}
+
+func (e BlockError) Unwrap() error { return ErrUncorrectable }
+
+// Bit 7 set so that b has the requested parity
+func withParity(b byte, p Parity) byte {
+	b &= 0x7f
+	if bits.OnesCount8(b)&1 != int(p) {
+		b |= 0x80
+	}
+	return b
+}
+
+// Add a parity bit to each 7bit character of data, and append the LRC character.
+// The block's LRC is the XOR of each column (with its own parity bit), unlike
+// New which is the two's complement of the byte sum (the Modbus ASCII LRC)
+func EncodeBlock(data []byte, p Parity) ([]byte, error) {
+	ret := make([]byte, len(data)+1)
+	var lrc byte
+	for i, b := range data {
+		if b > 0x7f {
+			return nil, ErrSevenBit
+		}
+		lrc ^= b
+		ret[i] = withParity(b, p)
+	}
+	ret[len(data)] = withParity(lrc, p)
+	return ret, nil
+}
+
+// The rows and columns of an encoded block that fail parity, both are empty
+// when the block is good
+func CheckBlock(block []byte, p Parity) (rows, cols []int) {
+	var col byte
+	for i, b := range block {
+		if bits.OnesCount8(b)&1 != int(p) {
+			rows = append(rows, i)
+		}
+		col ^= b
+	}
+	for j := 0; j < ParityCol; j++ {
+		if col>>j&1 != 0 {
+			cols = append(cols, j)
+		}
+	}
+	return
+}
+
+// Correct a single bit error in place, returning its row and column (ParityCol
+// for a parity bit), or -1,-1 when the block is good. Returns ErrBlockLength, or
+// a BlockError when the failing rows/columns aren't a single bit error. Four
+// errors on the corners of a rectangle aren't detected
+func CorrectBlock(block []byte, p Parity) (row, col int, err error) {
+	if len(block) < 1 {
+		return -1, -1, ErrBlockLength
+	}
+	rows, cols := CheckBlock(block, p)
+	switch {
+	case len(rows) == 0 && len(cols) == 0:
+		return -1, -1, nil
+	case len(rows) == 1 && len(cols) == 0:
+		//A parity bit flipped
+		row, col = rows[0], ParityCol
+	case len(rows) == 1 && len(cols) == 1:
+		row, col = rows[0], cols[0]
+	default:
+		return -1, -1, BlockError{rows, cols}
+	}
+	block[row] ^= 1 << col
+	return row, col, nil
+}
+
+// Correct a block in place (see CorrectBlock) and return the 7bit data, which
+// shares storage with block
+func DecodeBlock(block []byte, p Parity) ([]byte, error) {
+	if _, _, err := CorrectBlock(block, p); err != nil {
+		return nil, err
+	}
+	data := block[:len(block)-1]
+	for i := range data {
+		data[i] &= 0x7f
+	}
+	return data, nil
+}
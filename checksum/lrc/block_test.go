@@ -0,0 +1,105 @@
+package lrc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestEncodeBlock(t *testing.T) {
+	tests := []struct {
+		data   string
+		p      Parity
+		expect string
+	}{
+		{"HELLO", Even, "48c5cccccf42"},
+		{"HELLO", Odd, "c8454c4c4fc2"},
+		{"", Even, "00"},
+		{"", Odd, "80"},
+	}
+	for _, rec := range tests {
+		found, err := EncodeBlock([]byte(rec.data), rec.p)
+		if err != nil {
+			t.Errorf("EncodeBlock(%q) unexpected error %v", rec.data, err)
+			continue
+		}
+		if fmt.Sprintf("%x", found) != rec.expect {
+			t.Errorf("EncodeBlock(%q, %d) expecting %s, got %x", rec.data, rec.p, rec.expect, found)
+		}
+	}
+	if _, err := EncodeBlock([]byte{'a', 0x80}, Even); err != ErrSevenBit {
+		t.Errorf("EncodeBlock expecting %v, got %v", ErrSevenBit, err)
+	}
+}
+
+func TestCorrectBlockSingle(t *testing.T) {
+	data := []byte("The quick brown fox")
+	for _, p := range []Parity{Even, Odd} {
+		good, _ := EncodeBlock(data, p)
+		for row := range good {
+			for col := 0; col < 8; col++ {
+				block := append([]byte{}, good...)
+				block[row] ^= 1 << col
+				r, c, err := CorrectBlock(block, p)
+				if err != nil || r != row || c != col || !bytes.Equal(block, good) {
+					t.Errorf("CorrectBlock flip %d,%d expecting correction, got %d,%d %v", row, col, r, c, err)
+				}
+			}
+		}
+		r, c, err := CorrectBlock(good, p)
+		if r != -1 || c != -1 || err != nil {
+			t.Errorf("CorrectBlock good block expecting -1,-1, got %d,%d %v", r, c, err)
+		}
+	}
+}
+
+func TestCorrectBlockDouble(t *testing.T) {
+	good, _ := EncodeBlock([]byte("gnabgib"), Even)
+	bitCount := len(good) * 8
+	for a := 0; a < bitCount; a++ {
+		for b := a + 1; b < bitCount; b++ {
+			block := append([]byte{}, good...)
+			block[a/8] ^= 1 << (a % 8)
+			block[b/8] ^= 1 << (b % 8)
+			_, _, err := CorrectBlock(block, Even)
+			if !errors.Is(err, ErrUncorrectable) {
+				t.Errorf("CorrectBlock flips %d,%d expecting %v, got %v", a, b, ErrUncorrectable, err)
+			}
+		}
+	}
+}
+
+func TestCheckBlock(t *testing.T) {
+	block, _ := EncodeBlock([]byte("HELLO"), Odd)
+	//Two bits in the same row: columns fail, the row doesn't
+	block[1] ^= 0x05
+	rows, cols := CheckBlock(block, Odd)
+	if len(rows) != 0 || fmt.Sprint(cols) != "[0 2]" {
+		t.Errorf("CheckBlock expecting [] [0 2], got %v %v", rows, cols)
+	}
+	_, _, err := CorrectBlock(block, Odd)
+	var be BlockError
+	if !errors.As(err, &be) || fmt.Sprint(be.Cols) != "[0 2]" {
+		t.Errorf("CorrectBlock expecting BlockError, got %v", err)
+	}
+	//Four corners of a rectangle go undetected
+	block, _ = EncodeBlock([]byte("HELLO"), Odd)
+	block[0] ^= 0x11
+	block[3] ^= 0x11
+	if rows, cols := CheckBlock(block, Odd); len(rows)+len(cols) != 0 {
+		t.Errorf("CheckBlock rectangle expecting no failures, got %v %v", rows, cols)
+	}
+}
+
+func TestDecodeBlock(t *testing.T) {
+	block, _ := EncodeBlock([]byte("HELLO"), Even)
+	block[2] ^= 0x08
+	found, err := DecodeBlock(block, Even)
+	if err != nil || string(found) != "HELLO" {
+		t.Errorf("DecodeBlock expecting HELLO, got %q %v", found, err)
+	}
+	if _, err := DecodeBlock(nil, Even); err != ErrBlockLength {
+		t.Errorf("DecodeBlock expecting %v, got %v", ErrBlockLength, err)
+	}
+}
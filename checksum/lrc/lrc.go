@@ -7,6 +7,7 @@ package lrc
 
 import "github.com/gnabgib/gnablib-go/checksum"
 
+// Running sum of the data, the checksum is the two's complement of this
 type digest uint8
 
 // A new Hash8 for computing the Longitudinal redundancy check checksum
@@ -21,7 +22,7 @@ func (d *digest) update(p []byte) {
 	for i:=0;i<len(p);i++ {
 		dig+=p[i]
 	}
-	*d=digest(^dig+1)
+	*d=digest(dig)
 }
 
 func (d *digest) Write(p []byte) (n int, err error) {
@@ -29,7 +30,7 @@ func (d *digest) Write(p []byte) (n int, err error) {
 	return len(p), nil
 }
 
-func (d *digest) Sum(in []byte) []byte { return append(in, byte(*d)) }
+func (d *digest) Sum(in []byte) []byte { return append(in, d.Sum8()) }
 
 func (d *digest) Reset() { *d = 0 }
 
@@ -37,4 +38,4 @@ func (d *digest) Size() int { return 1 }
 
 func (d *digest) BlockSize() int { return 1 }
 
-func (d *digest) Sum8() uint8 { return byte(*d) }
+func (d *digest) Sum8() uint8 { return ^byte(*d) + 1 }
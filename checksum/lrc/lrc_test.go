@@ -1,7 +1,10 @@
 package lrc
 
 import (
+	"hash"
 	"testing"
+
+	"github.com/gnabgib/gnablib-go/test"
 )
 
 var lrcTests = []struct {
@@ -46,3 +49,10 @@ func TestLrc(t *testing.T) {
 		}
 	}
 }
+
+func TestLrcConformance(t *testing.T) {
+	vectors := test.SumVectors(len(lrcTests), 1, func(i int) (string, uint64) {
+		return lrcTests[i].s, uint64(lrcTests[i].c)
+	})
+	test.HashConformance(t, func() hash.Hash { return New() }, vectors)
+}
@@ -0,0 +1,254 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+// Identifiers that use a Luhn check: payment cards, IMEI, SIN, NPI
+package ident
+
+//https://en.wikipedia.org/wiki/Payment_card_number#Issuer_identification_number_(IIN)
+
+import (
+	"errors"
+	"io"
+
+	"github.com/gnabgib/gnablib-go/checksum"
+	"github.com/gnabgib/gnablib-go/checksum/luhn"
+)
+
+// The identifier is the wrong length
+var ErrLength = errors.New("Wrong length")
+
+// The card number doesn't start with a known issuer identification number
+var ErrBrand = errors.New("Unknown card brand")
+
+// The identifier starts with digits that aren't allocated
+var ErrPrefix = errors.New("Invalid prefix")
+
+// Payment card brand (scheme)
+type Brand int
+
+const (
+	Unknown Brand = iota
+	Visa
+	Mastercard
+	Amex
+	Discover
+	JCB
+	UnionPay
+)
+
+func (b Brand) String() string {
+	switch b {
+	case Visa:
+		return "Visa"
+	case Mastercard:
+		return "Mastercard"
+	case Amex:
+		return "American Express"
+	case Discover:
+		return "Discover"
+	case JCB:
+		return "JCB"
+	case UnionPay:
+		return "UnionPay"
+	}
+	return "Unknown"
+}
+
+// The first `n` digits are in lo-hi
+type iinRange struct {
+	lo, hi, n int
+}
+
+type brandSpec struct {
+	brand   Brand
+	ranges  []iinRange
+	lengths []int //Allowed PAN lengths, the first is the most common
+}
+
+// In match order, UnionPay co-branded Discover cards (622126-622925) are Discover
+var brands = []brandSpec{
+	{Visa, []iinRange{{4, 4, 1}}, []int{16, 13, 19}},
+	{Mastercard, []iinRange{{51, 55, 2}, {2221, 2720, 4}}, []int{16}},
+	{Amex, []iinRange{{34, 34, 2}, {37, 37, 2}}, []int{15}},
+	{Discover, []iinRange{{6011, 6011, 4}, {644, 649, 3}, {65, 65, 2}, {622126, 622925, 6}}, []int{16, 17, 18, 19}},
+	{JCB, []iinRange{{3528, 3589, 4}}, []int{16, 17, 18, 19}},
+	{UnionPay, []iinRange{{62, 62, 2}}, []int{16, 17, 18, 19}},
+}
+
+// Strip spaces and dashes, returns a checksum.InvalidCharError for
+// anything else that isn't a digit
+func digits(s string) (string, error) {
+	ret := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		b := s[i]
+		switch {
+		case b >= '0' && b <= '9':
+			ret = append(ret, b)
+		case checksum.IsSeparator(b):
+		default:
+			return "", checksum.InvalidCharAt("Luhn", b, i)
+		}
+	}
+	return string(ret), nil
+}
+
+// Value of the first n digits of s (s must be long enough)
+func prefix(s string, n int) int {
+	v := 0
+	for i := 0; i < n; i++ {
+		v = v*10 + int(s[i]-'0')
+	}
+	return v
+}
+
+func specFor(pan string) *brandSpec {
+	for i := range brands {
+		for _, r := range brands[i].ranges {
+			if len(pan) < r.n {
+				continue
+			}
+			if p := prefix(pan, r.n); p >= r.lo && p <= r.hi {
+				return &brands[i]
+			}
+		}
+	}
+	return nil
+}
+
+// The brand of a card number from its leading digits (the length and check digit
+// aren't considered), spaces and dashes are ignored
+func Classify(pan string) Brand {
+	d, err := digits(pan)
+	if err != nil {
+		return Unknown
+	}
+	if s := specFor(d); s != nil {
+		return s.brand
+	}
+	return Unknown
+}
+
+// Validate a card number (PAN): the brand must be known, the length allowed for
+// the brand and the Luhn check digit correct. Spaces and dashes are ignored
+func ValidatePAN(pan string) (Brand, error) {
+	d, err := digits(pan)
+	if err != nil {
+		return Unknown, err
+	}
+	s := specFor(d)
+	if s == nil {
+		return Unknown, ErrBrand
+	}
+	ok := false
+	for _, l := range s.lengths {
+		ok = ok || len(d) == l
+	}
+	if !ok {
+		return s.brand, ErrLength
+	}
+	return s.brand, luhn.Validate(d)
+}
+
+// Mask all but the first 6 and last 4 digits of a card number with '*', spaces
+// and dashes are kept
+func Mask(pan string) string {
+	return MaskN(pan, 6, 4, '*')
+}
+
+// Replace all but the first `first` and last `last` digits with `mask`. Other
+// characters are kept. If there are too few digits they're all masked
+func MaskN(pan string, first, last int, mask byte) string {
+	n := 0
+	for i := 0; i < len(pan); i++ {
+		if pan[i] >= '0' && pan[i] <= '9' {
+			n++
+		}
+	}
+	all := n <= first+last
+	ret := []byte(pan)
+	idx := 0
+	for i, b := range ret {
+		if b < '0' || b > '9' {
+			continue
+		}
+		if all || (idx >= first && idx < n-last) {
+			ret[i] = mask
+		}
+		idx++
+	}
+	return string(ret)
+}
+
+// A uniformly random value in [0,n) from `rand`
+func randInt(rand io.Reader, n int) (int, error) {
+	//Rejection sampling over 32bit values avoids bias
+	limit := (1 << 32) / uint64(n) * uint64(n)
+	var b [4]byte
+	for {
+		if _, err := io.ReadFull(rand, b[:]); err != nil {
+			return 0, err
+		}
+		v := uint64(b[0])<<24 | uint64(b[1])<<16 | uint64(b[2])<<8 | uint64(b[3])
+		if v < limit {
+			return int(v % uint64(n)), nil
+		}
+	}
+}
+
+// Generate a valid test card number for the brand, using randomness from `rand`
+// (crypto/rand.Reader or a seeded math/rand for repeatable fixtures). A `length`
+// of 0 uses the brand's most common length
+func GeneratePAN(brand Brand, length int, rand io.Reader) (string, error) {
+	var s *brandSpec
+	for i := range brands {
+		if brands[i].brand == brand {
+			s = &brands[i]
+		}
+	}
+	if s == nil {
+		return "", ErrBrand
+	}
+	if length == 0 {
+		length = s.lengths[0]
+	}
+	ok := false
+	for _, l := range s.lengths {
+		ok = ok || length == l
+	}
+	if !ok {
+		return "", ErrLength
+	}
+	//Ranges overlap (UnionPay 62 includes Discover co-brands) so retry until the
+	//prefix matches the requested brand
+	for {
+		pan, err := generate(s, length, rand)
+		if err != nil || specFor(pan) == s {
+			return pan, err
+		}
+	}
+}
+
+func generate(s *brandSpec, length int, rand io.Reader) (string, error) {
+	ri, err := randInt(rand, len(s.ranges))
+	if err != nil {
+		return "", err
+	}
+	r := s.ranges[ri]
+	p, err := randInt(rand, r.hi-r.lo+1)
+	if err != nil {
+		return "", err
+	}
+	ret := make([]byte, r.n, length)
+	for i, v := r.n-1, p+r.lo; i >= 0; i, v = i-1, v/10 {
+		ret[i] = byte('0' + v%10)
+	}
+	for len(ret) < length-1 {
+		d, err := randInt(rand, 10)
+		if err != nil {
+			return "", err
+		}
+		ret = append(ret, byte('0'+d))
+	}
+	//Always succeeds, the content is all digits
+	return luhn.Append(string(ret))
+}
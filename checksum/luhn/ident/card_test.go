@@ -0,0 +1,136 @@
+package ident
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/gnabgib/gnablib-go/checksum"
+	"github.com/gnabgib/gnablib-go/checksum/luhn"
+)
+
+// Published test card numbers
+var panTests = []struct {
+	pan   string
+	brand Brand
+}{
+	{"4111111111111111", Visa},
+	{"4012 8888 8888 1881", Visa},
+	{"4222222222222", Visa},
+	{"5555555555554444", Mastercard},
+	{"5105-1051-0510-5100", Mastercard},
+	{"2223003122003222", Mastercard},
+	{"378282246310005", Amex},
+	{"371449635398431", Amex},
+	{"6011111111111117", Discover},
+	{"6011000990139424", Discover},
+	{"6450000000000002", Discover},
+	{"6221260000000000", Discover},
+	{"3530111333300000", JCB},
+	{"3566002020360505", JCB},
+	{"6200000000000005", UnionPay},
+	{"6205500000000000004", UnionPay},
+}
+
+func TestValidatePAN(t *testing.T) {
+	for _, rec := range panTests {
+		brand, err := ValidatePAN(rec.pan)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", rec.pan, err)
+		}
+		if brand != rec.brand {
+			t.Errorf("%s: expecting %v, got %v", rec.pan, rec.brand, brand)
+		}
+		if found := Classify(rec.pan); found != rec.brand {
+			t.Errorf("%s: classify expecting %v, got %v", rec.pan, rec.brand, found)
+		}
+	}
+}
+
+func TestValidatePAN_errors(t *testing.T) {
+	tests := []struct {
+		pan   string
+		brand Brand
+		err   error
+	}{
+		{"4111111111111112", Visa, luhn.ErrCheckFailed},
+		{"41111111111111", Visa, ErrLength},
+		{"5555555555554444" + "4", Mastercard, ErrLength},
+		{"2721000000000000", Unknown, ErrBrand},
+		{"1111111111111117", Unknown, ErrBrand},
+		{"4111.1111", Unknown, checksum.InvalidCharAt("Luhn", '.', 4)},
+		{"", Unknown, ErrBrand},
+	}
+	for _, rec := range tests {
+		brand, err := ValidatePAN(rec.pan)
+		if err != rec.err {
+			t.Errorf("%s: expecting %v, got %v", rec.pan, rec.err, err)
+		}
+		if brand != rec.brand {
+			t.Errorf("%s: expecting %v, got %v", rec.pan, rec.brand, brand)
+		}
+	}
+	if Classify("4x") != Unknown {
+		t.Errorf("Expecting unknown")
+	}
+}
+
+func TestBrandString(t *testing.T) {
+	for b, expect := range map[Brand]string{Visa: "Visa", Amex: "American Express", UnionPay: "UnionPay", Unknown: "Unknown", Brand(99): "Unknown"} {
+		if found := b.String(); found != expect {
+			t.Errorf("Expecting %s, got %s", expect, found)
+		}
+	}
+}
+
+func TestMask(t *testing.T) {
+	tests := []struct {
+		in, expect string
+	}{
+		{"4111111111111111", "411111******1111"},
+		{"4111 1111 1111 1111", "4111 11** **** 1111"},
+		{"378282246310005", "378282*****0005"},
+		{"4111111111", "**********"},
+		{"", ""},
+	}
+	for _, rec := range tests {
+		if found := Mask(rec.in); found != rec.expect {
+			t.Errorf("%q: expecting %q, got %q", rec.in, rec.expect, found)
+		}
+	}
+	if found := MaskN("4111-1111-1111-1111", 0, 4, 'X'); found != "XXXX-XXXX-XXXX-1111" {
+		t.Errorf("Expecting last 4, got %s", found)
+	}
+}
+
+func TestGeneratePAN(t *testing.T) {
+	rnd := rand.New(rand.NewSource(0x70616e))
+	for _, s := range brands {
+		for _, l := range append([]int{0}, s.lengths...) {
+			for i := 0; i < 50; i++ {
+				pan, err := GeneratePAN(s.brand, l, rnd)
+				if err != nil {
+					t.Fatalf("%v(%d): unexpected error %v", s.brand, l, err)
+				}
+				if l > 0 && len(pan) != l || l == 0 && len(pan) != s.lengths[0] {
+					t.Errorf("%v(%d): unexpected length %s", s.brand, l, pan)
+				}
+				brand, err := ValidatePAN(pan)
+				if err != nil || brand != s.brand {
+					t.Errorf("%v(%d): %s validated as %v, %v", s.brand, l, pan, brand, err)
+				}
+			}
+		}
+	}
+	//Seeded sources are repeatable
+	a, _ := GeneratePAN(Visa, 0, rand.New(rand.NewSource(1)))
+	b, _ := GeneratePAN(Visa, 0, rand.New(rand.NewSource(1)))
+	if a != b {
+		t.Errorf("Expecting repeatable, got %s and %s", a, b)
+	}
+	if _, err := GeneratePAN(Unknown, 0, rnd); err != ErrBrand {
+		t.Errorf("Expecting ErrBrand, got %v", err)
+	}
+	if _, err := GeneratePAN(Amex, 16, rnd); err != ErrLength {
+		t.Errorf("Expecting ErrLength, got %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+package ident
+
+//https://en.wikipedia.org/wiki/International_Mobile_Equipment_Identity
+//https://en.wikipedia.org/wiki/Social_insurance_number
+//https://en.wikipedia.org/wiki/National_Provider_Identifier
+
+import "github.com/gnabgib/gnablib-go/checksum/luhn"
+
+const (
+	imeiLen   = 15
+	imeisvLen = 16
+	sinLen    = 9
+	npiLen    = 10
+	//NPIs are checked as if they had the ISO 7812 card issuer prefix 80840
+	npiPrefix = "80840"
+)
+
+// Validate an International Mobile Equipment Identity: 14 digits plus a Luhn
+// check digit, spaces and dashes are ignored
+func ValidateIMEI(imei string) error {
+	d, err := digits(imei)
+	if err != nil {
+		return err
+	}
+	if len(d) != imeiLen {
+		return ErrLength
+	}
+	return luhn.Validate(d)
+}
+
+// Validate an IMEI with software version: 14 digits plus a 2 digit software
+// version, there's no check digit so only the format can be confirmed
+func ValidateIMEISV(imeisv string) error {
+	d, err := digits(imeisv)
+	if err != nil {
+		return err
+	}
+	if len(d) != imeisvLen {
+		return ErrLength
+	}
+	return nil
+}
+
+// The IMEI (with check digit) for an IMEISV
+func IMEIFromIMEISV(imeisv string) (string, error) {
+	if err := ValidateIMEISV(imeisv); err != nil {
+		return "", err
+	}
+	d, _ := digits(imeisv)
+	return luhn.Append(d[:imeiLen-1])
+}
+
+// Validate a Canadian Social Insurance Number: 9 digits with a Luhn check.
+// The first digit can't be 0 (unused) or 8 (business numbers)
+func ValidateSIN(sin string) error {
+	d, err := digits(sin)
+	if err != nil {
+		return err
+	}
+	if len(d) != sinLen {
+		return ErrLength
+	}
+	if d[0] == '0' || d[0] == '8' {
+		return ErrPrefix
+	}
+	return luhn.Validate(d)
+}
+
+// Validate a US National Provider Identifier: 10 digits starting 1 or 2, the
+// Luhn check is calculated with the prefix 80840
+func ValidateNPI(npi string) error {
+	d, err := digits(npi)
+	if err != nil {
+		return err
+	}
+	if len(d) != npiLen {
+		return ErrLength
+	}
+	if d[0] != '1' && d[0] != '2' {
+		return ErrPrefix
+	}
+	return luhn.Validate(npiPrefix + d)
+}
+
+// The check digit for the first 9 digits of an NPI
+func NPICheckDigit(npi9 string) (byte, error) {
+	d, err := digits(npi9)
+	if err != nil {
+		return 0, err
+	}
+	if len(d) != npiLen-1 {
+		return 0, ErrLength
+	}
+	return luhn.Generate(npiPrefix + d)
+}
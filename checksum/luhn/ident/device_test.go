@@ -0,0 +1,85 @@
+package ident
+
+import (
+	"testing"
+
+	"github.com/gnabgib/gnablib-go/checksum"
+	"github.com/gnabgib/gnablib-go/checksum/luhn"
+)
+
+func TestIMEI(t *testing.T) {
+	tests := []struct {
+		imei string
+		err  error
+	}{
+		//Wiki
+		{"490154203237518", nil},
+		{"35-209900-176148-1", nil},
+		{"352099001761482", luhn.ErrCheckFailed},
+		{"35209900176148", ErrLength},
+		{"35209900176148A", checksum.InvalidCharAt("Luhn", 'A', 14)},
+	}
+	for _, rec := range tests {
+		if err := ValidateIMEI(rec.imei); err != rec.err {
+			t.Errorf("%s: expecting %v, got %v", rec.imei, rec.err, err)
+		}
+	}
+}
+
+func TestIMEISV(t *testing.T) {
+	if err := ValidateIMEISV("35-209900-176148-23"); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+	if err := ValidateIMEISV("352099001761481"); err != ErrLength {
+		t.Errorf("Expecting ErrLength, got %v", err)
+	}
+	imei, err := IMEIFromIMEISV("3520990017614823")
+	if err != nil || imei != "352099001761481" {
+		t.Errorf("Expecting 352099001761481, got %s %v", imei, err)
+	}
+	if _, err := IMEIFromIMEISV("35209900176148"); err != ErrLength {
+		t.Errorf("Expecting ErrLength, got %v", err)
+	}
+}
+
+func TestSIN(t *testing.T) {
+	tests := []struct {
+		sin string
+		err error
+	}{
+		{"130 692 544", nil},
+		{"130692545", luhn.ErrCheckFailed},
+		//Wiki's example is valid Luhn, but a fictitious (0) prefix
+		{"046 454 286", ErrPrefix},
+		{"13069254", ErrLength},
+	}
+	for _, rec := range tests {
+		if err := ValidateSIN(rec.sin); err != rec.err {
+			t.Errorf("%s: expecting %v, got %v", rec.sin, rec.err, err)
+		}
+	}
+}
+
+func TestNPI(t *testing.T) {
+	tests := []struct {
+		npi string
+		err error
+	}{
+		//CMS example
+		{"1234567893", nil},
+		{"1234567890", luhn.ErrCheckFailed},
+		{"3234567893", ErrPrefix},
+		{"123456789", ErrLength},
+	}
+	for _, rec := range tests {
+		if err := ValidateNPI(rec.npi); err != rec.err {
+			t.Errorf("%s: expecting %v, got %v", rec.npi, rec.err, err)
+		}
+	}
+	if c, err := NPICheckDigit("123456789"); c != '3' || err != nil {
+		t.Errorf("Expecting 3, got %c %v", c, err)
+	}
+	if _, err := NPICheckDigit("12345678"); err != ErrLength {
+		t.Errorf("Expecting ErrLength, got %v", err)
+	}
+}
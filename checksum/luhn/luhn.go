@@ -18,4 +18,22 @@ func Checksum(i uint64) uint8 {
         mul=1+mul%2
 	}
 	return uint8((10-ret%10)%10)
-}
\ No newline at end of file
+}
+
+// Generate the check digit ('0'-'9') for a string of digits of any length,
+// spaces and dashes are ignored
+func Generate(s string) (byte, error) {
+	return Mod10.Generate(s)
+}
+
+// Check the last digit of s is the correct Luhn check digit, spaces and dashes
+// are ignored. Returns ErrCheckFailed if it isn't, a checksum.InvalidCharError for
+// anything that isn't a digit
+func Validate(s string) error {
+	return Mod10.Validate(s)
+}
+
+// Append the check digit to a string of digits
+func Append(s string) (string, error) {
+	return Mod10.Append(s)
+}
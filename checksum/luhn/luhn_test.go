@@ -1,7 +1,10 @@
 package luhn
 
 import (
+	"strconv"
 	"testing"
+
+	"github.com/gnabgib/gnablib-go/checksum"
 )
 
 var luhnTests = []struct {
@@ -43,3 +46,84 @@ func TestLuhn(t *testing.T) {
 		}
 	}
 }
+
+
+var luhnStringTests = []struct {
+	s string
+	c byte
+}{
+	{"7992739871", '3'},
+	{"7992-7398-71", '3'},
+	{"411111111111111", '1'},
+	{"4111 1111 1111 111", '1'},
+	//ICCID (SIM) numbers are 19 digits + check
+	{"8901410321111851072", '0'},
+	{"12345678901234567890123", '4'},
+	//Leading zeros matter for position
+	{"000000000000000000000000000001", '8'},
+	{"0", '0'},
+	{"00", '0'},
+}
+
+func TestGenerate(t *testing.T) {
+	for _, rec := range luhnStringTests {
+		found, err := Generate(rec.s)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", rec.s, err)
+			continue
+		}
+		if found != rec.c {
+			t.Errorf("%s: expecting %c, got %c", rec.s, rec.c, found)
+		}
+	}
+}
+
+func TestGenerate_matchesChecksum(t *testing.T) {
+	for _, rec := range luhnTests {
+		found, _ := Generate(strconv.FormatUint(rec.n, 10))
+		if found != '0'+rec.c {
+			t.Errorf("%d: expecting %d, got %c", rec.n, rec.c, found)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, rec := range luhnStringTests {
+		full, err := Append(rec.s)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", rec.s, err)
+			continue
+		}
+		if full != rec.s+string(rec.c) {
+			t.Errorf("%s: expecting %s, got %s", rec.s, rec.s+string(rec.c), full)
+		}
+		if err = Validate(full); err != nil {
+			t.Errorf("%s: unexpected error %v", full, err)
+		}
+		//Break the check digit
+		bad := full[:len(full)-1] + string('0'+(rec.c-'0'+1)%10)
+		if err = Validate(bad); err != ErrCheckFailed {
+			t.Errorf("%s: expecting ErrCheckFailed, got %v", bad, err)
+		}
+	}
+}
+
+func TestValidate_invalidAt(t *testing.T) {
+	tests := []struct {
+		s   string
+		err error
+	}{
+		{"79927398713", nil},
+		{"7992 7398 713", nil},
+		{"7992x7398713", checksum.InvalidCharAt("Luhn", 'x', 4)},
+		{"7992-7398-71A", checksum.InvalidCharAt("Luhn", 'A', 12)},
+		{"+79927398713", checksum.InvalidCharAt("Luhn", '+', 0)},
+		{"7", ErrTooShort},
+		{"", ErrTooShort},
+	}
+	for _, rec := range tests {
+		if err := Validate(rec.s); err != rec.err {
+			t.Errorf("%q: expecting %v, got %v", rec.s, rec.err, err)
+		}
+	}
+}
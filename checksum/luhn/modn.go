@@ -0,0 +1,147 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+package luhn
+
+// https://en.wikipedia.org/wiki/Luhn_mod_N_algorithm
+
+import (
+	"errors"
+
+	"github.com/gnabgib/gnablib-go/checksum"
+)
+
+// Too few symbols to generate (1) or validate (2) a check character
+var ErrTooShort = errors.New("Too few symbols for Luhn")
+
+// The check character doesn't match the content
+var ErrCheckFailed = errors.New("Luhn check failed")
+
+// The alphabet has fewer than 2 symbols, or a repeat
+var ErrAlphabet = errors.New("Luhn alphabet must have 2-256 unique symbols")
+
+// Luhn mod N, over a configurable alphabet
+type ModN struct {
+	alphabet string
+	index    [256]int16 //-1 when not in the alphabet
+}
+
+// Decimal digits, the classic Luhn
+var Mod10 = MustModN("0123456789")
+
+// Hexadecimal (case insensitive)
+var Mod16 = MustModN("0123456789ABCDEF")
+
+// Digits and letters (case insensitive)
+var Mod36 = MustModN("0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ")
+
+// Build a Luhn mod N where N is the length of alphabet. If the alphabet has
+// no lower case letters, lower case input is treated as upper case
+func NewModN(alphabet string) (*ModN, error) {
+	n := len(alphabet)
+	if n < 2 || n > 256 {
+		return nil, ErrAlphabet
+	}
+	m := &ModN{alphabet: alphabet}
+	for i := range m.index {
+		m.index[i] = -1
+	}
+	hasLower := false
+	for i := 0; i < n; i++ {
+		b := alphabet[i]
+		if m.index[b] >= 0 {
+			return nil, ErrAlphabet
+		}
+		m.index[b] = int16(i)
+		hasLower = hasLower || (b >= 'a' && b <= 'z')
+	}
+	if !hasLower {
+		for b := 'a'; b <= 'z'; b++ {
+			m.index[b] = m.index[b-'a'+'A']
+		}
+	}
+	return m, nil
+}
+
+// Like NewModN but panics on an invalid alphabet (for package level vars)
+func MustModN(alphabet string) *ModN {
+	m, err := NewModN(alphabet)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// Size of the alphabet
+func (m *ModN) N() int { return len(m.alphabet) }
+
+// The alphabet index of each symbol in s, skipping separators
+func (m *ModN) values(s string) ([]int, error) {
+	ret := make([]int, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		b := s[i]
+		v := m.index[b]
+		if v < 0 {
+			if checksum.IsSeparator(b) {
+				continue
+			}
+			return nil, checksum.InvalidCharAt("Luhn", b, i)
+		}
+		ret = append(ret, int(v))
+	}
+	return ret, nil
+}
+
+// Sum working from the right, doubling every `double` position (1 for
+// validate, 0 for generate)
+func (m *ModN) sum(vals []int, double int) int {
+	n := len(m.alphabet)
+	sum := 0
+	for i := len(vals) - 1; i >= 0; i-- {
+		v := vals[i]
+		if (len(vals)-1-i)&1 == double {
+			v *= 2
+			v = v/n + v%n
+		}
+		sum += v
+	}
+	return sum % n
+}
+
+// Generate the check symbol for s
+func (m *ModN) Generate(s string) (byte, error) {
+	vals, err := m.values(s)
+	if err != nil {
+		return 0, err
+	}
+	if len(vals) < 1 {
+		return 0, ErrTooShort
+	}
+	n := len(m.alphabet)
+	return m.alphabet[(n-m.sum(vals, 0))%n], nil
+}
+
+// Check the last symbol of s is the correct check symbol. Returns
+// ErrCheckFailed if it isn't, a checksum.InvalidCharError for unknown symbols
+func (m *ModN) Validate(s string) error {
+	vals, err := m.values(s)
+	if err != nil {
+		return err
+	}
+	if len(vals) < 2 {
+		return ErrTooShort
+	}
+	if m.sum(vals, 1) != 0 {
+		return ErrCheckFailed
+	}
+	return nil
+}
+
+// Append the check symbol to s
+func (m *ModN) Append(s string) (string, error) {
+	c, err := m.Generate(s)
+	if err != nil {
+		return "", err
+	}
+	return s + string(c), nil
+}
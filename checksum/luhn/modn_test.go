@@ -0,0 +1,105 @@
+package luhn
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gnabgib/gnablib-go/checksum"
+)
+
+var modNTests = []struct {
+	m     *ModN
+	s     string
+	check byte
+}{
+	//Wiki
+	{MustModN("abcdef"), "abcdef", 'e'},
+	{Mod16, "DEADBEEF", 'C'},
+	{Mod16, "deadbeef", 'C'},
+	{Mod16, "C0FFEE", 'B'},
+	{Mod36, "GNABGIB2023", 'C'},
+	{Mod36, "VOUCHER9X", 'L'},
+	{Mod36, "vouc-her9x", 'L'},
+	{Mod36, "1", 'Y'},
+}
+
+func TestModN(t *testing.T) {
+	for _, rec := range modNTests {
+		found, err := rec.m.Generate(rec.s)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", rec.s, err)
+			continue
+		}
+		if found != rec.check {
+			t.Errorf("%s: expecting %c, got %c", rec.s, rec.check, found)
+		}
+		full, _ := rec.m.Append(rec.s)
+		if err := rec.m.Validate(full); err != nil {
+			t.Errorf("%s: unexpected validate error %v", full, err)
+		}
+	}
+}
+
+// Any single symbol substitution must be detected
+func TestModN_singleError(t *testing.T) {
+	for _, m := range []*ModN{Mod10, Mod16, Mod36} {
+		full, _ := m.Append("0123456789")
+		for i := 0; i < len(full); i++ {
+			for j := 0; j < m.N(); j++ {
+				c := m.alphabet[j]
+				if c == full[i] {
+					continue
+				}
+				bad := full[:i] + string(c) + full[i+1:]
+				if m.Validate(bad) != ErrCheckFailed {
+					t.Errorf("Mod%d: expecting %s to fail", m.N(), bad)
+				}
+			}
+		}
+	}
+}
+
+func TestModN_errors(t *testing.T) {
+	if _, err := Mod16.Generate("12G4"); err != checksum.InvalidCharAt("Luhn", 'G', 2) {
+		t.Errorf("Expecting invalid G @ 2, got %v", err)
+	}
+	if err := Mod36.Validate("AB_C"); err != checksum.InvalidCharAt("Luhn", '_', 2) {
+		t.Errorf("Expecting invalid _ @ 2, got %v", err)
+	}
+	if _, err := Mod36.Generate(" - "); err != ErrTooShort {
+		t.Errorf("Expecting ErrTooShort, got %v", err)
+	}
+	if err := Mod36.Validate("A"); err != ErrTooShort {
+		t.Errorf("Expecting ErrTooShort, got %v", err)
+	}
+	if _, err := Mod36.Append(""); err != ErrTooShort {
+		t.Errorf("Expecting ErrTooShort, got %v", err)
+	}
+	for _, alphabet := range []string{"", "a", "abca"} {
+		if _, err := NewModN(alphabet); err != ErrAlphabet {
+			t.Errorf("NewModN(%q) expecting ErrAlphabet, got %v", alphabet, err)
+		}
+	}
+}
+
+func TestModN_caseSensitive(t *testing.T) {
+	//An alphabet with lower case letters doesn't fold
+	m := MustModN("abcdefABCDEF")
+	if _, err := m.Generate("aA"); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+	_, err := m.Generate("aAg")
+	var ce checksum.InvalidCharError
+	if !errors.As(err, &ce) || ce.At != 2 || !errors.Is(err, checksum.ErrInvalidChar) {
+		t.Errorf("Expecting invalid char @2, got %v", err)
+	}
+}
+
+func TestMustModN_panic(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expecting panic")
+		}
+	}()
+	MustModN("aa")
+}
@@ -0,0 +1,66 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+package rolling
+
+//https://www.samba.org/~tridge/phd_thesis.pdf (section 3.2.2)
+//a = sum(x_i) mod 2^16, b = sum((l-i+1)x_i) mod 2^16, s = a + 2^16 b
+
+type adler struct {
+	w    ring
+	a, b uint32 //Only the low 16 bits matter
+}
+
+// A new rolling Adler-32 style weak checksum (from the rsync algorithm) over
+// `window` bytes. Unlike Adler-32 the sums start at zero and aren't reduced by
+// a prime
+func NewAdler(window int) (Rolling32, error) {
+	if window < 1 {
+		return nil, ErrWindow
+	}
+	return &adler{w: ring{buf: make([]byte, window)}}, nil
+}
+
+func (d *adler) add(in byte) {
+	d.a += uint32(in)
+	d.b += d.a
+}
+
+func (d *adler) roll(out, in byte) {
+	d.a += uint32(in) - uint32(out)
+	d.b += d.a - uint32(len(d.w.buf))*uint32(out)
+}
+
+func (d *adler) Roll(out, in byte) {
+	d.w.push(in)
+	d.roll(out, in)
+}
+
+func (d *adler) Write(p []byte) (n int, err error) {
+	for _, b := range p {
+		if out, full := d.w.push(b); full {
+			d.roll(out, b)
+		} else {
+			d.add(b)
+		}
+	}
+	return len(p), nil
+}
+
+func (d *adler) Sum(in []byte) []byte {
+	s := d.Sum32()
+	return append(in, byte(s>>24), byte(s>>16), byte(s>>8), byte(s))
+}
+
+func (d *adler) Reset() {
+	d.w.reset()
+	d.a, d.b = 0, 0
+}
+
+func (d *adler) Size() int { return 4 }
+
+func (d *adler) BlockSize() int { return 1 }
+
+func (d *adler) Window() int { return len(d.w.buf) }
+
+func (d *adler) Sum32() uint32 { return d.b<<16 | d.a&0xffff }
@@ -0,0 +1,83 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+package rolling
+
+//https://en.wikipedia.org/wiki/Rolling_hash#Cyclic_polynomial
+//h = rotl(h,1) ^ T[in], rolling also removes rotl(T[out], window)
+
+import "math/bits"
+
+type buzhash struct {
+	w     ring
+	table *[256]uint32
+	h     uint32
+}
+
+// A table of random values for Buzhash generated from `seed` (the same seed
+// always gives the same table)
+func BuzhashTable(seed uint64) *[256]uint32 {
+	t := new([256]uint32)
+	for i := range t {
+		//SplitMix64
+		seed += 0x9e3779b97f4a7c15
+		z := seed
+		z = (z ^ z>>30) * 0xbf58476d1ce4e5b9
+		z = (z ^ z>>27) * 0x94d049bb133111eb
+		t[i] = uint32((z ^ z>>31) >> 32)
+	}
+	return t
+}
+
+// A new rolling Buzhash (cyclic polynomial) over `window` bytes with a byte
+// table generated from `seed`
+func NewBuzhash(window int, seed uint64) (Rolling32, error) {
+	return NewBuzhashTable(window, BuzhashTable(seed))
+}
+
+// A new rolling Buzhash over `window` bytes using the given byte table
+func NewBuzhashTable(window int, table *[256]uint32) (Rolling32, error) {
+	if window < 1 {
+		return nil, ErrWindow
+	}
+	return &buzhash{w: ring{buf: make([]byte, window)}, table: table}, nil
+}
+
+func (d *buzhash) roll(out, in byte) {
+	d.h = bits.RotateLeft32(d.h, 1) ^
+		bits.RotateLeft32(d.table[out], len(d.w.buf)) ^ d.table[in]
+}
+
+func (d *buzhash) Roll(out, in byte) {
+	d.w.push(in)
+	d.roll(out, in)
+}
+
+func (d *buzhash) Write(p []byte) (n int, err error) {
+	for _, b := range p {
+		if out, full := d.w.push(b); full {
+			d.roll(out, b)
+		} else {
+			d.h = bits.RotateLeft32(d.h, 1) ^ d.table[b]
+		}
+	}
+	return len(p), nil
+}
+
+func (d *buzhash) Sum(in []byte) []byte {
+	s := d.h
+	return append(in, byte(s>>24), byte(s>>16), byte(s>>8), byte(s))
+}
+
+func (d *buzhash) Reset() {
+	d.w.reset()
+	d.h = 0
+}
+
+func (d *buzhash) Size() int { return 4 }
+
+func (d *buzhash) BlockSize() int { return 1 }
+
+func (d *buzhash) Window() int { return len(d.w.buf) }
+
+func (d *buzhash) Sum32() uint32 { return d.h }
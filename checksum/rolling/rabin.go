@@ -0,0 +1,155 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+package rolling
+
+//http://www.xmailserver.org/rabin.pdf
+//https://en.wikipedia.org/wiki/Rabin_fingerprint
+//The fingerprint of bytes w_1..w_l is sum(w_i x^(8(l-i))) mod P over GF(2)
+
+import (
+	"errors"
+	"math/bits"
+)
+
+// The polynomial isn't irreducible, or has a degree outside 8-63
+var ErrPoly = errors.New("Rabin polynomial must be irreducible with a degree of 8-63")
+
+// An irreducible polynomial of degree 53 (x^53+x^6+x^2+x+1)
+const DefaultPoly uint64 = 1<<53 | 1<<6 | 1<<2 | 1<<1 | 1
+
+type rabin struct {
+	w   ring
+	deg uint
+	mod [256]uint64 //Reduction of the top byte shifted past the degree
+	out [256]uint64 //Contribution of the oldest byte in a full window
+	fp  uint64
+}
+
+// A new rolling Rabin fingerprint over `window` bytes, modulo the irreducible
+// polynomial `poly` (the highest set bit is the degree, which must be 8-63).
+// Returns ErrPoly if the polynomial isn't suitable
+func NewRabin(window int, poly uint64) (Rolling64, error) {
+	if window < 1 {
+		return nil, ErrWindow
+	}
+	if !Irreducible(poly) || degree(poly) < 8 {
+		return nil, ErrPoly
+	}
+	d := &rabin{w: ring{buf: make([]byte, window)}, deg: degree(poly)}
+	for i := range d.mod {
+		//x^deg = poly - x^deg (mod poly)
+		d.mod[i] = mulMod(uint64(i), poly&^(1<<d.deg), poly)
+	}
+	for i := range d.out {
+		//i * x^(8(l-1))
+		var fp uint64
+		fp = d.append(fp, byte(i))
+		for j := 1; j < window; j++ {
+			fp = d.append(fp, 0)
+		}
+		d.out[i] = fp
+	}
+	return d, nil
+}
+
+// Degree of poly (the highest set bit)
+func degree(poly uint64) uint {
+	return uint(63 - bits.LeadingZeros64(poly))
+}
+
+// a*b mod poly over GF(2), a must have a lower degree than poly
+func mulMod(a, b, poly uint64) uint64 {
+	deg := degree(poly)
+	top := uint64(1) << deg
+	var r uint64
+	for ; b != 0; b >>= 1 {
+		if b&1 != 0 {
+			r ^= a
+		}
+		a <<= 1
+		if a&top != 0 {
+			a ^= poly
+		}
+	}
+	return r
+}
+
+func gcd(a, b uint64) uint64 {
+	for b != 0 {
+		//a mod b
+		db := degree(b)
+		for a != 0 && degree(a) >= db {
+			a ^= b << (degree(a) - db)
+		}
+		a, b = b, a
+	}
+	return a
+}
+
+// Whether poly is irreducible over GF(2) (degree 1-63). Uses Ben-Or's test:
+// gcd(poly, x^(2^i) - x) = 1 for i = 1..degree/2
+func Irreducible(poly uint64) bool {
+	if poly < 2 {
+		return false
+	}
+	deg := degree(poly)
+	if deg == 1 {
+		return true
+	}
+	const x = 2
+	p := uint64(x)
+	for i := uint(1); i <= deg/2; i++ {
+		p = mulMod(p, p, poly)
+		if gcd(poly, p^x) != 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// (fp x^8 + b) mod poly
+func (d *rabin) append(fp uint64, b byte) uint64 {
+	top := fp >> (d.deg - 8)
+	fp = (fp<<8)&(1<<d.deg-1) | uint64(b)
+	return fp ^ d.mod[top]
+}
+
+func (d *rabin) roll(out, in byte) {
+	d.fp = d.append(d.fp^d.out[out], in)
+}
+
+func (d *rabin) Roll(out, in byte) {
+	d.w.push(in)
+	d.roll(out, in)
+}
+
+func (d *rabin) Write(p []byte) (n int, err error) {
+	for _, b := range p {
+		if out, full := d.w.push(b); full {
+			d.roll(out, b)
+		} else {
+			d.fp = d.append(d.fp, b)
+		}
+	}
+	return len(p), nil
+}
+
+func (d *rabin) Sum(in []byte) []byte {
+	s := d.fp
+	return append(in, byte(s>>56), byte(s>>48), byte(s>>40), byte(s>>32),
+		byte(s>>24), byte(s>>16), byte(s>>8), byte(s))
+}
+
+func (d *rabin) Reset() {
+	d.w.reset()
+	d.fp = 0
+}
+
+func (d *rabin) Size() int { return 8 }
+
+func (d *rabin) BlockSize() int { return 1 }
+
+func (d *rabin) Window() int { return len(d.w.buf) }
+
+func (d *rabin) Sum64() uint64 { return d.fp }
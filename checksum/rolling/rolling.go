@@ -0,0 +1,67 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+// Rolling checksums over a fixed size window, that can slide one byte in O(1)
+package rolling
+
+import (
+	"errors"
+	"hash"
+)
+
+// The window size must be at least 1
+var ErrWindow = errors.New("rolling window must be at least 1 byte")
+
+// A checksum of the last Window() bytes written. Write slides the window once
+// it's full, so Sum is always of the most recent bytes
+type Rolling interface {
+	hash.Hash
+
+	// Slide the window one byte: `out` (which must be the oldest byte in a
+	// full window) leaves, and `in` enters
+	Roll(out, in byte)
+
+	// Size of the window in bytes
+	Window() int
+}
+
+// A rolling checksum with a 32bit result
+type Rolling32 interface {
+	Rolling
+
+	Sum32() uint32
+}
+
+// A rolling checksum with a 64bit result
+type Rolling64 interface {
+	Rolling
+
+	Sum64() uint64
+}
+
+// The bytes in the window
+type ring struct {
+	buf []byte
+	pos int //Next slot to write (the oldest byte once full)
+	n   int //Bytes held (up to len(buf))
+}
+
+// Add b to the window, if the window was full `out` is the byte that left
+func (w *ring) push(b byte) (out byte, full bool) {
+	out = w.buf[w.pos]
+	full = w.n == len(w.buf)
+	w.buf[w.pos] = b
+	w.pos++
+	if w.pos == len(w.buf) {
+		w.pos = 0
+	}
+	if !full {
+		w.n++
+	}
+	return
+}
+
+func (w *ring) reset() {
+	w.pos = 0
+	w.n = 0
+}
@@ -0,0 +1,145 @@
+package rolling
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+)
+
+// Each is the sum of the last `window` bytes of "gnabgib foo bar baz"
+var rollingTests = []struct {
+	window  int
+	adler   uint32
+	buz0    uint32
+	buz42   uint32
+	rabin53 uint64
+}{
+	{1, 0x007a007a, 0x9fea7dfc, 0x5bb34ae5, 0x7a},
+	{4, 0x02e2015d, 0x502cee64, 0xc1e9f0c6, 0x2062617a},
+	{16, 0x301005aa, 0x78181669, 0xb7a76f0c, 0x1756b6eeb28804},
+	//Window bigger than the content
+	{64, 0x45e206e0, 0x5a1fd53e, 0xcf322762, 0x0b0c3e93f28b20},
+}
+
+func TestRolling(t *testing.T) {
+	in := []byte("gnabgib foo bar baz")
+	for _, rec := range rollingTests {
+		a, _ := NewAdler(rec.window)
+		b0, _ := NewBuzhash(rec.window, 0)
+		b42, _ := NewBuzhash(rec.window, 42)
+		r, _ := NewRabin(rec.window, DefaultPoly)
+		for _, h := range []Rolling{a, b0, b42, r} {
+			h.Write(in)
+		}
+		if found := a.Sum32(); found != rec.adler {
+			t.Errorf("Adler(%d) expecting %08x, got %08x", rec.window, rec.adler, found)
+		}
+		if found := b0.Sum32(); found != rec.buz0 {
+			t.Errorf("Buzhash(%d,0) expecting %08x, got %08x", rec.window, rec.buz0, found)
+		}
+		if found := b42.Sum32(); found != rec.buz42 {
+			t.Errorf("Buzhash(%d,42) expecting %08x, got %08x", rec.window, rec.buz42, found)
+		}
+		if found := r.Sum64(); found != rec.rabin53 {
+			t.Errorf("Rabin(%d) expecting %014x, got %014x", rec.window, rec.rabin53, found)
+		}
+	}
+}
+
+func factories(window int) map[string]func() Rolling {
+	return map[string]func() Rolling{
+		"Adler":   func() Rolling { h, _ := NewAdler(window); return h },
+		"Buzhash": func() Rolling { h, _ := NewBuzhash(window, 1); return h },
+		"Rabin":   func() Rolling { h, _ := NewRabin(window, DefaultPoly); return h },
+		"Rabin63": func() Rolling { h, _ := NewRabin(window, 1<<63|1<<1|1); return h },
+		"Rabin8":  func() Rolling { h, _ := NewRabin(window, 0x11b); return h },
+	}
+}
+
+func TestRollMatchesFresh(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	data := make([]byte, 500)
+	rnd.Read(data)
+	for _, window := range []int{1, 2, 7, 32, 48, 64} {
+		for name, f := range factories(window) {
+			h := f()
+			if h.Window() != window {
+				t.Errorf("%s expecting window %d, got %d", name, window, h.Window())
+			}
+			written := f()
+			rolled := f()
+			rolled.Write(data[:window])
+			for i := 0; i < len(data); i++ {
+				written.Write(data[i : i+1])
+				if i < window {
+					continue
+				}
+				rolled.Roll(data[i-window], data[i])
+				h.Reset()
+				h.Write(data[i+1-window : i+1])
+				expect := h.Sum(nil)
+				if found := written.Sum(nil); !bytes.Equal(found, expect) {
+					t.Fatalf("%s(%d) write @%d expecting %x, got %x", name, window, i, expect, found)
+				}
+				if found := rolled.Sum(nil); !bytes.Equal(found, expect) {
+					t.Fatalf("%s(%d) roll @%d expecting %x, got %x", name, window, i, expect, found)
+				}
+			}
+		}
+	}
+}
+
+func TestIrreducible(t *testing.T) {
+	tests := []struct {
+		poly   uint64
+		expect bool
+	}{
+		{DefaultPoly, true},
+		{0x3DA3358B4DC173, true},
+		{1<<63 | 1<<1 | 1, true},
+		{0x11b, true}, //AES
+		{0x11d, true},
+		{0x7, true},
+		{0x100, false},
+		{0x5, false}, //(x+1)^2
+		{1<<53 | 1, false},
+		{1, false},
+		{0, false},
+	}
+	for _, rec := range tests {
+		if found := Irreducible(rec.poly); found != rec.expect {
+			t.Errorf("%x expecting %v, got %v", rec.poly, rec.expect, found)
+		}
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	if _, err := NewAdler(0); err != ErrWindow {
+		t.Errorf("Adler expecting ErrWindow, got %v", err)
+	}
+	if _, err := NewBuzhash(-1, 0); err != ErrWindow {
+		t.Errorf("Buzhash expecting ErrWindow, got %v", err)
+	}
+	if _, err := NewRabin(0, DefaultPoly); err != ErrWindow {
+		t.Errorf("Rabin expecting ErrWindow, got %v", err)
+	}
+	for _, poly := range []uint64{0x7, 1<<53 | 1, 0} {
+		if _, err := NewRabin(16, poly); err != ErrPoly {
+			t.Errorf("Rabin(%x) expecting ErrPoly, got %v", poly, err)
+		}
+	}
+}
+
+func TestBuzhashTable(t *testing.T) {
+	a, b := BuzhashTable(0), BuzhashTable(0)
+	if *a != *b {
+		t.Errorf("Expecting the same table from the same seed")
+	}
+	//SplitMix64's first output for seed 0 is e220a8397b1dcdaf
+	if a[0] != 0xe220a839 {
+		t.Errorf("Expecting e220a839, got %08x", a[0])
+	}
+	if c := BuzhashTable(1); *a == *c {
+		t.Errorf("Expecting different seeds to give different tables")
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+// Simple 8, 16 and 32 bit sums: additive (two's complement), XOR and ones'
+// complement (end-around carry) over bytes or words
+package sum
+
+//https://datatracker.ietf.org/doc/html/rfc1071 (ones' complement)
+//https://en.wikipedia.org/wiki/Checksum
+
+import (
+	"encoding/binary"
+	"hash"
+
+	"github.com/gnabgib/gnablib-go/checksum"
+)
+
+type op uint8
+
+const (
+	add  op = iota //Modular sum
+	xor            //Word wise XOR
+	ones           //Ones' complement of the end-around carry sum
+)
+
+// Words are assembled from bytes in `order`. A trailing partial word is padded
+// with zero bytes (as if the data were extended to a whole word) when summed,
+// without changing the state, so more data can be written after
+type digest struct {
+	op    op
+	size  int //Word size in bytes (1, 2 or 4)
+	order binary.ByteOrder
+	s     uint64
+	part  [4]byte //Bytes waiting for the rest of their word
+	pLen  int     //Number of bytes in part (0-3)
+}
+
+func newDigest(o op, size int, order binary.ByteOrder) *digest {
+	d := &digest{op: o, size: size, order: order}
+	d.Reset()
+	return d
+}
+
+// Width specific wrappers, so each constructor only exposes its own SumN
+type digest8 struct{ *digest }
+
+func (d *digest8) Sum8() uint8 { return uint8(d.final()) }
+
+type digest16 struct{ *digest }
+
+func (d *digest16) Sum16() uint16 { return uint16(d.final()) }
+
+type digest32 struct{ *digest }
+
+func (d *digest32) Sum32() uint32 { return uint32(d.final()) }
+
+// A new Hash8 for the sum of bytes (mod 256)
+func NewSum8() checksum.Hash8 { return &digest8{newDigest(add, 1, nil)} }
+
+// A new Hash16 for the sum of 16bit words (mod 2^16), words are assembled in
+// `order` (eg. endian.Network or binary.LittleEndian)
+func NewSum16(order binary.ByteOrder) checksum.Hash16 { return &digest16{newDigest(add, 2, order)} }
+
+// A new Hash32 for the sum of 32bit words (mod 2^32), words are assembled in
+// `order`
+func NewSum32(order binary.ByteOrder) hash.Hash32 { return &digest32{newDigest(add, 4, order)} }
+
+// A new Hash16 for the XOR of 16bit words, words are assembled in `order`
+func NewXor16(order binary.ByteOrder) checksum.Hash16 { return &digest16{newDigest(xor, 2, order)} }
+
+// A new Hash32 for the XOR of 32bit words, words are assembled in `order`
+func NewXor32(order binary.ByteOrder) hash.Hash32 { return &digest32{newDigest(xor, 4, order)} }
+
+// A new Hash16 for the ones' complement of the ones' complement sum of 16bit
+// words (the internet checksum when `order` is big endian)
+func NewOnesComplement16(order binary.ByteOrder) checksum.Hash16 {
+	return &digest16{newDigest(ones, 2, order)}
+}
+
+// A new Hash32 for the ones' complement of the ones' complement sum of 32bit
+// words, words are assembled in `order`
+func NewOnesComplement32(order binary.ByteOrder) hash.Hash32 {
+	return &digest32{newDigest(ones, 4, order)}
+}
+
+func (d *digest) word(p []byte) uint64 {
+	switch d.size {
+	case 1:
+		return uint64(p[0])
+	case 2:
+		return uint64(d.order.Uint16(p))
+	}
+	return uint64(d.order.Uint32(p))
+}
+
+// Fold carries back into the low `size` bytes (end-around carry)
+func (d *digest) fold(s uint64) uint64 {
+	bits := uint(d.size) * 8
+	for s>>bits != 0 {
+		s = s&(1<<bits-1) + s>>bits
+	}
+	return s
+}
+
+func (d *digest) add(w uint64) {
+	switch d.op {
+	case xor:
+		d.s ^= w
+	case ones:
+		d.s += w
+		//Words are at most 32bits, so folding before the top bit is used
+		//keeps the sum exact
+		if d.s>>63 != 0 {
+			d.s = d.fold(d.s)
+		}
+	default:
+		d.s += w
+	}
+}
+
+func (d *digest) update(p []byte) {
+	//Complete a word started in a prior write
+	if d.pLen > 0 {
+		n := copy(d.part[d.pLen:d.size], p)
+		d.pLen += n
+		p = p[n:]
+		if d.pLen < d.size {
+			return
+		}
+		d.add(d.word(d.part[:]))
+		d.pLen = 0
+	}
+	n := len(p) - len(p)%d.size
+	for i := 0; i < n; i += d.size {
+		d.add(d.word(p[i:]))
+	}
+	d.pLen = copy(d.part[:], p[n:])
+}
+
+func (d *digest) Write(p []byte) (n int, err error) {
+	d.update(p)
+	return len(p), nil
+}
+
+// The final value, with any partial word zero padded (doesn't mutate d)
+func (d *digest) final() uint64 {
+	t := *d
+	if t.pLen > 0 {
+		t.update(make([]byte, t.size-t.pLen))
+	}
+	s := t.s
+	if t.op == ones {
+		s = ^t.fold(s)
+	}
+	return s & (1<<(uint(t.size)*8) - 1)
+}
+
+// Big endian, like other hashes (regardless of the word order)
+func (d *digest) Sum(in []byte) []byte {
+	s := d.final()
+	for i := d.size - 1; i >= 0; i-- {
+		in = append(in, byte(s>>(uint(i)*8)))
+	}
+	return in
+}
+
+func (d *digest) Reset() {
+	d.s = 0
+	d.pLen = 0
+}
+
+func (d *digest) Size() int { return d.size }
+
+func (d *digest) BlockSize() int { return d.size }
@@ -0,0 +1,166 @@
+package sum
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"testing"
+
+	"github.com/gnabgib/gnablib-go/endian"
+	"github.com/gnabgib/gnablib-go/test"
+)
+
+var sumTests = []struct {
+	s          string
+	s8         uint8
+	s16b, s16l uint16
+	s32b, s32l uint32
+	x16b, x16l uint16
+	x32b, x32l uint32
+	o16b, o16l uint16
+	o32b, o32l uint32
+}{
+	{"", 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xffff, 0xffff, 0xffffffff, 0xffffffff},
+	{"a", 0x61, 0x6100, 0x0061, 0x61000000, 0x00000061, 0x6100, 0x0061, 0x61000000, 0x00000061, 0x9eff, 0xff9e, 0x9effffff, 0xffffff9e},
+	{"ab", 0xc3, 0x6162, 0x6261, 0x61620000, 0x00006261, 0x6162, 0x6261, 0x61620000, 0x00006261, 0x9e9d, 0x9d9e, 0x9e9dffff, 0xffff9d9e},
+	{"abc", 0x26, 0xc462, 0x62c4, 0x61626300, 0x00636261, 0x0262, 0x6202, 0x61626300, 0x00636261, 0x3b9d, 0x9d3b, 0x9e9d9cff, 0xff9c9d9e},
+	{"abcde", 0xef, 0x29c6, 0xc729, 0xc6626364, 0x646362c6, 0x6706, 0x0667, 0x04626364, 0x64636204, 0xd638, 0x38d6, 0x399d9c9b, 0x9b9c9d39},
+	{"123456789", 0xdd, 0x09d4, 0xd509, 0x9f686a6c, 0x6c6a689f, 0x3908, 0x0839, 0x3d04040c, 0x0c04043d, 0xf62a, 0x2af6, 0x60979593, 0x93959760},
+	{"gnabgib", 0xca, 0x9239, 0x3a91, 0xced7c362, 0x62c3d7ce, 0x0365, 0x6503, 0x00070362, 0x62030700, 0x6dc5, 0xc56d, 0x31283c9d, 0x9d3c2831},
+	//RFC1071 (section 3) example
+	{"\x00\x01\xf2\x03\xf4\xf5\xf6\xf7", 0xcc, 0xddf0, 0xf2dc, 0xf4f7e8fa, 0xfbe8f6f4, 0xf000, 0x00f0, 0xf4f404f4, 0xf404f4f4, 0x220d, 0x0d22, 0x0b081705, 0x0417090b},
+	{"\xff\xff\xff\xff\xff", 0xfb, 0xfefe, 0x00fd, 0xfeffffff, 0x000000fe, 0xff00, 0x00ff, 0x00ffffff, 0xffffff00, 0x00ff, 0xff00, 0x00ffffff, 0xffffff00},
+	{"foo bar baz٪☃🍣", 0x35, 0x48f1, 0xf644, 0xc6b4823f, 0x4182b4c4, 0x4eeb, 0xeb4e, 0x3c1272f9, 0xf972123c, 0xb709, 0x09b7, 0x394b7dbe, 0xbe7d4b39},
+}
+
+func sum16(h hash.Hash, s string) uint16 {
+	h.Write([]byte(s))
+	return h.(interface{ Sum16() uint16 }).Sum16()
+}
+
+func sum32(h hash.Hash32, s string) uint32 {
+	h.Write([]byte(s))
+	return h.Sum32()
+}
+
+func TestSum8(t *testing.T) {
+	for _, rec := range sumTests {
+		d := NewSum8()
+		d.Write([]byte(rec.s))
+		if found := d.Sum8(); found != rec.s8 {
+			t.Errorf("Sum8 %q expecting %x, got %x", rec.s, rec.s8, found)
+		}
+	}
+}
+
+func TestWords(t *testing.T) {
+	be, le := endian.Network, binary.LittleEndian
+	for _, rec := range sumTests {
+		tests := []struct {
+			name          string
+			expect, found uint32
+		}{
+			{"Sum16 BE", uint32(rec.s16b), uint32(sum16(NewSum16(be), rec.s))},
+			{"Sum16 LE", uint32(rec.s16l), uint32(sum16(NewSum16(le), rec.s))},
+			{"Sum32 BE", rec.s32b, sum32(NewSum32(be), rec.s)},
+			{"Sum32 LE", rec.s32l, sum32(NewSum32(le), rec.s)},
+			{"Xor16 BE", uint32(rec.x16b), uint32(sum16(NewXor16(be), rec.s))},
+			{"Xor16 LE", uint32(rec.x16l), uint32(sum16(NewXor16(le), rec.s))},
+			{"Xor32 BE", rec.x32b, sum32(NewXor32(be), rec.s)},
+			{"Xor32 LE", rec.x32l, sum32(NewXor32(le), rec.s)},
+			{"OnesComplement16 BE", uint32(rec.o16b), uint32(sum16(NewOnesComplement16(be), rec.s))},
+			{"OnesComplement16 LE", uint32(rec.o16l), uint32(sum16(NewOnesComplement16(le), rec.s))},
+			{"OnesComplement32 BE", rec.o32b, sum32(NewOnesComplement32(be), rec.s)},
+			{"OnesComplement32 LE", rec.o32l, sum32(NewOnesComplement32(le), rec.s)},
+		}
+		for _, tst := range tests {
+			if tst.found != tst.expect {
+				t.Errorf("%s %q expecting %x, got %x", tst.name, rec.s, tst.expect, tst.found)
+			}
+		}
+	}
+}
+
+func TestConformance(t *testing.T) {
+	be := endian.Network
+	factories := []struct {
+		name    string
+		factory func() hash.Hash
+		expect  func(i int) string
+	}{
+		{"Sum8", func() hash.Hash { return NewSum8() }, func(i int) string { return fmt.Sprintf("%02X", sumTests[i].s8) }},
+		{"Sum16", func() hash.Hash { return NewSum16(be) }, func(i int) string { return fmt.Sprintf("%04X", sumTests[i].s16b) }},
+		{"Sum32", func() hash.Hash { return NewSum32(be) }, func(i int) string { return fmt.Sprintf("%08X", sumTests[i].s32b) }},
+		{"Xor16", func() hash.Hash { return NewXor16(be) }, func(i int) string { return fmt.Sprintf("%04X", sumTests[i].x16b) }},
+		{"Xor32", func() hash.Hash { return NewXor32(be) }, func(i int) string { return fmt.Sprintf("%08X", sumTests[i].x32b) }},
+		{"OnesComplement16", func() hash.Hash { return NewOnesComplement16(be) }, func(i int) string { return fmt.Sprintf("%04X", sumTests[i].o16b) }},
+		{"OnesComplement32", func() hash.Hash { return NewOnesComplement32(be) }, func(i int) string { return fmt.Sprintf("%08X", sumTests[i].o32b) }},
+	}
+	for _, f := range factories {
+		vectors := []test.HashVector{}
+		for i, rec := range sumTests {
+			vectors = append(vectors, test.HashVector{In: rec.s, Hex: f.expect(i)})
+		}
+		t.Run(f.name, func(t *testing.T) {
+			test.HashConformance(t, f.factory, vectors)
+		})
+	}
+}
+
+func TestOnesComplementCarry(t *testing.T) {
+	//0xffff words never overflow to zero in ones' complement (-0 stays -0)
+	d := NewOnesComplement16(endian.Network)
+	for i := 0; i < 100000; i++ {
+		d.Write([]byte{0xff, 0xff})
+	}
+	if found := d.Sum16(); found != 0 {
+		t.Errorf("Expecting 0, got %x", found)
+	}
+	//Appending the checksum gives a sum of -0 (so a checksum of 0)
+	d = NewOnesComplement16(endian.Network)
+	d.Write([]byte("gnabgib\x00"))
+	d.Write(d.Sum(nil))
+	if found := d.Sum16(); found != 0 {
+		t.Errorf("Expecting 0, got %x", found)
+	}
+}
+
+func TestSumContinues(t *testing.T) {
+	//Sum pads a partial word without changing state
+	d := NewSum16(binary.LittleEndian)
+	d.Write([]byte("abc"))
+	if found := d.Sum16(); found != 0x62c4 {
+		t.Errorf("Expecting 62c4, got %x", found)
+	}
+	d.Write([]byte("de"))
+	if found := d.Sum16(); found != 0xc729 {
+		t.Errorf("Expecting c729, got %x", found)
+	}
+	if d.Size() != 2 || d.BlockSize() != 2 {
+		t.Errorf("Expecting size 2, got %d/%d", d.Size(), d.BlockSize())
+	}
+}
+
+func TestWidthOnly(t *testing.T) {
+	hashes := []struct {
+		name string
+		h    hash.Hash
+		size int
+	}{
+		{"Sum8", NewSum8(), 1},
+		{"Sum16", NewSum16(binary.BigEndian), 2},
+		{"Xor16", NewXor16(binary.BigEndian), 2},
+		{"Ones16", NewOnesComplement16(binary.BigEndian), 2},
+		{"Sum32", NewSum32(binary.BigEndian), 4},
+		{"Xor32", NewXor32(binary.BigEndian), 4},
+		{"Ones32", NewOnesComplement32(binary.BigEndian), 4},
+	}
+	for _, rec := range hashes {
+		_, is8 := rec.h.(interface{ Sum8() uint8 })
+		_, is16 := rec.h.(interface{ Sum16() uint16 })
+		_, is32 := rec.h.(interface{ Sum32() uint32 })
+		if is8 != (rec.size == 1) || is16 != (rec.size == 2) || is32 != (rec.size == 4) {
+			t.Errorf("%s: expecting only Sum%d, got 8=%v 16=%v 32=%v", rec.name, rec.size*8, is8, is16, is32)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+// Verhoeff check digit, detects all single digit errors and adjacent transpositions
+package verhoeff
+
+// https://en.wikipedia.org/wiki/Verhoeff_algorithm
+
+import (
+	"errors"
+
+	"github.com/gnabgib/gnablib-go/checksum"
+)
+
+// Too few digits to generate (1) or validate (2) a check digit
+var ErrTooShort = errors.New("Too few digits for Verhoeff")
+
+// The check digit doesn't match the content
+var ErrCheckFailed = errors.New("Verhoeff check failed")
+
+// Multiplication table of the dihedral group D5
+var d = [10][10]uint8{
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+	{1, 2, 3, 4, 0, 6, 7, 8, 9, 5},
+	{2, 3, 4, 0, 1, 7, 8, 9, 5, 6},
+	{3, 4, 0, 1, 2, 8, 9, 5, 6, 7},
+	{4, 0, 1, 2, 3, 9, 5, 6, 7, 8},
+	{5, 9, 8, 7, 6, 0, 4, 3, 2, 1},
+	{6, 5, 9, 8, 7, 1, 0, 4, 3, 2},
+	{7, 6, 5, 9, 8, 2, 1, 0, 4, 3},
+	{8, 7, 6, 5, 9, 3, 2, 1, 0, 4},
+	{9, 8, 7, 6, 5, 4, 3, 2, 1, 0},
+}
+
+// Permutation applied by position (repeats every 8)
+var p = [8][10]uint8{
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+	{1, 5, 7, 6, 2, 8, 3, 0, 9, 4},
+	{5, 8, 0, 3, 7, 9, 6, 1, 4, 2},
+	{8, 9, 1, 6, 0, 4, 3, 5, 2, 7},
+	{9, 4, 5, 3, 1, 2, 6, 8, 7, 0},
+	{4, 2, 8, 6, 5, 7, 3, 9, 0, 1},
+	{2, 7, 9, 3, 8, 0, 6, 4, 1, 5},
+	{7, 0, 4, 6, 9, 1, 3, 2, 5, 8},
+}
+
+// Multiplicative inverse in D5
+var inv = [10]uint8{0, 4, 3, 2, 1, 5, 6, 7, 8, 9}
+
+// Generate a single digit (0-9) checksum for the given number
+func Checksum(i uint64) uint8 {
+	//Digits are processed right to left, the check digit takes position 0. Zero
+	//is treated as the single digit "0"
+	c := uint8(0)
+	for pos := 1; ; pos++ {
+		c = d[c][p[pos%8][i%10]]
+		i /= 10
+		if i == 0 {
+			return inv[c]
+		}
+	}
+}
+
+// The digits in s, skipping separators
+func digits(s string) ([]uint8, error) {
+	ret := make([]uint8, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		b := s[i]
+		if b >= '0' && b <= '9' {
+			ret = append(ret, b-'0')
+			continue
+		}
+		if checksum.IsSeparator(b) {
+			continue
+		}
+		return nil, checksum.InvalidCharAt("Verhoeff", b, i)
+	}
+	return ret, nil
+}
+
+// Run the digits through the tables from the right, starting at position `first`
+func check(ds []uint8, first int) uint8 {
+	c := uint8(0)
+	for i := len(ds) - 1; i >= 0; i-- {
+		c = d[c][p[(len(ds)-1-i+first)%8][ds[i]]]
+	}
+	return c
+}
+
+// Generate the check digit ('0'-'9') for a string of digits of any length,
+// spaces and dashes are ignored
+func Generate(s string) (byte, error) {
+	ds, err := digits(s)
+	if err != nil {
+		return 0, err
+	}
+	if len(ds) < 1 {
+		return 0, ErrTooShort
+	}
+	return '0' + inv[check(ds, 1)], nil
+}
+
+// Check the last digit of s is the correct Verhoeff check digit, spaces and
+// dashes are ignored. Returns ErrCheckFailed if it isn't, a
+// checksum.InvalidCharError for anything that isn't a digit
+func Validate(s string) error {
+	ds, err := digits(s)
+	if err != nil {
+		return err
+	}
+	if len(ds) < 2 {
+		return ErrTooShort
+	}
+	if check(ds, 0) != 0 {
+		return ErrCheckFailed
+	}
+	return nil
+}
+
+// Append the check digit to a string of digits
+func Append(s string) (string, error) {
+	c, err := Generate(s)
+	if err != nil {
+		return "", err
+	}
+	return s + string(c), nil
+}
@@ -0,0 +1,111 @@
+package verhoeff
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/gnabgib/gnablib-go/checksum"
+)
+
+var verhoeffTests = []struct {
+	s string
+	c byte
+}{
+	//Wiki
+	{"236", '3'},
+	//Others
+	{"12345", '1'},
+	{"142857", '0'},
+	{"0", '4'},
+	{"1", '5'},
+	{"123456789", '0'},
+	{"1234-5678-9", '0'},
+	{"8473643095483728456789", '2'},
+}
+
+func TestChecksum(t *testing.T) {
+	for _, rec := range verhoeffTests {
+		n, err := strconv.ParseUint(rec.s, 10, 64)
+		if err != nil {
+			continue
+		}
+		if found := Checksum(n); found != rec.c-'0' {
+			t.Errorf("%d: expecting %c, got %d", n, rec.c, found)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	for _, rec := range verhoeffTests {
+		found, err := Generate(rec.s)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", rec.s, err)
+			continue
+		}
+		if found != rec.c {
+			t.Errorf("%s: expecting %c, got %c", rec.s, rec.c, found)
+		}
+		full, _ := Append(rec.s)
+		if err = Validate(full); err != nil {
+			t.Errorf("%s: unexpected error %v", full, err)
+		}
+	}
+}
+
+func TestValidate_errors(t *testing.T) {
+	tests := []struct {
+		s   string
+		err error
+	}{
+		{"2363", nil},
+		{"2364", ErrCheckFailed},
+		{"2 3 6 3", nil},
+		{"23x63", checksum.InvalidCharAt("Verhoeff", 'x', 2)},
+		{"3", ErrTooShort},
+		{"", ErrTooShort},
+	}
+	for _, rec := range tests {
+		if err := Validate(rec.s); err != rec.err {
+			t.Errorf("%q: expecting %v, got %v", rec.s, rec.err, err)
+		}
+	}
+	if _, err := Generate("-"); err != ErrTooShort {
+		t.Errorf("Expecting ErrTooShort, got %v", err)
+	}
+	if _, err := Append("1.2"); err != checksum.InvalidCharAt("Verhoeff", '.', 1) {
+		t.Errorf("Expecting invalid . @ 1, got %v", err)
+	}
+}
+
+// Every 4 digit payload: each single digit error and adjacent transposition
+// (including with the check digit) must be detected
+func TestExhaustive(t *testing.T) {
+	for n := 0; n < 10000; n++ {
+		payload := strconv.Itoa(n + 10000)[1:]
+		full, _ := Append(payload)
+		b := []byte(full)
+		for i := range b {
+			orig := b[i]
+			for c := byte('0'); c <= '9'; c++ {
+				if c == orig {
+					continue
+				}
+				b[i] = c
+				if Validate(string(b)) == nil {
+					t.Fatalf("%s: single error %s not detected", full, b)
+				}
+			}
+			b[i] = orig
+		}
+		for i := 0; i < len(b)-1; i++ {
+			if b[i] == b[i+1] {
+				continue
+			}
+			b[i], b[i+1] = b[i+1], b[i]
+			if Validate(string(b)) == nil {
+				t.Fatalf("%s: transposition %s not detected", full, b)
+			}
+			b[i], b[i+1] = b[i+1], b[i]
+		}
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+// Weighted check schemes of common identifiers
+package catalogue
+
+//https://www.isbn-international.org/content/isbn-users-manual (ISBN-10, ISBN-13)
+//https://www.issn.org/understanding-the-issn/issn-uses/ (ISSN)
+//https://www.gs1.org/services/how-calculate-check-digit-manually (EAN, UPC, GTIN)
+//https://en.wikipedia.org/wiki/ABA_routing_transit_number
+//https://en.wikipedia.org/wiki/Vehicle_identification_number#Check-digit_calculation (North America)
+//https://en.wikipedia.org/wiki/ISO_6346
+
+import "github.com/gnabgib/gnablib-go/checksum/weighted"
+
+const (
+	digits   = "0123456789"
+	digitsX  = "0123456789X"
+	hyphenSp = "- "
+)
+
+// GS1 family: weights 3,1.. from the right, mod 10
+func gs1(name string, length int, prefixes ...string) *weighted.Scheme {
+	return &weighted.Scheme{
+		Name:       name,
+		Length:     length,
+		CheckAt:    -1,
+		Weights:    []int{3, 1},
+		FromRight:  true,
+		Modulus:    10,
+		Complement: true,
+		CheckChars: digits,
+		Value:      weighted.Digit,
+		Separators: hyphenSp,
+		Prefixes:   prefixes,
+	}
+}
+
+// The schemes are unexported so importers can't change them (the Validate and
+// Compute functions below use them)
+var (
+	// International Standard Book Number (pre 2007)
+	isbn10 = &weighted.Scheme{
+		Name:       "ISBN-10",
+		Length:     10,
+		CheckAt:    -1,
+		Weights:    []int{10, 9, 8, 7, 6, 5, 4, 3, 2},
+		Modulus:    11,
+		Complement: true,
+		CheckChars: digitsX,
+		Value:      weighted.Digit,
+		Separators: hyphenSp,
+	}
+	// International Standard Book Number, an EAN-13 starting 978 or 979
+	isbn13 = gs1("ISBN-13", 13, "978", "979")
+	// International Standard Serial Number
+	issn = &weighted.Scheme{
+		Name:       "ISSN",
+		Length:     8,
+		CheckAt:    -1,
+		Weights:    []int{8, 7, 6, 5, 4, 3, 2},
+		Modulus:    11,
+		Complement: true,
+		CheckChars: digitsX,
+		Value:      weighted.Digit,
+		Separators: hyphenSp,
+	}
+	// European (International) Article Number, 8 digits (GTIN-8)
+	ean8 = gs1("EAN-8", 8)
+	// European (International) Article Number, 13 digits (GTIN-13)
+	ean13 = gs1("EAN-13", 13)
+	// Universal Product Code (GTIN-12)
+	upca = gs1("UPC-A", 12)
+	// Global Trade Item Number, 14 digits
+	gtin14 = gs1("GTIN-14", 14)
+	// American Bankers Association routing transit number
+	aba = &weighted.Scheme{
+		Name:       "ABA",
+		Length:     9,
+		CheckAt:    -1,
+		Weights:    []int{3, 7, 1},
+		Modulus:    10,
+		Complement: true,
+		CheckChars: digits,
+		Value:      weighted.Digit,
+	}
+	// Vehicle Identification Number (North American check digit, 9th character)
+	vin = &weighted.Scheme{
+		Name:    "VIN",
+		Length:  17,
+		CheckAt: 8,
+		//The check position (weight 0) is excluded
+		Weights:    []int{8, 7, 6, 5, 4, 3, 2, 10, 9, 8, 7, 6, 5, 4, 3, 2},
+		Modulus:    11,
+		CheckChars: digitsX,
+		Value:      vinValue,
+	}
+	// Shipping container code (owner, category, serial, check)
+	iso6346 = &weighted.Scheme{
+		Name:       "ISO 6346",
+		Length:     11,
+		CheckAt:    -1,
+		Weights:    []int{1, 2, 4, 8, 16, 32, 64, 128, 256, 512},
+		Modulus:    11,
+		CheckChars: "01234567890", //A remainder of 10 is written 0
+		Value:      iso6346Value,
+		Separators: hyphenSp,
+	}
+)
+
+// Validate an ISBN-10, returns a weighted.LengthError, CharError or CheckError
+func ValidateISBN10(id string) error { return isbn10.Validate(id) }
+
+// The check character of an ISBN-10 missing its check
+func ComputeISBN10(payload string) (byte, error) { return isbn10.Compute(payload) }
+
+// Validate an ISBN-13, returns a weighted.LengthError, CharError, CheckError or ErrPrefix
+func ValidateISBN13(id string) error { return isbn13.Validate(id) }
+
+// The check character of an ISBN-13 missing its check
+func ComputeISBN13(payload string) (byte, error) { return isbn13.Compute(payload) }
+
+// Validate an ISSN, returns a weighted.LengthError, CharError or CheckError
+func ValidateISSN(id string) error { return issn.Validate(id) }
+
+// The check character of an ISSN missing its check
+func ComputeISSN(payload string) (byte, error) { return issn.Compute(payload) }
+
+// Validate an EAN-8, returns a weighted.LengthError, CharError or CheckError
+func ValidateEAN8(id string) error { return ean8.Validate(id) }
+
+// The check character of an EAN-8 missing its check
+func ComputeEAN8(payload string) (byte, error) { return ean8.Compute(payload) }
+
+// Validate an EAN-13, returns a weighted.LengthError, CharError or CheckError
+func ValidateEAN13(id string) error { return ean13.Validate(id) }
+
+// The check character of an EAN-13 missing its check
+func ComputeEAN13(payload string) (byte, error) { return ean13.Compute(payload) }
+
+// Validate a UPC-A, returns a weighted.LengthError, CharError or CheckError
+func ValidateUPCA(id string) error { return upca.Validate(id) }
+
+// The check character of a UPC-A missing its check
+func ComputeUPCA(payload string) (byte, error) { return upca.Compute(payload) }
+
+// Validate a GTIN-14, returns a weighted.LengthError, CharError or CheckError
+func ValidateGTIN14(id string) error { return gtin14.Validate(id) }
+
+// The check character of a GTIN-14 missing its check
+func ComputeGTIN14(payload string) (byte, error) { return gtin14.Compute(payload) }
+
+// Validate an ABA routing number, returns a weighted.LengthError, CharError or CheckError
+func ValidateABA(id string) error { return aba.Validate(id) }
+
+// The check character of an ABA routing number missing its check
+func ComputeABA(payload string) (byte, error) { return aba.Compute(payload) }
+
+// Validate a VIN, returns a weighted.LengthError, CharError or CheckError
+func ValidateVIN(id string) error { return vin.Validate(id) }
+
+// The check character of a VIN missing its check
+func ComputeVIN(payload string) (byte, error) { return vin.Compute(payload) }
+
+// Validate an ISO 6346 container code, returns a weighted.LengthError, CharError or CheckError
+func ValidateISO6346(id string) error { return iso6346.Validate(id) }
+
+// The check character of an ISO 6346 container code missing its check
+func ComputeISO6346(payload string) (byte, error) { return iso6346.Compute(payload) }
+
+// VIN transliteration, I, O and Q aren't allowed
+var vinLetters = [26]int8{
+	1, 2, 3, 4, 5, 6, 7, 8, -1, //A-I
+	1, 2, 3, 4, 5, -1, 7, -1, 9, //J-R
+	2, 3, 4, 5, 6, 7, 8, 9, //S-Z
+}
+
+func vinValue(b byte, at int) int {
+	if b >= 'A' && b <= 'Z' {
+		return int(vinLetters[b-'A'])
+	}
+	return weighted.Digit(b, at)
+}
+
+// Letters count from A=10, skipping multiples of 11
+var iso6346Letters = [26]int8{
+	10, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, //A-K
+	23, 24, 25, 26, 27, 28, 29, 30, 31, 32, //L-U
+	34, 35, 36, 37, 38, //V-Z
+}
+
+func iso6346Value(b byte, at int) int {
+	//Owner code (3) and category (U, J or Z) are letters, then a 6 digit serial
+	if at < 4 {
+		if at == 3 && b != 'U' && b != 'J' && b != 'Z' {
+			return -1
+		}
+		if b >= 'A' && b <= 'Z' {
+			return int(iso6346Letters[b-'A'])
+		}
+		return -1
+	}
+	return weighted.Digit(b, at)
+}
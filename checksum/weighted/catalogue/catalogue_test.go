@@ -0,0 +1,145 @@
+package catalogue
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gnabgib/gnablib-go/checksum/weighted"
+)
+
+// The exported functions for each scheme
+var funcs = map[*weighted.Scheme]struct {
+	validate func(string) error
+	compute  func(string) (byte, error)
+}{
+	isbn10:  {ValidateISBN10, ComputeISBN10},
+	isbn13:  {ValidateISBN13, ComputeISBN13},
+	issn:    {ValidateISSN, ComputeISSN},
+	ean8:    {ValidateEAN8, ComputeEAN8},
+	ean13:   {ValidateEAN13, ComputeEAN13},
+	upca:    {ValidateUPCA, ComputeUPCA},
+	gtin14:  {ValidateGTIN14, ComputeGTIN14},
+	aba:     {ValidateABA, ComputeABA},
+	vin:     {ValidateVIN, ComputeVIN},
+	iso6346: {ValidateISO6346, ComputeISO6346},
+}
+
+var validTests = []struct {
+	s  *weighted.Scheme
+	id string
+}{
+	//Wiki
+	{isbn10, "0-306-40615-2"},
+	{isbn10, "0-8044-2957-X"},
+	{isbn10, "080442957x"},
+	{isbn13, "978-0-306-40615-7"},
+	{issn, "0378-5955"},
+	{issn, "2049-3630"},
+	{ean13, "4006381333931"},
+	{ean13, "5901234123457"},
+	{ean8, "73513537"},
+	{ean8, "96385074"},
+	{upca, "036000291452"},
+	{gtin14, "10012345678902"},
+	{aba, "111000025"},
+	{aba, "021000021"},
+	{vin, "1M8GDM9AXKP042788"},
+	{vin, "11111111111111111"},
+	{vin, "5GZCZ43D13S812715"},
+	{iso6346, "CSQU3054383"},
+	{iso6346, "MSKU 907032 3"},
+	{iso6346, "TGHU1234567"},
+}
+
+func TestValidate(t *testing.T) {
+	for _, rec := range validTests {
+		if err := funcs[rec.s].validate(rec.id); err != nil {
+			t.Errorf("%s %s: unexpected error %v", rec.s.Name, rec.id, err)
+		}
+	}
+}
+
+func TestCompute(t *testing.T) {
+	for _, rec := range validTests {
+		check := rec.s.CheckAt
+		if check < 0 {
+			check = len(rec.id) + check
+		}
+		//Drop the check character (the VIN test values have no separators)
+		payload := rec.id[:check] + rec.id[check+1:]
+		expect := rec.id[check]
+		if expect == 'x' {
+			expect = 'X'
+		}
+		found, err := funcs[rec.s].compute(payload)
+		if err != nil {
+			t.Errorf("%s %s: unexpected error %v", rec.s.Name, payload, err)
+			continue
+		}
+		if found != expect {
+			t.Errorf("%s %s: expecting %c, got %c", rec.s.Name, payload, expect, found)
+		}
+		if full, _ := rec.s.Apply(payload); full != rec.id[:check]+string(expect)+rec.id[check+1:] {
+			t.Errorf("%s %s: Apply got %s", rec.s.Name, payload, full)
+		}
+	}
+}
+
+func TestValidate_errors(t *testing.T) {
+	tests := []struct {
+		s      *weighted.Scheme
+		id     string
+		target error
+		err    error
+	}{
+		{isbn10, "0-306-40615-3", weighted.ErrCheck, weighted.CheckError{Expect: '2', Found: '3'}},
+		{isbn10, "0-306-40615", weighted.ErrLength, weighted.LengthError{Expect: 10, Found: 9}},
+		{isbn10, "0-306-4O615-2", weighted.ErrChar, weighted.CharError{Byte: 'O', At: 7}},
+		{isbn10, "X-306-40615-2", weighted.ErrChar, weighted.CharError{Byte: 'X', At: 0}},
+		{isbn10, "0-306-40615-A", weighted.ErrChar, weighted.CharError{Byte: 'A', At: 12}},
+		{isbn13, "5901234123457", weighted.ErrPrefix, weighted.ErrPrefix},
+		{ean13, "4006381333932", weighted.ErrCheck, weighted.CheckError{Expect: '1', Found: '2'}},
+		{upca, "03600029145X", weighted.ErrChar, weighted.CharError{Byte: 'X', At: 11}},
+		{aba, "111000026", weighted.ErrCheck, weighted.CheckError{Expect: '5', Found: '6'}},
+		{vin, "1M8GDM9A1KP042788", weighted.ErrCheck, weighted.CheckError{Expect: 'X', Found: '1'}},
+		{vin, "1M8GDM9AXKP0427O8", weighted.ErrChar, weighted.CharError{Byte: 'O', At: 15}},
+		{vin, "1M8GDM9AXKP04278", weighted.ErrLength, weighted.LengthError{Expect: 17, Found: 16}},
+		{iso6346, "CSQX3054383", weighted.ErrChar, weighted.CharError{Byte: 'X', At: 3}},
+		{iso6346, "CSQU30543A3", weighted.ErrChar, weighted.CharError{Byte: 'A', At: 9}},
+		{iso6346, "CSQU3054384", weighted.ErrCheck, weighted.CheckError{Expect: '3', Found: '4'}},
+	}
+	for _, rec := range tests {
+		err := funcs[rec.s].validate(rec.id)
+		if !errors.Is(err, rec.target) {
+			t.Errorf("%s %s: expecting %v, got %v", rec.s.Name, rec.id, rec.target, err)
+		}
+		if err != rec.err {
+			t.Errorf("%s %s: expecting %#v, got %#v", rec.s.Name, rec.id, rec.err, err)
+		}
+	}
+}
+
+// Mod 11 schemes catch every single digit error and adjacent transposition
+func TestISBN10_detection(t *testing.T) {
+	id := []byte("0306406152")
+	for i := range id {
+		orig := id[i]
+		for c := byte('0'); c <= '9'; c++ {
+			id[i] = c
+			if c != orig && ValidateISBN10(string(id)) == nil {
+				t.Errorf("Single error %s not detected", id)
+			}
+		}
+		id[i] = orig
+	}
+	for i := 0; i < len(id)-1; i++ {
+		if id[i] == id[i+1] {
+			continue
+		}
+		id[i], id[i+1] = id[i+1], id[i]
+		if ValidateISBN10(string(id)) == nil {
+			t.Errorf("Transposition %s not detected", id)
+		}
+		id[i], id[i+1] = id[i+1], id[i]
+	}
+}
@@ -0,0 +1,250 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+// Weighted sum check characters, the basis of many identifiers (ISBN, EAN, VIN..)
+package weighted
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gnabgib/gnablib-go/checksum"
+)
+
+// The identifier is the wrong length (use errors.Is to test for this)
+var ErrLength = errors.New("wrong length")
+
+// The identifier contains an invalid character (use errors.Is to test for this)
+var ErrChar = errors.New("invalid character")
+
+// The check character doesn't match (use errors.Is to test for this)
+var ErrCheck = errors.New("check character mismatch")
+
+// The identifier doesn't start with an allowed prefix
+var ErrPrefix = errors.New("invalid prefix")
+
+// The scheme's parameters can't work (use errors.Is to test for this)
+var ErrScheme = errors.New("invalid scheme")
+
+// Wrong length, both lengths exclude separators. When the scheme allows any
+// length, Expect is the shortest length with room for the check
+type LengthError struct {
+	Expect int
+	Found  int
+}
+
+func (e LengthError) Error() string {
+	return fmt.Sprintf("Invalid length: expected %d, found %d", e.Expect, e.Found)
+}
+
+func (e LengthError) Unwrap() error { return ErrLength }
+
+// An invalid character, at its position in the input (including separators)
+type CharError struct {
+	Byte byte
+	At   int
+}
+
+func (e CharError) Error() string {
+	return fmt.Sprintf("Invalid character: %q @ %d", e.Byte, e.At)
+}
+
+func (e CharError) Unwrap() error { return ErrChar }
+
+// The check character found, and the one expected
+type CheckError struct {
+	Expect byte
+	Found  byte
+}
+
+func (e CheckError) Error() string {
+	return fmt.Sprintf("Check mismatch: expected %c, found %c", e.Expect, e.Found)
+}
+
+func (e CheckError) Unwrap() error { return ErrCheck }
+
+// A scheme with unusable parameters
+type SchemeError struct {
+	Name   string
+	Reason string
+}
+
+func (e SchemeError) Error() string {
+	return fmt.Sprintf("Invalid scheme %s: %s", e.Name, e.Reason)
+}
+
+func (e SchemeError) Unwrap() error { return ErrScheme }
+
+// A weighted sum check scheme, input is upper cased before use
+type Scheme struct {
+	Name       string
+	Length     int   //Symbols including the check (separators excluded), 0 for any
+	CheckAt    int   //Index of the check symbol, negative counts from the end (-1 is last)
+	Weights    []int //Payload weights, repeated as needed
+	FromRight  bool  //Align the weights with the rightmost payload symbol (rather than the leftmost)
+	Modulus    int
+	Complement bool   //Check is (Modulus-sum%Modulus)%Modulus, rather than sum%Modulus
+	CheckChars string //Check symbol for each remainder 0..Modulus-1
+	//Value of the payload symbol `b` at index `at` (in the full identifier), -1 when invalid
+	Value      func(b byte, at int) int
+	Separators string   //Symbols ignored in input
+	Prefixes   []string //When set, the identifier must start with one of these
+}
+
+// Value of a decimal digit, -1 otherwise
+func Digit(b byte, at int) int {
+	if b >= '0' && b <= '9' {
+		return int(b - '0')
+	}
+	return -1
+}
+
+// Make sure the parameters are usable, so Compute and Validate can't panic
+func (s *Scheme) check() error {
+	reason := ""
+	switch {
+	case s.Modulus < 1:
+		reason = "modulus must be at least 1"
+	case len(s.Weights) == 0:
+		reason = "no weights"
+	case len(s.CheckChars) < s.Modulus:
+		reason = "fewer check characters than the modulus"
+	case s.Value == nil:
+		reason = "no value function"
+	case s.Length > 0 && s.Length < s.minLength():
+		reason = "check position is outside the length"
+	default:
+		return nil
+	}
+	return SchemeError{s.Name, reason}
+}
+
+// Shortest identifier (including the check) with room for the check symbol
+func (s *Scheme) minLength() int {
+	if s.CheckAt < 0 {
+		return -s.CheckAt
+	}
+	return s.CheckAt + 1
+}
+
+// Remove separators and upper case, keeping the original position of each symbol
+func (s *Scheme) clean(id string) (syms []byte, pos []int) {
+	for i := 0; i < len(id); i++ {
+		b := id[i]
+		if strings.IndexByte(s.Separators, b) >= 0 {
+			continue
+		}
+		syms = append(syms, checksum.Upper(b))
+		pos = append(pos, i)
+	}
+	return
+}
+
+// Index of the check symbol in an identifier of length n
+func (s *Scheme) checkIndex(n int) int {
+	if s.CheckAt < 0 {
+		return n + s.CheckAt
+	}
+	return s.CheckAt
+}
+
+// The check symbol for `payload` (the full identifier without the check),
+// `pos` is the position of each payload symbol in `orig`
+func (s *Scheme) compute(orig string, payload []byte, pos []int, check int) (byte, error) {
+	n := len(payload)
+	id := string(payload[:check]) + "?" + string(payload[check:])
+	if len(s.Prefixes) > 0 {
+		ok := false
+		for _, pre := range s.Prefixes {
+			ok = ok || strings.HasPrefix(id, pre)
+		}
+		if !ok {
+			return 0, ErrPrefix
+		}
+	}
+	sum := 0
+	for i, b := range payload {
+		at := i
+		if i >= check {
+			at++
+		}
+		v := s.Value(b, at)
+		if v < 0 {
+			return 0, CharError{Byte: orig[pos[i]], At: pos[i]}
+		}
+		var w int
+		if s.FromRight {
+			w = s.Weights[(n-1-i)%len(s.Weights)]
+		} else {
+			w = s.Weights[i%len(s.Weights)]
+		}
+		sum += w * v
+	}
+	r := sum % s.Modulus
+	if s.Complement {
+		r = (s.Modulus - r) % s.Modulus
+	}
+	return s.CheckChars[r], nil
+}
+
+// Compute the check symbol for `payload` (the identifier without its check)
+func (s *Scheme) Compute(payload string) (byte, error) {
+	if err := s.check(); err != nil {
+		return 0, err
+	}
+	syms, pos := s.clean(payload)
+	if s.Length > 0 && len(syms) != s.Length-1 {
+		return 0, LengthError{Expect: s.Length - 1, Found: len(syms)}
+	}
+	check := s.checkIndex(len(syms) + 1)
+	if check < 0 || check > len(syms) {
+		return 0, LengthError{Expect: s.minLength() - 1, Found: len(syms)}
+	}
+	return s.compute(payload, syms, pos, check)
+}
+
+// Insert the check symbol into `payload`
+func (s *Scheme) Apply(payload string) (string, error) {
+	c, err := s.Compute(payload)
+	if err != nil {
+		return "", err
+	}
+	syms, pos := s.clean(payload)
+	check := s.checkIndex(len(syms) + 1)
+	if check == len(syms) {
+		return payload + string(c), nil
+	}
+	at := pos[check]
+	return payload[:at] + string(c) + payload[at:], nil
+}
+
+// Validate an identifier, returns a LengthError, CharError, CheckError,
+// ErrPrefix or (for unusable parameters) a SchemeError
+func (s *Scheme) Validate(id string) error {
+	if err := s.check(); err != nil {
+		return err
+	}
+	syms, pos := s.clean(id)
+	if s.Length > 0 && len(syms) != s.Length {
+		return LengthError{Expect: s.Length, Found: len(syms)}
+	}
+	check := s.checkIndex(len(syms))
+	if check < 0 || check >= len(syms) {
+		return LengthError{Expect: s.minLength(), Found: len(syms)}
+	}
+	found := syms[check]
+	if strings.IndexByte(s.CheckChars, found) < 0 {
+		return CharError{Byte: id[pos[check]], At: pos[check]}
+	}
+	payload := append(append([]byte{}, syms[:check]...), syms[check+1:]...)
+	payloadPos := append(append([]int{}, pos[:check]...), pos[check+1:]...)
+	expect, err := s.compute(id, payload, payloadPos, check)
+	if err != nil {
+		return err
+	}
+	if expect != found {
+		return CheckError{Expect: expect, Found: found}
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package weighted
+
+import (
+	"errors"
+	"testing"
+)
+
+// A mod 7 scheme with the check in the middle, weights 1,2,3 from the right
+var testScheme = &Scheme{
+	Name:       "test",
+	CheckAt:    2,
+	Weights:    []int{1, 2, 3},
+	FromRight:  true,
+	Modulus:    7,
+	Complement: false,
+	CheckChars: "ABCDEFG",
+	Value:      Digit,
+	Separators: ".",
+}
+
+func TestScheme(t *testing.T) {
+	tests := []struct {
+		payload string
+		expect  byte
+		full    string
+	}{
+		//Sum of 12 34 from the right: 4*1+3*2+2*3+1*1=17%7=3
+		{"1234", 'D', "12D34"},
+		{"12.34", 'D', "12.D34"},
+		{"00", 'A', "00A"},
+		{"99999", 'E', "99E999"},
+	}
+	for _, rec := range tests {
+		found, err := testScheme.Compute(rec.payload)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", rec.payload, err)
+			continue
+		}
+		if found != rec.expect {
+			t.Errorf("%s: expecting %c, got %c", rec.payload, rec.expect, found)
+		}
+		full, _ := testScheme.Apply(rec.payload)
+		if full != rec.full {
+			t.Errorf("%s: expecting %s, got %s", rec.payload, rec.full, full)
+		}
+		if err := testScheme.Validate(full); err != nil {
+			t.Errorf("%s: unexpected error %v", full, err)
+		}
+	}
+}
+
+func TestScheme_errors(t *testing.T) {
+	if err := testScheme.Validate("12E34"); err != (CheckError{Expect: 'D', Found: 'E'}) {
+		t.Errorf("Expecting check error, got %v", err)
+	}
+	if err := testScheme.Validate("12H34"); err != (CharError{Byte: 'H', At: 2}) {
+		t.Errorf("Expecting char error, got %v", err)
+	}
+	if err := testScheme.Validate("1bD34"); err != (CharError{Byte: 'b', At: 1}) {
+		t.Errorf("Expecting char error, got %v", err)
+	}
+	if _, err := testScheme.Compute("1"); err != (LengthError{Expect: 2, Found: 1}) {
+		t.Errorf("Expecting length error, got %v", err)
+	}
+	if err := testScheme.Validate("1D"); err != (LengthError{Expect: 3, Found: 2}) {
+		t.Errorf("Expecting length error, got %v", err)
+	}
+	if _, err := testScheme.Apply("1"); !errors.Is(err, ErrLength) {
+		t.Errorf("Expecting ErrLength, got %v", err)
+	}
+}
+
+func TestScheme_invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		edit func(s *Scheme)
+	}{
+		{"zero modulus", func(s *Scheme) { s.Modulus = 0 }},
+		{"no weights", func(s *Scheme) { s.Weights = nil }},
+		{"short check chars", func(s *Scheme) { s.CheckChars = "ABC" }},
+		{"no value", func(s *Scheme) { s.Value = nil }},
+		{"check past length", func(s *Scheme) { s.Length = 2 }},
+		{"check before length", func(s *Scheme) { s.Length, s.CheckAt = 2, -3 }},
+	}
+	for _, rec := range tests {
+		s := *testScheme
+		rec.edit(&s)
+		if _, err := s.Compute("1234"); !errors.Is(err, ErrScheme) {
+			t.Errorf("%s: Compute expecting ErrScheme, got %v", rec.name, err)
+		}
+		if _, err := s.Apply("1234"); !errors.Is(err, ErrScheme) {
+			t.Errorf("%s: Apply expecting ErrScheme, got %v", rec.name, err)
+		}
+		if err := s.Validate("12D34"); !errors.Is(err, ErrScheme) {
+			t.Errorf("%s: Validate expecting ErrScheme, got %v", rec.name, err)
+		}
+	}
+}
+
+func TestErrorText(t *testing.T) {
+	tests := []struct {
+		err    error
+		expect string
+	}{
+		{LengthError{10, 9}, "Invalid length: expected 10, found 9"},
+		{CharError{'x', 3}, "Invalid character: 'x' @ 3"},
+		{CheckError{'1', '2'}, "Check mismatch: expected 1, found 2"},
+		{SchemeError{"test", "no weights"}, "Invalid scheme test: no weights"},
+	}
+	for _, rec := range tests {
+		if found := rec.err.Error(); found != rec.expect {
+			t.Errorf("Expecting %q, got %q", rec.expect, found)
+		}
+	}
+}
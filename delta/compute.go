@@ -0,0 +1,155 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+package delta
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+
+	"github.com/gnabgib/gnablib-go/checksum/rolling"
+)
+
+// Literals are written in pieces of at most this many bytes
+const maxLiteral = 1 << 16
+
+// Buffers delta commands, merging consecutive copies
+type encoder struct {
+	w       *bufio.Writer
+	copyOff int64
+	copyLen int64
+	tmp     [2 * binary.MaxVarintLen64]byte
+}
+
+func (e *encoder) flushCopy() error {
+	if e.copyLen == 0 {
+		return nil
+	}
+	e.w.WriteByte(opCopy)
+	n := binary.PutUvarint(e.tmp[:], uint64(e.copyOff))
+	n += binary.PutUvarint(e.tmp[n:], uint64(e.copyLen))
+	_, err := e.w.Write(e.tmp[:n])
+	e.copyLen = 0
+	return err
+}
+
+func (e *encoder) copy(off, length int64) error {
+	if e.copyLen > 0 && e.copyOff+e.copyLen == off {
+		e.copyLen += length
+		return nil
+	}
+	if err := e.flushCopy(); err != nil {
+		return err
+	}
+	e.copyOff, e.copyLen = off, length
+	return nil
+}
+
+func (e *encoder) literal(p []byte) error {
+	if len(p) == 0 {
+		return nil
+	}
+	if err := e.flushCopy(); err != nil {
+		return err
+	}
+	e.w.WriteByte(opLiteral)
+	n := binary.PutUvarint(e.tmp[:], uint64(len(p)))
+	e.w.Write(e.tmp[:n])
+	_, err := e.w.Write(p)
+	return err
+}
+
+// Compute the delta of the new content in `r` against the basis signature,
+// and write it to `w`
+func Compute(sig *Signature, r io.Reader, w io.Writer) error {
+	sig.buildIndex()
+	e := &encoder{w: bufio.NewWriter(w)}
+	e.w.WriteString(deltaMagic)
+	e.w.WriteByte(Version)
+	e.w.WriteByte(byte(sig.Hash))
+
+	bs := sig.BlockSize
+	weak, _ := rolling.NewAdler(bs)
+	strong := sig.Hash.New()
+	whole := sig.Hash.New()
+	var sum []byte
+	//buf holds pending literal bytes (buf[:pos]) followed by the window
+	buf := make([]byte, 0, maxLiteral+bs)
+	pos := 0
+	fresh := true
+	var out byte //The byte that left the window on the last slide
+	eof := false
+	for {
+		for !eof && len(buf) < pos+bs {
+			n, err := r.Read(buf[len(buf):cap(buf)])
+			whole.Write(buf[len(buf) : len(buf)+n])
+			buf = buf[:len(buf)+n]
+			if err == io.EOF {
+				eof = true
+			} else if err != nil {
+				return err
+			}
+		}
+		if len(buf) < pos+bs {
+			//The basis' short last block can only match the end
+			tail := buf[pos:]
+			idx := -1
+			if len(tail) > 0 && sig.Blocks() > 0 {
+				weak.Reset()
+				weak.Write(tail)
+				idx, sum = sig.find(weak.Sum32(), tail, strong, sum)
+			}
+			if idx < 0 {
+				if err := e.literal(buf); err != nil {
+					return err
+				}
+			} else {
+				if err := e.literal(buf[:pos]); err != nil {
+					return err
+				}
+				if err := e.copy(int64(idx)*int64(bs), int64(len(tail))); err != nil {
+					return err
+				}
+			}
+			break
+		}
+		win := buf[pos : pos+bs]
+		if fresh {
+			weak.Reset()
+			weak.Write(win)
+			fresh = false
+		} else {
+			weak.Roll(out, win[bs-1])
+		}
+		var idx int
+		idx, sum = sig.find(weak.Sum32(), win, strong, sum)
+		if idx >= 0 {
+			if err := e.literal(buf[:pos]); err != nil {
+				return err
+			}
+			if err := e.copy(int64(idx)*int64(bs), int64(bs)); err != nil {
+				return err
+			}
+			buf = buf[:copy(buf, buf[pos+bs:])]
+			pos = 0
+			fresh = true
+			continue
+		}
+		out = buf[pos]
+		pos++
+		if pos == maxLiteral {
+			if err := e.literal(buf[:pos]); err != nil {
+				return err
+			}
+			buf = buf[:copy(buf, buf[pos:])]
+			pos = 0
+		}
+	}
+	if err := e.flushCopy(); err != nil {
+		return err
+	}
+	e.w.WriteByte(opEnd)
+	e.w.Write(whole.Sum(nil))
+	return e.w.Flush()
+}
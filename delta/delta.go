@@ -0,0 +1,134 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+// rsync style file synchronisation: build a block signature of the old (basis)
+// file, compute a delta of the new file against the signature, then patch a
+// copy of the basis to recreate the new file.
+//
+// Signature format (version 1), all integers big endian:
+//
+//	off  size  field
+//	0    4     magic "gsig"
+//	4    1     version (1)
+//	5    1     strong hash (see StrongHash)
+//	6    1     strong digest length in bytes (truncated, 1..digest size)
+//	7    4     block size in bytes
+//	11   8     basis length in bytes
+//	19   ...   one record per block (the last may be short): the rolling
+//	           Adler-32 style weak sum (4 bytes) then the truncated strong digest
+//
+// Delta format (version 1):
+//
+//	off  size  field
+//	0    4     magic "gdlt"
+//	4    1     version (1)
+//	5    1     strong hash used for the whole file digest
+//	6    ...   commands, each a type byte followed by its arguments:
+//	           0x01 copy: uvarint basis offset, uvarint length
+//	           0x02 literal: uvarint length, then that many bytes
+//	           0x00 end: the full strong digest of the new file, nothing may
+//	           follow
+package delta
+
+//https://rsync.samba.org/tech_report/
+//https://www.samba.org/~tridge/phd_thesis.pdf
+
+import (
+	"errors"
+	"fmt"
+	"hash"
+
+	"github.com/gnabgib/gnablib-go/hash/ripemd"
+	"github.com/gnabgib/gnablib-go/hash/whirlpool"
+)
+
+// Current version of the signature and delta formats
+const Version = 1
+
+const (
+	sigMagic   = "gsig"
+	deltaMagic = "gdlt"
+	sigHeader  = 19
+)
+
+const (
+	opEnd     = 0x00
+	opCopy    = 0x01
+	opLiteral = 0x02
+)
+
+// The input isn't a valid signature or delta (use errors.Is to test for this)
+var ErrCorrupt = errors.New("corrupt delta input")
+
+// The input was written by an unsupported format version
+var ErrVersion = errors.New("unsupported delta format version")
+
+// The strong hash is unknown
+var ErrHash = errors.New("unknown strong hash")
+
+// The block size or strong length is out of range
+var ErrParams = errors.New("invalid signature params")
+
+// Corrupt input, includes the byte offset where the problem was found
+type CorruptError struct {
+	Offset int64
+	Reason string
+}
+
+func (e CorruptError) Error() string {
+	return fmt.Sprintf("Corrupt delta input @ %d: %s", e.Offset, e.Reason)
+}
+
+func (e CorruptError) Unwrap() error { return ErrCorrupt }
+
+// A strong hash from this library, the value is stored in the wire format
+type StrongHash uint8
+
+const (
+	RIPEMD160 StrongHash = iota + 1
+	RIPEMD128
+	RIPEMD256
+	RIPEMD320
+	Whirlpool
+)
+
+// A new instance of the hash, nil if it's unknown
+func (s StrongHash) New() hash.Hash {
+	switch s {
+	case RIPEMD160:
+		return ripemd.New160()
+	case RIPEMD128:
+		return ripemd.New128()
+	case RIPEMD256:
+		return ripemd.New256()
+	case RIPEMD320:
+		return ripemd.New320()
+	case Whirlpool:
+		return whirlpool.New()
+	}
+	return nil
+}
+
+// Largest block size
+const MaxBlockSize = 1 << 24
+
+// Block size and strong digest settings for a signature
+type Params struct {
+	BlockSize int
+	Hash      StrongHash
+	StrongLen int //Truncate the strong digest to this many bytes
+}
+
+// 2KiB blocks with RIPEMD-160 truncated to 8 bytes
+var Default = Params{BlockSize: 2048, Hash: RIPEMD160, StrongLen: 8}
+
+func (p *Params) validate() error {
+	h := p.Hash.New()
+	if h == nil {
+		return ErrHash
+	}
+	if p.BlockSize < 1 || p.BlockSize > MaxBlockSize || p.StrongLen < 1 || p.StrongLen > h.Size() {
+		return ErrParams
+	}
+	return nil
+}
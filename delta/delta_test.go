@@ -0,0 +1,238 @@
+package delta
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"testing"
+
+	gHash "github.com/gnabgib/gnablib-go/hash"
+)
+
+func roundTrip(t *testing.T, basis, target []byte, p Params) []byte {
+	t.Helper()
+	sig, err := NewSignature(bytes.NewReader(basis), p)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	//Always go through the wire format
+	var sigBuf bytes.Buffer
+	sig.WriteTo(&sigBuf)
+	sig, err = ReadSignature(&sigBuf)
+	if err != nil {
+		t.Fatalf("Unexpected error reading signature %v", err)
+	}
+	var d bytes.Buffer
+	if err := Compute(sig, bytes.NewReader(target), &d); err != nil {
+		t.Fatalf("Unexpected error computing delta %v", err)
+	}
+	var out bytes.Buffer
+	if err := Patch(bytes.NewReader(basis), bytes.NewReader(d.Bytes()), &out); err != nil {
+		t.Fatalf("Unexpected error patching %v", err)
+	}
+	if !bytes.Equal(out.Bytes(), target) {
+		t.Fatalf("Patched content doesn't match (%d vs %d bytes)", out.Len(), len(target))
+	}
+	return d.Bytes()
+}
+
+// Apply a random edit: insert, delete, overwrite, append or truncate
+func edit(rnd *rand.Rand, b []byte) []byte {
+	ret := append([]byte{}, b...)
+	at := 0
+	if len(ret) > 0 {
+		at = rnd.Intn(len(ret))
+	}
+	n := 1 + rnd.Intn(300)
+	chunk := make([]byte, n)
+	rnd.Read(chunk)
+	switch rnd.Intn(5) {
+	case 0:
+		ret = append(ret[:at], append(chunk, ret[at:]...)...)
+	case 1:
+		if at+n > len(ret) {
+			n = len(ret) - at
+		}
+		ret = append(ret[:at], ret[at+n:]...)
+	case 2:
+		copy(ret[at:], chunk)
+	case 3:
+		ret = append(ret, chunk...)
+	case 4:
+		ret = ret[:at]
+	}
+	return ret
+}
+
+func TestRoundTripRandomEdits(t *testing.T) {
+	rnd := rand.New(rand.NewSource(2048))
+	params := []Params{
+		Default,
+		{BlockSize: 1, Hash: RIPEMD128, StrongLen: 4},
+		{BlockSize: 7, Hash: RIPEMD256, StrongLen: 32},
+		{BlockSize: 64, Hash: RIPEMD320, StrongLen: 2},
+		{BlockSize: 700, Hash: Whirlpool, StrongLen: 16},
+	}
+	for _, p := range params {
+		for i := 0; i < 20; i++ {
+			basis := make([]byte, rnd.Intn(20000))
+			rnd.Read(basis)
+			target := basis
+			for e := rnd.Intn(6); e >= 0; e-- {
+				target = edit(rnd, target)
+			}
+			roundTrip(t, basis, target, p)
+		}
+	}
+}
+
+func TestRoundTripEdges(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	big := make([]byte, 300000)
+	rnd.Read(big)
+	tests := []struct {
+		name          string
+		basis, target []byte
+	}{
+		{"empty", nil, nil},
+		{"empty basis", nil, []byte("gnabgib")},
+		{"empty target", []byte("gnabgib"), nil},
+		{"shorter than a block", []byte("gnabgib"), []byte("gnabgib")},
+		//Literals are split and rolling continues across the split
+		{"long literal", big[:1000], append(append([]byte{}, big[100000:]...), big[:1000]...)},
+		{"repeated blocks", bytes.Repeat([]byte("a"), 10000), bytes.Repeat([]byte("a"), 12345)},
+	}
+	for _, rec := range tests {
+		t.Run(rec.name, func(t *testing.T) {
+			roundTrip(t, rec.basis, rec.target, Default)
+		})
+	}
+}
+
+func TestDeltaSize(t *testing.T) {
+	rnd := rand.New(rand.NewSource(3))
+	basis := make([]byte, 100000)
+	rnd.Read(basis)
+	//Identical content is a single copy (header, copy, end+digest)
+	if d := roundTrip(t, basis, basis, Default); len(d) > 6+1+1+3+1+20 {
+		t.Errorf("Expecting a single copy, got %d bytes", len(d))
+	}
+	//A small insert only adds about the insert (and a block) to the delta
+	target := append(append(append([]byte{}, basis[:50000]...), []byte("gnabgib")...), basis[50000:]...)
+	if d := roundTrip(t, basis, target, Default); len(d) > 100+Default.BlockSize {
+		t.Errorf("Expecting a small delta, got %d bytes", len(d))
+	}
+}
+
+func TestReadSignatureCorrupt(t *testing.T) {
+	sig, _ := NewSignature(bytes.NewReader([]byte("gnabgib gnabgib gnabgib")), Params{BlockSize: 8, Hash: RIPEMD160, StrongLen: 4})
+	var buf bytes.Buffer
+	sig.WriteTo(&buf)
+	good := buf.Bytes()
+	if len(good) != sigHeader+3*8 {
+		t.Fatalf("Expecting %d bytes, got %d", sigHeader+3*8, len(good))
+	}
+	for i := 0; i < len(good); i++ {
+		_, err := ReadSignature(bytes.NewReader(good[:i]))
+		var ce CorruptError
+		if !errors.As(err, &ce) || ce.Offset != int64(i) {
+			t.Errorf("Truncated to %d expecting CorruptError @%d, got %v", i, i, err)
+		}
+	}
+	tests := []struct {
+		name   string
+		at     int
+		b      byte
+		offset int64
+	}{
+		{"magic", 0, 'G', 0},
+		{"hash", 5, 99, 5},
+		{"strong length", 6, 21, 6},
+		{"zero strong length", 6, 0, 6},
+		{"block size", 7, 0xff, 7},
+		{"zero block size", 10, 0, 7},
+	}
+	for _, rec := range tests {
+		bad := append([]byte{}, good...)
+		bad[rec.at] = rec.b
+		_, err := ReadSignature(bytes.NewReader(bad))
+		var ce CorruptError
+		if !errors.As(err, &ce) || ce.Offset != rec.offset {
+			t.Errorf("%s expecting CorruptError @%d, got %v", rec.name, rec.offset, err)
+		}
+	}
+	bad := append([]byte{}, good...)
+	bad[4] = 2
+	if _, err := ReadSignature(bytes.NewReader(bad)); err != ErrVersion {
+		t.Errorf("Expecting ErrVersion, got %v", err)
+	}
+	_, err := ReadSignature(bytes.NewReader(append(good, 0)))
+	if !errors.Is(err, ErrCorrupt) {
+		t.Errorf("Expecting ErrCorrupt for trailing data, got %v", err)
+	}
+}
+
+func TestPatchCorrupt(t *testing.T) {
+	basis := []byte("gnabgib gnabgib gnabgib gnabgib")
+	target := []byte("gnabgib gnabgib extra gnabgib gnabgib")
+	sig, _ := NewSignature(bytes.NewReader(basis), Params{BlockSize: 8, Hash: RIPEMD160, StrongLen: 8})
+	var d bytes.Buffer
+	Compute(sig, bytes.NewReader(target), &d)
+	good := d.Bytes()
+	var out bytes.Buffer
+	for i := 0; i < len(good); i++ {
+		err := Patch(bytes.NewReader(basis), bytes.NewReader(good[:i]), &out)
+		if !errors.Is(err, ErrCorrupt) {
+			t.Errorf("Truncated to %d expecting ErrCorrupt, got %v", i, err)
+		}
+	}
+	//Changed literal content fails the digest
+	bad := append([]byte{}, good...)
+	lit := bytes.Index(bad, []byte("extra"))
+	bad[lit] = 'E'
+	if err := Patch(bytes.NewReader(basis), bytes.NewReader(bad), &out); !errors.Is(err, gHash.ErrDigestMismatch) {
+		t.Errorf("Expecting ErrDigestMismatch, got %v", err)
+	}
+	//Copy beyond the basis, reported at the command
+	bad = []byte(deltaMagic + "\x01\x01" + "\x01\x1c\x08")
+	var ce CorruptError
+	if err := Patch(bytes.NewReader(basis), bytes.NewReader(bad), &out); !errors.As(err, &ce) || ce.Offset != 6 {
+		t.Errorf("Expecting CorruptError @6, got %v", err)
+	}
+	bad = []byte(deltaMagic + "\x01\x01" + "\x02\x01g\x07")
+	if err := Patch(bytes.NewReader(basis), bytes.NewReader(bad), &out); !errors.As(err, &ce) || ce.Offset != 9 {
+		t.Errorf("Expecting CorruptError @9, got %v", err)
+	}
+	//An over long varint
+	bad = []byte(deltaMagic + "\x01\x01" + "\x02\xff\xff\xff\xff\xff\xff\xff\xff\xff\x01")
+	if err := Patch(bytes.NewReader(basis), bytes.NewReader(bad), &out); !errors.As(err, &ce) || ce.Offset != 7 {
+		t.Errorf("Expecting CorruptError @7, got %v", err)
+	}
+	bad = append(append([]byte{}, good...), 0)
+	if err := Patch(bytes.NewReader(basis), bytes.NewReader(bad), &out); !errors.As(err, &ce) || ce.Offset != int64(len(good)) {
+		t.Errorf("Expecting CorruptError @%d, got %v", len(good), err)
+	}
+	bad = append([]byte{}, good...)
+	bad[4] = 9
+	if err := Patch(bytes.NewReader(basis), bytes.NewReader(bad), &out); err != ErrVersion {
+		t.Errorf("Expecting ErrVersion, got %v", err)
+	}
+}
+
+func TestParams(t *testing.T) {
+	tests := []struct {
+		p   Params
+		err error
+	}{
+		{Params{BlockSize: 0, Hash: RIPEMD160, StrongLen: 8}, ErrParams},
+		{Params{BlockSize: MaxBlockSize + 1, Hash: RIPEMD160, StrongLen: 8}, ErrParams},
+		{Params{BlockSize: 16, Hash: RIPEMD160, StrongLen: 21}, ErrParams},
+		{Params{BlockSize: 16, Hash: RIPEMD160, StrongLen: 0}, ErrParams},
+		{Params{BlockSize: 16, Hash: 0, StrongLen: 8}, ErrHash},
+	}
+	for _, rec := range tests {
+		if _, err := NewSignature(bytes.NewReader(nil), rec.p); err != rec.err {
+			t.Errorf("%v expecting %v, got %v", rec.p, rec.err, err)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+package delta
+
+import (
+	"bufio"
+	"io"
+
+	gHash "github.com/gnabgib/gnablib-go/hash"
+)
+
+// Tracks the read offset for error reporting
+type countingReader struct {
+	r   *bufio.Reader
+	off int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.off += int64(n)
+	return n, err
+}
+
+func (c *countingReader) ReadByte() (byte, error) {
+	b, err := c.r.ReadByte()
+	if err == nil {
+		c.off++
+	}
+	return b, err
+}
+
+// Read a uvarint, invalid or truncated values are a CorruptError
+func (c *countingReader) uvarint(what string) (uint64, error) {
+	at := c.off
+	var v uint64
+	for shift := uint(0); shift < 63; shift += 7 {
+		b, err := c.ReadByte()
+		if err != nil {
+			return 0, readErr(err, c.off, "truncated "+what)
+		}
+		v |= uint64(b&0x7f) << shift
+		if b < 0x80 {
+			return v, nil
+		}
+	}
+	return 0, CorruptError{at, what + " out of range"}
+}
+
+// Recreate the new content by applying the delta read from `d` to the basis,
+// writing the result to `w`. Returns a CorruptError (with the offset in the
+// delta) for invalid input, a hash.DigestMismatchError if the result doesn't
+// match the new content's digest, or ErrVersion
+func Patch(basis io.ReaderAt, d io.Reader, w io.Writer) error {
+	r := &countingReader{r: bufio.NewReader(d)}
+	hdr := make([]byte, 6)
+	if n, err := io.ReadFull(r, hdr); err != nil {
+		return readErr(err, int64(n), "truncated delta header")
+	}
+	if string(hdr[:4]) != deltaMagic {
+		return CorruptError{0, "not a delta"}
+	}
+	if hdr[4] != Version {
+		return ErrVersion
+	}
+	whole := StrongHash(hdr[5]).New()
+	if whole == nil {
+		return CorruptError{5, ErrHash.Error()}
+	}
+	out := io.MultiWriter(w, whole)
+	for {
+		at := r.off
+		op, err := r.ReadByte()
+		if err != nil {
+			return readErr(err, at, "missing end")
+		}
+		switch op {
+		case opCopy:
+			off, err := r.uvarint("copy offset")
+			if err != nil {
+				return err
+			}
+			length, err := r.uvarint("copy length")
+			if err != nil {
+				return err
+			}
+			n, err := io.Copy(out, io.NewSectionReader(basis, int64(off), int64(length)))
+			if err != nil {
+				return err
+			}
+			if n != int64(length) {
+				return CorruptError{at, "copy beyond the end of the basis"}
+			}
+		case opLiteral:
+			length, err := r.uvarint("literal length")
+			if err != nil {
+				return err
+			}
+			if _, err := io.CopyN(out, r, int64(length)); err != nil {
+				return readErr(err, r.off, "truncated literal")
+			}
+		case opEnd:
+			expect := make([]byte, whole.Size())
+			if _, err := io.ReadFull(r, expect); err != nil {
+				return readErr(err, r.off, "truncated digest")
+			}
+			if _, err := r.ReadByte(); err == nil {
+				return CorruptError{r.off - 1, "data after the end"}
+			}
+			return gHash.Verify(expect, whole.Sum(nil))
+		default:
+			return CorruptError{at, "unknown command"}
+		}
+	}
+}
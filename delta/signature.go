@@ -0,0 +1,189 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+package delta
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash"
+	"io"
+
+	"github.com/gnabgib/gnablib-go/checksum/rolling"
+)
+
+// Weak and strong sums of each block of a basis file
+type Signature struct {
+	Params
+	Length int64    //Basis length in bytes
+	weak   []uint32 //Per block
+	strong []byte   //StrongLen bytes per block
+	index  map[uint32][]int32
+}
+
+// Number of blocks
+func (s *Signature) Blocks() int { return len(s.weak) }
+
+// The sums of a block
+func (s *Signature) block(i int) (weak uint32, strong []byte) {
+	return s.weak[i], s.strong[i*s.StrongLen : (i+1)*s.StrongLen]
+}
+
+// Length of the last block (which may be short)
+func (s *Signature) lastLen() int {
+	if n := int(s.Length % int64(s.BlockSize)); n > 0 {
+		return n
+	}
+	return s.BlockSize
+}
+
+// Build the signature of the basis content in `r`
+func NewSignature(r io.Reader, p Params) (*Signature, error) {
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	s := &Signature{Params: p}
+	weak, _ := rolling.NewAdler(p.BlockSize)
+	strong := p.Hash.New()
+	buf := make([]byte, p.BlockSize)
+	var sum []byte
+	for {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			weak.Reset()
+			weak.Write(buf[:n])
+			strong.Reset()
+			strong.Write(buf[:n])
+			sum = strong.Sum(sum[:0])
+			s.weak = append(s.weak, weak.Sum32())
+			s.strong = append(s.strong, sum[:p.StrongLen]...)
+			s.Length += int64(n)
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Write the signature in the wire format
+func (s *Signature) WriteTo(w io.Writer) (int64, error) {
+	hdr := make([]byte, sigHeader, sigHeader+len(s.weak)*(4+s.StrongLen))
+	copy(hdr, sigMagic)
+	hdr[4] = Version
+	hdr[5] = byte(s.Hash)
+	hdr[6] = byte(s.StrongLen)
+	binary.BigEndian.PutUint32(hdr[7:], uint32(s.BlockSize))
+	binary.BigEndian.PutUint64(hdr[11:], uint64(s.Length))
+	for i := range s.weak {
+		weak, strong := s.block(i)
+		hdr = binary.BigEndian.AppendUint32(hdr, weak)
+		hdr = append(hdr, strong...)
+	}
+	n, err := w.Write(hdr)
+	return int64(n), err
+}
+
+// Read a signature in the wire format. Returns a CorruptError (with the offset)
+// if the content is invalid or truncated, or ErrVersion
+func ReadSignature(r io.Reader) (*Signature, error) {
+	hdr := make([]byte, sigHeader)
+	if n, err := io.ReadFull(r, hdr); err != nil {
+		return nil, readErr(err, int64(n), "truncated signature header")
+	}
+	if string(hdr[:4]) != sigMagic {
+		return nil, CorruptError{0, "not a signature"}
+	}
+	if hdr[4] != Version {
+		return nil, ErrVersion
+	}
+	s := &Signature{Params: Params{
+		Hash:      StrongHash(hdr[5]),
+		StrongLen: int(hdr[6]),
+		BlockSize: int(binary.BigEndian.Uint32(hdr[7:])),
+	}}
+	//Report the offset of the field at fault (validate can't say which)
+	h := s.Hash.New()
+	if h == nil {
+		return nil, CorruptError{5, ErrHash.Error()}
+	}
+	if s.StrongLen < 1 || s.StrongLen > h.Size() {
+		return nil, CorruptError{6, "strong sum length out of range"}
+	}
+	if s.BlockSize < 1 || s.BlockSize > MaxBlockSize {
+		return nil, CorruptError{7, "block size out of range"}
+	}
+	length := binary.BigEndian.Uint64(hdr[11:])
+	if length > 1<<62 {
+		return nil, CorruptError{11, "basis length out of range"}
+	}
+	s.Length = int64(length)
+	blocks := int((s.Length + int64(s.BlockSize) - 1) / int64(s.BlockSize))
+	rec := make([]byte, 4+s.StrongLen)
+	off := int64(sigHeader)
+	for i := 0; i < blocks; i++ {
+		if n, err := io.ReadFull(r, rec); err != nil {
+			return nil, readErr(err, off+int64(n), "truncated signature block")
+		}
+		s.weak = append(s.weak, binary.BigEndian.Uint32(rec))
+		s.strong = append(s.strong, rec[4:]...)
+		off += int64(len(rec))
+	}
+	var extra [1]byte
+	if n, _ := r.Read(extra[:]); n > 0 {
+		return nil, CorruptError{off, "data after the last block"}
+	}
+	return s, nil
+}
+
+// A CorruptError for a truncated read, other errors are returned as is
+func readErr(err error, off int64, reason string) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return CorruptError{off, reason}
+	}
+	return err
+}
+
+// Index the blocks by weak sum
+func (s *Signature) buildIndex() {
+	if s.index != nil {
+		return
+	}
+	s.index = make(map[uint32][]int32, len(s.weak))
+	for i, w := range s.weak {
+		s.index[w] = append(s.index[w], int32(i))
+	}
+}
+
+// The block matching `data` with the given weak sum, -1 if none. A block only
+// matches data of its own length
+func (s *Signature) find(weak uint32, data []byte, h hash.Hash, sum []byte) (int, []byte) {
+	candidates := s.index[weak]
+	if len(candidates) == 0 {
+		return -1, sum
+	}
+	computed := false
+	last := len(s.weak) - 1
+	for _, c := range candidates {
+		l := s.BlockSize
+		if int(c) == last {
+			l = s.lastLen()
+		}
+		if l != len(data) {
+			continue
+		}
+		if !computed {
+			h.Reset()
+			h.Write(data)
+			sum = h.Sum(sum[:0])
+			computed = true
+		}
+		if _, strong := s.block(int(c)); bytes.Equal(strong, sum[:s.StrongLen]) {
+			return int(c), sum
+		}
+	}
+	return -1, sum
+}
@@ -0,0 +1,45 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+package hamming
+
+// Bits are numbered from the most significant bit of p[0]
+
+// The bit at index i of p (0 or 1)
+func Bit(p []byte, i int) byte {
+	return p[i>>3] >> (7 - i&7) & 1
+}
+
+// Set the bit at index i of p to the low bit of v
+func SetBit(p []byte, i int, v byte) {
+	mask := byte(0x80) >> (i & 7)
+	if v&1 != 0 {
+		p[i>>3] |= mask
+	} else {
+		p[i>>3] &^= mask
+	}
+}
+
+// Flip the bit at index i of p
+func FlipBit(p []byte, i int) {
+	p[i>>3] ^= 0x80 >> (i & 7)
+}
+
+// Pack bits (one per byte, the low bit is used) into bytes, a partial final
+// byte is zero padded
+func Pack(bits []byte) []byte {
+	ret := make([]byte, (len(bits)+7)/8)
+	for i, b := range bits {
+		SetBit(ret, i, b)
+	}
+	return ret
+}
+
+// Unpack the first n bits of p, one per byte
+func Unpack(p []byte, n int) []byte {
+	ret := make([]byte, n)
+	for i := range ret {
+		ret[i] = Bit(p, i)
+	}
+	return ret
+}
@@ -0,0 +1,226 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+// Hamming single error correcting codes, and extended Hamming (SECDED) codes that
+// also detect double errors
+package hamming
+
+//https://en.wikipedia.org/wiki/Hamming_code
+//https://en.wikipedia.org/wiki/Hamming_code#Hamming_codes_with_additional_parity_(SECDED)
+
+import (
+	"errors"
+	"fmt"
+)
+
+// The parity/data bit counts don't describe a Hamming code
+var ErrParams = errors.New("invalid Hamming code parameters")
+
+// The code is too short for the stated data size
+var ErrLength = errors.New("encoded data too short")
+
+// A block has more errors than the code can correct (use errors.Is to test for
+// this)
+var ErrUncorrectable = errors.New("uncorrectable error")
+
+// A block (from 0) with a detected but uncorrectable error
+type UncorrectableError struct {
+	Block int
+}
+
+func (e UncorrectableError) Error() string {
+	return fmt.Sprintf("Uncorrectable error in block %d", e.Block)
+}
+
+func (e UncorrectableError) Unwrap() error { return ErrUncorrectable }
+
+// A corrected bit, by block (from 0) and bit position within the block's codeword
+type Correction struct {
+	Block int
+	Bit   int
+}
+
+// A Hamming code with r parity bits and k data bits. Codeword bits are in
+// position order: parity bits sit at power of two positions (1,2,4..) with data
+// in between, an extended code adds an overall parity bit before position 1
+type Code struct {
+	r, k     int
+	extended bool
+}
+
+// Hamming(7,4)
+var Hamming74 = MustNew(3, 4, false)
+
+// Hamming(15,11)
+var Hamming1511 = MustNew(4, 11, false)
+
+// Hamming(31,26)
+var Hamming3126 = MustNew(5, 26, false)
+
+// Extended Hamming(72,64), single error correct double error detect as used by
+// ECC memory (a shortened (127,120) code with overall parity)
+var SECDED7264 = MustNew(7, 64, true)
+
+// A Hamming code with r parity bits (2-8) and k data bits. If k is less than
+// 2^r-r-1 the code is shortened, but k must be at least 2^(r-1)-r so the
+// codeword reaches the last parity position. Extended codes add an overall
+// parity bit
+func New(r, k int, extended bool) (*Code, error) {
+	if r < 2 || r > 8 || k < 1 || k > 1<<r-r-1 || k < 1<<(r-1)-r {
+		return nil, ErrParams
+	}
+	return &Code{r, k, extended}, nil
+}
+
+// Like New but panics on invalid parameters (for package level vars)
+func MustNew(r, k int, extended bool) *Code {
+	c, err := New(r, k, extended)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// Bits in each codeword
+func (c *Code) N() int {
+	if c.extended {
+		return c.r + c.k + 1
+	}
+	return c.r + c.k
+}
+
+// Data bits in each codeword
+func (c *Code) K() int { return c.k }
+
+// Whether the code detects double errors
+func (c *Code) Extended() bool { return c.extended }
+
+func (c *Code) String() string {
+	if c.extended {
+		return fmt.Sprintf("SECDED(%d,%d)", c.N(), c.k)
+	}
+	return fmt.Sprintf("Hamming(%d,%d)", c.N(), c.k)
+}
+
+// Offset from the start of a codeword of position 1
+func (c *Code) base() int {
+	if c.extended {
+		return 1
+	}
+	return 0
+}
+
+// Number of codewords for size bytes of data
+func (c *Code) blocks(size int) int {
+	return (size*8 + c.k - 1) / c.k
+}
+
+// Length in bytes of size bytes of data once encoded
+func (c *Code) EncodedLen(size int) int {
+	return (c.blocks(size)*c.N() + 7) / 8
+}
+
+// Encode data, the final block is zero padded
+func (c *Code) Encode(data []byte) []byte {
+	n := c.N()
+	dataBits := len(data) * 8
+	ret := make([]byte, c.EncodedLen(len(data)))
+	last := c.r + c.k
+	for b, in := 0, 0; b < c.blocks(len(data)); b++ {
+		start := b*n + c.base()
+		var syn, all int
+		for pos := 1; pos <= last; pos++ {
+			if pos&(pos-1) == 0 {
+				continue
+			}
+			if in < dataBits && Bit(data, in) != 0 {
+				SetBit(ret, start+pos-1, 1)
+				syn ^= pos
+				all ^= 1
+			}
+			in++
+		}
+		for i := 0; i < c.r; i++ {
+			if syn>>i&1 != 0 {
+				SetBit(ret, start+1<<i-1, 1)
+				all ^= 1
+			}
+		}
+		if c.extended {
+			SetBit(ret, b*n, byte(all))
+		}
+	}
+	return ret
+}
+
+// Correct a codeword in place, returns the corrected bit (or -1) and whether
+// the error is uncorrectable
+func (c *Code) correct(code []byte, start int) (int, bool) {
+	last := c.r + c.k
+	syn, all := 0, 0
+	base := start + c.base()
+	for pos := 1; pos <= last; pos++ {
+		if Bit(code, base+pos-1) != 0 {
+			syn ^= pos
+			all ^= 1
+		}
+	}
+	if !c.extended {
+		if syn == 0 {
+			return -1, false
+		}
+		if syn > last {
+			//Shortened code, the position doesn't exist
+			return -1, true
+		}
+		FlipBit(code, base+syn-1)
+		return syn - 1, false
+	}
+	all ^= int(Bit(code, start))
+	switch {
+	case syn == 0 && all == 0:
+		return -1, false
+	case all == 0 || syn > last:
+		//Even parity with a syndrome is a double error
+		return -1, true
+	case syn == 0:
+		FlipBit(code, start)
+		return 0, false
+	}
+	FlipBit(code, base+syn-1)
+	return syn, false
+}
+
+// Decode the first size bytes of data, correcting code in place. Returns the
+// corrections made, ErrLength if code is too short, or an UncorrectableError
+// for the first block that couldn't be corrected (the data is still returned).
+// A non-extended code miscorrects double errors
+func (c *Code) Decode(code []byte, size int) ([]byte, []Correction, error) {
+	if len(code) < c.EncodedLen(size) {
+		return nil, nil, ErrLength
+	}
+	n := c.N()
+	dataBits := size * 8
+	ret := make([]byte, size)
+	var fixes []Correction
+	var err error
+	last := c.r + c.k
+	for b, out := 0, 0; b < c.blocks(size); b++ {
+		bit, bad := c.correct(code, b*n)
+		if bad && err == nil {
+			err = UncorrectableError{b}
+		}
+		if bit >= 0 {
+			fixes = append(fixes, Correction{b, bit})
+		}
+		base := b*n + c.base()
+		for pos := 1; pos <= last && out < dataBits; pos++ {
+			if pos&(pos-1) == 0 {
+				continue
+			}
+			SetBit(ret, out, Bit(code, base+pos-1))
+			out++
+		}
+	}
+	return ret, fixes, err
+}
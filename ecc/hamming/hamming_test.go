@@ -0,0 +1,132 @@
+package hamming
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+var codes = []*Code{Hamming74, Hamming1511, Hamming3126, SECDED7264, MustNew(3, 4, true), MustNew(4, 8, false)}
+
+func TestParams(t *testing.T) {
+	tests := []struct {
+		c      *Code
+		expect string
+		bytes8 int
+	}{
+		{Hamming74, "Hamming(7,4)", 14},
+		{Hamming1511, "Hamming(15,11)", 12},
+		{Hamming3126, "Hamming(31,26)", 12},
+		{SECDED7264, "SECDED(72,64)", 9},
+	}
+	for _, rec := range tests {
+		if found := rec.c.String(); found != rec.expect {
+			t.Errorf("String() expecting %s, got %s", rec.expect, found)
+		}
+		if found := rec.c.EncodedLen(8); found != rec.bytes8 {
+			t.Errorf("%s EncodedLen(8) expecting %d, got %d", rec.expect, rec.bytes8, found)
+		}
+	}
+	//{5,8}, {4,1} and {8,119} never reach the last parity position (16, 8 and 128)
+	for _, bad := range [][2]int{{1, 1}, {3, 5}, {9, 100}, {4, 0}, {5, 8}, {4, 1}, {8, 119}} {
+		for _, ext := range []bool{false, true} {
+			if _, err := New(bad[0], bad[1], ext); err != ErrParams {
+				t.Errorf("New(%d,%d,%v) expecting %v, got %v", bad[0], bad[1], ext, ErrParams, err)
+			}
+		}
+	}
+}
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		c      *Code
+		data   []byte
+		expect string
+	}{
+		//Data 1011 is codeword 0110011 (and 0000 is 0000000)
+		{Hamming74, []byte{0xb0}, "6600"},
+		{Hamming74, []byte{0xff}, "fffc"},
+		//Extended adds even overall parity: 0 0110011 / 0 0000000
+		{MustNew(3, 4, true), []byte{0xb0}, "3300"},
+		{SECDED7264, make([]byte, 8), "000000000000000000"},
+		//No published vector, regression only
+		{Hamming1511, []byte{0x80, 0x00}, "e0000000"},
+		{Hamming1511, []byte("gn"), "dd763c00"},
+		{SECDED7264, []byte{0x80, 0, 0, 0, 0, 0, 0, 0}, "f00000000000000000"},
+		{SECDED7264, []byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef}, "08890d154f13579b6f"},
+	}
+	for _, rec := range tests {
+		found := rec.c.Encode(rec.data)
+		if fmt.Sprintf("%x", found) != rec.expect {
+			t.Errorf("%v Encode(%x) expecting %s, got %x", rec.c, rec.data, rec.expect, found)
+		}
+	}
+}
+
+func TestCorrectSingle(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, c := range codes {
+		data := make([]byte, 13)
+		rng.Read(data)
+		good := c.Encode(data)
+		for bit := 0; bit < c.blocks(len(data))*c.N(); bit++ {
+			code := append([]byte{}, good...)
+			FlipBit(code, bit)
+			found, fixes, err := c.Decode(code, len(data))
+			expectFix := Correction{bit / c.N(), bit % c.N()}
+			if err != nil || !bytes.Equal(found, data) || len(fixes) != 1 || fixes[0] != expectFix {
+				t.Errorf("%v flip %d expecting %v, got %v %v", c, bit, expectFix, fixes, err)
+			}
+			if !bytes.Equal(code, good) {
+				t.Errorf("%v flip %d expecting code corrected in place", c, bit)
+			}
+		}
+	}
+}
+
+func TestDetectDouble(t *testing.T) {
+	for _, c := range []*Code{SECDED7264, MustNew(3, 4, true)} {
+		good := c.Encode([]byte{0xde, 0xad, 0xbe, 0xef, 0x01, 0x23, 0x45, 0x67})
+		n := c.N()
+		for a := 0; a < n; a++ {
+			for b := a + 1; b < n; b++ {
+				code := append([]byte{}, good...)
+				FlipBit(code, a)
+				FlipBit(code, b)
+				_, _, err := c.Decode(code, 8)
+				var ue UncorrectableError
+				if !errors.As(err, &ue) || ue.Block != 0 {
+					t.Errorf("%v flips %d,%d expecting block 0 uncorrectable, got %v", c, a, b, err)
+				}
+			}
+		}
+	}
+}
+
+func TestDecodeLength(t *testing.T) {
+	if _, _, err := SECDED7264.Decode(make([]byte, 8), 8); err != ErrLength {
+		t.Errorf("Decode expecting %v, got %v", ErrLength, err)
+	}
+	found, fixes, err := Hamming74.Decode(nil, 0)
+	if len(found) != 0 || len(fixes) != 0 || err != nil {
+		t.Errorf("Decode empty expecting nothing, got %v %v %v", found, fixes, err)
+	}
+}
+
+func TestBits(t *testing.T) {
+	bits := []byte{1, 0, 1, 1, 0, 0, 1, 1, 1}
+	p := Pack(bits)
+	if fmt.Sprintf("%x", p) != "b380" {
+		t.Errorf("Pack expecting b380, got %x", p)
+	}
+	if found := Unpack(p, len(bits)); !bytes.Equal(found, bits) {
+		t.Errorf("Unpack expecting %v, got %v", bits, found)
+	}
+	SetBit(p, 0, 0)
+	FlipBit(p, 15)
+	if fmt.Sprintf("%x", p) != "3381" || Bit(p, 2) != 1 {
+		t.Errorf("SetBit/FlipBit expecting 3381, got %x", p)
+	}
+}
@@ -0,0 +1,244 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+// Reed-Solomon codes over GF(2^8): systematic RS(n,k) codewords that correct
+// errors and erasures, and k+m shard erasure coding for storage
+package reedsolomon
+
+//https://en.wikiversity.org/wiki/Reed%E2%80%93Solomon_codes_for_coders
+//https://en.wikipedia.org/wiki/Berlekamp%E2%80%93Massey_algorithm
+//https://en.wikipedia.org/wiki/Forney_algorithm
+
+import (
+	"errors"
+
+	"github.com/gnabgib/gnablib-go/math/gf"
+)
+
+// The code parameters are invalid
+var ErrParams = errors.New("invalid Reed-Solomon parameters")
+
+// The message or codeword is the wrong length
+var ErrLength = errors.New("wrong Reed-Solomon block length")
+
+// An erasure position is outside the codeword, or repeated
+var ErrErasure = errors.New("invalid erasure position")
+
+// There are more errors than the code can correct
+var ErrUncorrectable = errors.New("too many errors to correct")
+
+// The field and generator of a code
+type Params struct {
+	Poly  int  //Field polynomial, degree 8 (0x11d is x^8+x^4+x^3+x^2+1)
+	Alpha byte //Primitive element of the field, generator roots are powers of it
+	FCR   int  //First consecutive root, the generator's roots are α^FCR..α^(FCR+n-k-1)
+}
+
+// The parameters used by QR codes, DVB, and many others
+var Default = Params{Poly: 0x11d, Alpha: 2, FCR: 0}
+
+// A systematic RS(n,k) code: k message bytes followed by n-k parity bytes, it
+// corrects e errors and f erasures when 2e+f <= n-k
+type Code struct {
+	n, k int
+	fcr  int
+	f    *gf.Field
+	gen  []byte //Generator polynomial, monic, highest power first
+}
+
+// A code with n (up to 255) byte codewords holding k message bytes. Codes with
+// n below 255 are shortened
+func New(n, k int, p Params) (*Code, error) {
+	if n > 255 || k < 1 || k >= n {
+		return nil, ErrParams
+	}
+	f, err := gf.NewFieldGenerator(p.Poly, p.Alpha)
+	if err != nil {
+		return nil, ErrParams
+	}
+	c := &Code{n: n, k: k, fcr: p.FCR, f: f, gen: []byte{1}}
+	for j := 0; j < n-k; j++ {
+		//gen*=(x-α^(fcr+j))
+		r := f.Exp(p.FCR + j)
+		next := make([]byte, len(c.gen)+1)
+		for i, g := range c.gen {
+			next[i] ^= g
+			next[i+1] ^= f.Mul(g, r)
+		}
+		c.gen = next
+	}
+	return c, nil
+}
+
+// Bytes in a codeword
+func (c *Code) N() int { return c.n }
+
+// Message bytes in a codeword
+func (c *Code) K() int { return c.k }
+
+// The n-k parity bytes for a k byte message
+func (c *Code) Parity(msg []byte) ([]byte, error) {
+	if len(msg) != c.k {
+		return nil, ErrLength
+	}
+	//Remainder of msg*x^(n-k) / gen, as an LFSR
+	par := make([]byte, c.n-c.k)
+	for _, b := range msg {
+		fb := b ^ par[0]
+		copy(par, par[1:])
+		par[len(par)-1] = 0
+		if fb != 0 {
+			for i := range par {
+				par[i] ^= c.f.Mul(fb, c.gen[i+1])
+			}
+		}
+	}
+	return par, nil
+}
+
+// The codeword for a k byte message: the message followed by parity
+func (c *Code) Encode(msg []byte) ([]byte, error) {
+	par, err := c.Parity(msg)
+	if err != nil {
+		return nil, err
+	}
+	return append(append(make([]byte, 0, c.n), msg...), par...), nil
+}
+
+// Syndromes of a codeword (position p is the coefficient of x^(n-1-p)), and
+// whether they're all zero
+func (c *Code) syndromes(code []byte) ([]byte, bool) {
+	s := make([]byte, c.n-c.k)
+	ok := true
+	for j := range s {
+		x := c.f.Exp(c.fcr + j)
+		var y byte
+		for _, b := range code {
+			y = c.f.Mul(y, x) ^ b
+		}
+		s[j] = y
+		ok = ok && y == 0
+	}
+	return s, ok
+}
+
+// Whether code is a valid codeword
+func (c *Code) Verify(code []byte) bool {
+	if len(code) != c.n {
+		return false
+	}
+	_, ok := c.syndromes(code)
+	return ok
+}
+
+// Correct a codeword in place, erasures are the positions of bytes known to be
+// bad (their value is ignored). Returns the positions that were changed,
+// ErrLength, ErrErasure or ErrUncorrectable (code is unchanged on error)
+func (c *Code) Decode(code []byte, erasures []int) ([]int, error) {
+	if len(code) != c.n {
+		return nil, ErrLength
+	}
+	nsym := c.n - c.k
+	seen := make(map[int]bool, len(erasures))
+	for _, p := range erasures {
+		if p < 0 || p >= c.n || seen[p] {
+			return nil, ErrErasure
+		}
+		seen[p] = true
+	}
+	if len(erasures) > nsym {
+		return nil, ErrUncorrectable
+	}
+	s, ok := c.syndromes(code)
+	if ok {
+		return nil, nil
+	}
+	f := c.f
+	//Erasure locator Γ(x)=∏(1+X x) with X=α^(n-1-p)
+	gamma := []byte{1}
+	for _, p := range erasures {
+		gamma = c.f.PolyMul(gamma, []byte{1, f.Exp(c.n - 1 - p)})
+	}
+	//Berlekamp-Massey seeded with the erasure locator gives the errata locator Λ
+	nu := len(erasures)
+	lambda := append([]byte{}, gamma...)
+	b := append([]byte{}, gamma...)
+	l := nu
+	for r := nu; r < nsym; r++ {
+		var delta byte
+		for i := 0; i < len(lambda) && i <= r; i++ {
+			delta ^= f.Mul(lambda[i], s[r-i])
+		}
+		b = append([]byte{0}, b...)
+		if delta == 0 {
+			continue
+		}
+		t := make([]byte, max(len(lambda), len(b)))
+		copy(t, lambda)
+		for i, v := range b {
+			t[i] ^= f.Mul(delta, v)
+		}
+		if 2*l <= r+nu {
+			b = make([]byte, len(lambda))
+			for i, v := range lambda {
+				b[i] = f.Div(v, delta)
+			}
+			l = r + 1 + nu - l
+		}
+		lambda = t
+	}
+	for len(lambda) > 1 && lambda[len(lambda)-1] == 0 {
+		lambda = lambda[:len(lambda)-1]
+	}
+	deg := len(lambda) - 1
+	if deg != l || deg > nsym {
+		return nil, ErrUncorrectable
+	}
+	//Chien search: a root at α^-e is an error at power e
+	var pos []int
+	for e := 0; e < c.n; e++ {
+		if f.PolyEval(lambda, f.Exp(-e)) == 0 {
+			pos = append(pos, c.n-1-e)
+		}
+	}
+	if len(pos) != deg {
+		return nil, ErrUncorrectable
+	}
+	//Forney: Y=X^(1-fcr)Ω(X^-1)/Λ'(X^-1), Ω=SΛ mod x^nsym
+	omega := c.f.PolyMul(s, lambda)[:nsym]
+	deriv := make([]byte, len(lambda)-1)
+	for i := 1; i < len(lambda); i += 2 {
+		deriv[i-1] = lambda[i]
+	}
+	fixed := append([]byte{}, code...)
+	var changed []int
+	for _, p := range pos {
+		e := c.n - 1 - p
+		xInv := f.Exp(-e)
+		den := f.PolyEval(deriv, xInv)
+		if den == 0 {
+			return nil, ErrUncorrectable
+		}
+		y := f.Mul(f.Exp(e*(1-c.fcr)), f.Div(f.PolyEval(omega, xInv), den))
+		if y != 0 {
+			fixed[p] ^= y
+			changed = append(changed, p)
+		}
+	}
+	if _, ok := c.syndromes(fixed); !ok {
+		return nil, ErrUncorrectable
+	}
+	copy(code, fixed)
+	//Chien search runs high powers first, report in position order
+	for i, j := 0, len(changed)-1; i < j; i, j = i+1, j-1 {
+		changed[i], changed[j] = changed[j], changed[i]
+	}
+	return changed, nil
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,138 @@
+package reedsolomon
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func TestParity(t *testing.T) {
+	tests := []struct {
+		n, k   int
+		p      Params
+		msg    []byte
+		expect string
+	}{
+		//QR 1-M "HELLO WORLD" https://www.thonky.com/qr-code-tutorial/error-correction-coding
+		{26, 16, Default, []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}, "c4232777ebd7e7e25d17"},
+		//https://en.wikiversity.org/wiki/Reed%E2%80%93Solomon_codes_for_coders
+		{21, 11, Default, []byte("hello world"), "ed2554c4fdfd89f3a8aa"},
+		//No published vector, regression only
+		{19, 11, Params{0x187, 11, 112}, []byte("hello world"), "174c2be56b9757b7"},
+	}
+	for _, rec := range tests {
+		c, err := New(rec.n, rec.k, rec.p)
+		if err != nil {
+			t.Errorf("New(%d,%d) unexpected error %v", rec.n, rec.k, err)
+			continue
+		}
+		found, _ := c.Parity(rec.msg)
+		if fmt.Sprintf("%x", found) != rec.expect {
+			t.Errorf("Parity(%q) expecting %s, got %x", rec.msg, rec.expect, found)
+		}
+		code, _ := c.Encode(rec.msg)
+		if !c.Verify(code) {
+			t.Errorf("Verify(Encode(%q)) expecting true", rec.msg)
+		}
+	}
+	c, _ := New(20, 16, Default)
+	if fmt.Sprint(c.gen) != "[1 15 54 120 64]" {
+		t.Errorf("Generator expecting [1 15 54 120 64], got %v", c.gen)
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		n, k int
+		p    Params
+		ok   bool
+	}{
+		{255, 223, Default, true},
+		{256, 223, Default, false},
+		{10, 10, Default, false},
+		{10, 0, Default, false},
+		//AES polynomial, 2 isn't primitive but 3 is
+		{10, 5, Params{0x11b, 2, 0}, false},
+		{10, 5, Params{0x11b, 3, 0}, true},
+		{10, 5, Params{0x1d, 2, 0}, false},
+	}
+	for _, rec := range tests {
+		_, err := New(rec.n, rec.k, rec.p)
+		if (err == nil) != rec.ok {
+			t.Errorf("New(%d,%d,%v) expecting ok=%v, got %v", rec.n, rec.k, rec.p, rec.ok, err)
+		}
+		if err != nil && err != ErrParams {
+			t.Errorf("New(%d,%d,%v) expecting %v, got %v", rec.n, rec.k, rec.p, ErrParams, err)
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	params := []struct {
+		n, k int
+		p    Params
+	}{
+		{255, 223, Default},
+		{26, 16, Default},
+		{19, 11, Params{0x187, 11, 112}},
+		{40, 30, Params{0x11b, 3, 1}},
+	}
+	for _, rec := range params {
+		c, _ := New(rec.n, rec.k, rec.p)
+		nsym := rec.n - rec.k
+		for trial := 0; trial < 50; trial++ {
+			msg := make([]byte, rec.k)
+			rng.Read(msg)
+			good, _ := c.Encode(msg)
+			//Random mix with 2*errors+erasures<=nsym
+			erasures := rng.Intn(nsym + 1)
+			errs := rng.Intn((nsym-erasures)/2 + 1)
+			perm := rng.Perm(rec.n)
+			code := append([]byte{}, good...)
+			for _, p := range perm[:erasures+errs] {
+				code[p] ^= byte(rng.Intn(255) + 1)
+			}
+			changed, err := c.Decode(code, perm[:erasures])
+			if err != nil || !bytes.Equal(code, good) {
+				t.Errorf("RS(%d,%d) %d errors %d erasures expecting correction, got %v", rec.n, rec.k, errs, erasures, err)
+				continue
+			}
+			if len(changed) != erasures+errs {
+				t.Errorf("RS(%d,%d) expecting %d changes, got %v", rec.n, rec.k, erasures+errs, changed)
+			}
+		}
+	}
+}
+
+func TestDecodeError(t *testing.T) {
+	c, _ := New(21, 11, Default)
+	good, _ := c.Encode([]byte("hello world"))
+	if _, err := c.Decode(good[1:], nil); err != ErrLength {
+		t.Errorf("Decode expecting %v, got %v", ErrLength, err)
+	}
+	for _, e := range [][]int{{21}, {-1}, {3, 3}} {
+		if _, err := c.Decode(good, e); err != ErrErasure {
+			t.Errorf("Decode(%v) expecting %v, got %v", e, ErrErasure, err)
+		}
+	}
+	code := append([]byte{}, good...)
+	for i := 0; i < 6; i++ {
+		code[i] ^= 0xff
+	}
+	bad := append([]byte{}, code...)
+	if _, err := c.Decode(code, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10}); err != ErrUncorrectable {
+		t.Errorf("Decode 11 erasures expecting %v, got %v", ErrUncorrectable, err)
+	}
+	//6 errors exceeds the capacity of 5
+	if _, err := c.Decode(code, nil); err != ErrUncorrectable {
+		t.Errorf("Decode 6 errors expecting %v, got %v", ErrUncorrectable, err)
+	}
+	if !bytes.Equal(code, bad) {
+		t.Errorf("Decode failure expecting code unchanged, got %x", code)
+	}
+	if changed, err := c.Decode(good, nil); changed != nil || err != nil {
+		t.Errorf("Decode good expecting no changes, got %v %v", changed, err)
+	}
+}
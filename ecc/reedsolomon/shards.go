@@ -0,0 +1,244 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+package reedsolomon
+
+//Plank, "A Tutorial on Reed-Solomon Coding for Fault-Tolerance in RAID-like
+//Systems" with its 2003 correction: a systematic matrix from a Vandermonde matrix
+
+import (
+	"errors"
+
+	"github.com/gnabgib/gnablib-go/math/gf"
+)
+
+// The shard list is the wrong length, or shards differ in size
+var ErrShards = errors.New("wrong shard count or size")
+
+// Fewer than the data shard count are present
+var ErrTooFewShards = errors.New("too few shards to reconstruct")
+
+// Erasure coding over equal sized shards: any `data` of the data+parity shards
+// rebuild the rest. Uses the Default field
+type ShardEncoder struct {
+	data, parity int
+	f            *gf.Field
+	matrix       [][]byte //(data+parity)×data, the top is identity
+}
+
+// An encoder for `data` data shards and `parity` parity shards (up to 256 in
+// total)
+func NewShardEncoder(data, parity int) (*ShardEncoder, error) {
+	if data < 1 || parity < 1 || data+parity > 256 {
+		return nil, ErrParams
+	}
+	f := gf.GF256
+	n := data + parity
+	//Vandermonde rows x^0..x^(data-1) for distinct x, any `data` rows are
+	//independent. Multiplying by the inverse of the top square keeps that, and
+	//makes the data shards pass through unchanged
+	v := make([][]byte, n)
+	for i := range v {
+		v[i] = make([]byte, data)
+		for j := range v[i] {
+			switch {
+			case j == 0:
+				v[i][j] = 1
+			case i != 0:
+				v[i][j] = f.Pow(byte(i), j)
+			}
+		}
+	}
+	top, _ := f.Invert(v[:data])
+	e := &ShardEncoder{data, parity, f, make([][]byte, n)}
+	for i := range v {
+		e.matrix[i] = make([]byte, data)
+		for j := 0; j < data; j++ {
+			var s byte
+			for t := 0; t < data; t++ {
+				s ^= f.Mul(v[i][t], top[t][j])
+			}
+			e.matrix[i][j] = s
+		}
+	}
+	return e, nil
+}
+
+// Data shard count
+func (e *ShardEncoder) DataShards() int { return e.data }
+
+// Parity shard count
+func (e *ShardEncoder) ParityShards() int { return e.parity }
+
+// out=Σ row[j]*in[j]
+func (e *ShardEncoder) combine(out []byte, row []byte, in [][]byte) {
+	for i := range out {
+		out[i] = 0
+	}
+	for j, c := range row {
+		if c == 0 {
+			continue
+		}
+		for i, b := range in[j] {
+			out[i] ^= e.f.Mul(c, b)
+		}
+	}
+}
+
+// Shard size, or -1 if the present shards differ (or none are present)
+func (e *ShardEncoder) shardSize(shards [][]byte) int {
+	size := -1
+	for _, s := range shards {
+		if s == nil {
+			continue
+		}
+		if size >= 0 && len(s) != size {
+			return -1
+		}
+		size = len(s)
+	}
+	return size
+}
+
+// Whether all the data shards are present
+func (e *ShardEncoder) hasData(shards [][]byte) bool {
+	for _, s := range shards[:e.data] {
+		if s == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Compute the parity shards from the data shards. Shards holds data+parity
+// entries, parity entries that are nil are allocated. Returns ErrShards if a data
+// shard is nil
+func (e *ShardEncoder) Encode(shards [][]byte) error {
+	if len(shards) != e.data+e.parity || !e.hasData(shards) {
+		return ErrShards
+	}
+	size := e.shardSize(shards[:e.data])
+	if size < 0 {
+		return ErrShards
+	}
+	for i := e.data; i < len(shards); i++ {
+		if shards[i] == nil {
+			shards[i] = make([]byte, size)
+		}
+		if len(shards[i]) != size {
+			return ErrShards
+		}
+		e.combine(shards[i], e.matrix[i], shards[:e.data])
+	}
+	return nil
+}
+
+// Whether the parity shards match the data shards, all shards must be present
+// (ErrShards otherwise)
+func (e *ShardEncoder) Verify(shards [][]byte) (bool, error) {
+	if len(shards) != e.data+e.parity || !e.hasData(shards) {
+		return false, ErrShards
+	}
+	size := e.shardSize(shards)
+	if size < 0 {
+		return false, ErrShards
+	}
+	buf := make([]byte, size)
+	for i := e.data; i < len(shards); i++ {
+		if shards[i] == nil {
+			return false, ErrShards
+		}
+		e.combine(buf, e.matrix[i], shards[:e.data])
+		for j := range buf {
+			if buf[j] != shards[i][j] {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
+
+// Rebuild missing (nil) shards in place, ErrTooFewShards if fewer than the data
+// shard count are present
+func (e *ShardEncoder) Reconstruct(shards [][]byte) error {
+	if len(shards) != e.data+e.parity {
+		return ErrShards
+	}
+	size := e.shardSize(shards)
+	if size < 0 {
+		for _, s := range shards {
+			if s != nil {
+				return ErrShards
+			}
+		}
+		return ErrTooFewShards
+	}
+	var rows [][]byte
+	var in [][]byte
+	dataMissing := false
+	for i, s := range shards {
+		if s == nil {
+			dataMissing = dataMissing || i < e.data
+			continue
+		}
+		if len(rows) < e.data {
+			rows = append(rows, e.matrix[i])
+			in = append(in, s)
+		}
+	}
+	if len(rows) < e.data {
+		return ErrTooFewShards
+	}
+	if dataMissing {
+		//The present rows map data to the present shards, their inverse maps back.
+		//Any `data` rows are independent, so this never fails
+		dec, _ := e.f.Invert(rows)
+		for i := 0; i < e.data; i++ {
+			if shards[i] == nil {
+				shards[i] = make([]byte, size)
+				e.combine(shards[i], dec[i], in)
+			}
+		}
+	}
+	for i := e.data; i < len(shards); i++ {
+		if shards[i] == nil {
+			shards[i] = make([]byte, size)
+			e.combine(shards[i], e.matrix[i], shards[:e.data])
+		}
+	}
+	return nil
+}
+
+// Split data into data+parity shards (the data shards are zero padded, parity
+// shards are allocated but not computed, see Encode)
+func (e *ShardEncoder) Split(data []byte) [][]byte {
+	size := (len(data) + e.data - 1) / e.data
+	if size == 0 {
+		size = 1
+	}
+	buf := make([]byte, size*(e.data+e.parity))
+	copy(buf, data)
+	shards := make([][]byte, e.data+e.parity)
+	for i := range shards {
+		shards[i] = buf[i*size : (i+1)*size : (i+1)*size]
+	}
+	return shards
+}
+
+// Join the first size bytes of the data shards
+func (e *ShardEncoder) Join(shards [][]byte, size int) ([]byte, error) {
+	if len(shards) < e.data || size < 0 {
+		return nil, ErrShards
+	}
+	ret := make([]byte, 0, size)
+	for _, s := range shards[:e.data] {
+		if s == nil {
+			return nil, ErrShards
+		}
+		if len(ret)+len(s) >= size {
+			return append(ret, s[:size-len(ret)]...), nil
+		}
+		ret = append(ret, s...)
+	}
+	return nil, ErrShards
+}
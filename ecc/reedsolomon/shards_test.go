@@ -0,0 +1,87 @@
+package reedsolomon
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+)
+
+func TestShards(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, dp := range [][2]int{{1, 1}, {4, 2}, {10, 4}, {17, 3}, {200, 56}} {
+		e, err := NewShardEncoder(dp[0], dp[1])
+		if err != nil {
+			t.Fatalf("NewShardEncoder(%d,%d) unexpected error %v", dp[0], dp[1], err)
+		}
+		data := make([]byte, 1000)
+		rng.Read(data)
+		shards := e.Split(data)
+		if err := e.Encode(shards); err != nil {
+			t.Fatalf("Encode unexpected error %v", err)
+		}
+		if ok, err := e.Verify(shards); !ok || err != nil {
+			t.Errorf("Verify expecting true, got %v %v", ok, err)
+		}
+		good := make([][]byte, len(shards))
+		for i := range shards {
+			good[i] = append([]byte{}, shards[i]...)
+		}
+		//Lose as many shards as there is parity
+		for trial := 0; trial < 10; trial++ {
+			for _, i := range rng.Perm(len(shards))[:dp[1]] {
+				shards[i] = nil
+			}
+			if err := e.Reconstruct(shards); err != nil {
+				t.Fatalf("Reconstruct unexpected error %v", err)
+			}
+			for i := range shards {
+				if !bytes.Equal(shards[i], good[i]) {
+					t.Errorf("%d+%d Reconstruct shard %d mismatch", dp[0], dp[1], i)
+				}
+			}
+		}
+		found, err := e.Join(shards, len(data))
+		if err != nil || !bytes.Equal(found, data) {
+			t.Errorf("Join expecting original data, got %v", err)
+		}
+		shards[0][0] ^= 1
+		if ok, _ := e.Verify(shards); ok {
+			t.Errorf("Verify corrupt expecting false")
+		}
+	}
+}
+
+func TestShardErrors(t *testing.T) {
+	for _, dp := range [][2]int{{0, 1}, {1, 0}, {200, 57}} {
+		if _, err := NewShardEncoder(dp[0], dp[1]); err != ErrParams {
+			t.Errorf("NewShardEncoder(%d,%d) expecting %v, got %v", dp[0], dp[1], ErrParams, err)
+		}
+	}
+	e, _ := NewShardEncoder(3, 2)
+	shards := e.Split([]byte("gnabgib"))
+	if err := e.Encode(shards[:4]); err != ErrShards {
+		t.Errorf("Encode expecting %v, got %v", ErrShards, err)
+	}
+	shards[1] = shards[1][:1]
+	if err := e.Encode(shards); err != ErrShards {
+		t.Errorf("Encode expecting %v, got %v", ErrShards, err)
+	}
+	shards = e.Split([]byte("gnabgib"))
+	shards[0] = nil
+	if err := e.Encode(shards); err != ErrShards {
+		t.Errorf("Encode nil data shard expecting %v, got %v", ErrShards, err)
+	}
+	shards = e.Split([]byte("gnabgib"))
+	e.Encode(shards)
+	shards[0] = nil
+	if _, err := e.Verify(shards); err != ErrShards {
+		t.Errorf("Verify nil data shard expecting %v, got %v", ErrShards, err)
+	}
+	if _, err := e.Join(shards[1:], -1); err != ErrShards {
+		t.Errorf("Join negative size expecting %v, got %v", ErrShards, err)
+	}
+	shards[0], shards[2], shards[4] = nil, nil, nil
+	if err := e.Reconstruct(shards); err != ErrTooFewShards {
+		t.Errorf("Reconstruct expecting %v, got %v", ErrTooFewShards, err)
+	}
+}
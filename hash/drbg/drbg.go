@@ -0,0 +1,143 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+// Deterministic random bit generators Hash_DRBG and HMAC_DRBG (NIST SP 800-90A Rev1)
+// over any hash
+package drbg
+
+import (
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+)
+
+//https://nvlpubs.nist.gov/nistpubs/SpecialPublications/NIST.SP.800-90Ar1.pdf
+
+const (
+	//Maximum number of bytes per generate request (2^19 bits)
+	MaxRequestBytes = 1 << 16
+	//Maximum number of generate requests between reseeds (2^48)
+	MaxReseedInterval = uint64(1) << 48
+)
+
+// A generate request was larger than MaxRequestBytes
+var ErrRequestTooLarge = errors.New("request exceeds maximum bytes per generate")
+
+// The reseed counter has exceeded the interval, and there's no entropy source
+// to reseed from
+var ErrReseedRequired = errors.New("reseed required")
+
+// The underlying mechanism (Hash_DRBG, HMAC_DRBG)
+type mechanism interface {
+	//Instantiate from seed material (entropy || nonce || personalization)
+	instantiate(entropy, nonce, personalization []byte)
+	reseed(entropy, additional []byte)
+	generate(out, additional []byte)
+	//Number of generate requests since the last (re)seed + 1
+	reseedCounter() uint64
+}
+
+// A deterministic random bit generator
+type DRBG struct {
+	m        mechanism
+	entropy  io.Reader //Entropy source, required to instantiate and to reseed
+	strength int       //Security strength in bytes
+	interval uint64    //Reseed interval
+}
+
+// Security strength (in bits) of a hash, from SP 800-57 (SHA-1=128, SHA-224=192, SHA-256+=256)
+func securityStrength(h func() hash.Hash) int {
+	n := h().Size()
+	switch {
+	case n >= 32:
+		return 256
+	case n >= 28:
+		return 192
+	}
+	return 128
+}
+
+func newDRBG(m mechanism, h func() hash.Hash, entropy io.Reader, nonce, personalization []byte) (*DRBG, error) {
+	d := &DRBG{m: m, entropy: entropy, strength: securityStrength(h) / 8, interval: MaxReseedInterval}
+	e, err := d.getEntropy(d.strength)
+	if err != nil {
+		return nil, err
+	}
+	//If there's no nonce, use half strength of entropy for one (SP 800-90A 8.6.7)
+	if nonce == nil {
+		nonce, err = d.getEntropy(d.strength / 2)
+		if err != nil {
+			return nil, err
+		}
+	}
+	m.instantiate(e, nonce, personalization)
+	return d, nil
+}
+
+func (d *DRBG) getEntropy(n int) ([]byte, error) {
+	if d.entropy == nil {
+		return nil, ErrReseedRequired
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(d.entropy, b); err != nil {
+		return nil, fmt.Errorf("entropy source: %w", err)
+	}
+	return b, nil
+}
+
+// Security strength of the generator in bits
+func (d *DRBG) SecurityStrength() int { return d.strength * 8 }
+
+// Set the number of generate requests allowed before a reseed is required
+// (capped at MaxReseedInterval)
+func (d *DRBG) SetReseedInterval(n uint64) {
+	if n > MaxReseedInterval {
+		n = MaxReseedInterval
+	}
+	d.interval = n
+}
+
+// Reseed the generator with fresh entropy from the source, and optional additional input
+func (d *DRBG) Reseed(additional []byte) error {
+	e, err := d.getEntropy(d.strength)
+	if err != nil {
+		return err
+	}
+	d.m.reseed(e, additional)
+	return nil
+}
+
+// Fill `out` with pseudorandom bytes, with optional additional input.  When
+// `predictionResistance` is set, or the reseed interval has been reached, the
+// generator reseeds from the entropy source first (SP 800-90A 9.3.1)
+func (d *DRBG) Generate(out, additional []byte, predictionResistance bool) error {
+	if len(out) > MaxRequestBytes {
+		return ErrRequestTooLarge
+	}
+	if predictionResistance || d.m.reseedCounter() > d.interval {
+		if err := d.Reseed(additional); err != nil {
+			return err
+		}
+		//Additional input has been consumed by the reseed
+		additional = nil
+	}
+	d.m.generate(out, additional)
+	return nil
+}
+
+// Read fills p with pseudorandom bytes (in MaxRequestBytes requests, without
+// additional input or prediction resistance), which makes a DRBG an io.Reader
+func (d *DRBG) Read(p []byte) (n int, err error) {
+	for n < len(p) {
+		end := n + MaxRequestBytes
+		if end > len(p) {
+			end = len(p)
+		}
+		if err = d.Generate(p[n:end], nil, false); err != nil {
+			return
+		}
+		n = end
+	}
+	return
+}
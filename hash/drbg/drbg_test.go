@@ -0,0 +1,280 @@
+package drbg
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"errors"
+	"hash"
+	"io"
+	"testing"
+
+	"github.com/gnabgib/gnablib-go/encoding/hex"
+	"github.com/gnabgib/gnablib-go/hash/ripemd"
+	"github.com/gnabgib/gnablib-go/hash/whirlpool"
+	"github.com/gnabgib/gnablib-go/test"
+)
+
+type ctor func(h func() hash.Hash, entropy io.Reader, nonce, personalization []byte) (*DRBG, error)
+
+// Source: NIST CAVP DRBG test vectors (drbgtestvectors.zip), COUNT=0 of each
+// section.  The no reseed sections instantiate and generate twice, the
+// PredictionResistance=False sections instantiate, reseed (with EntropyInputReseed
+// and AdditionalInputReseed) and generate twice (with each AdditionalInput). As in
+// CAVP the second output is checked
+var cavpTests = []struct {
+	new          ctor
+	h            func() hash.Hash
+	name         string
+	entropyHex   string
+	nonceHex     string
+	reseed       bool
+	entReseed    string
+	addReseed    string
+	add1         string
+	add2         string
+	returnedBits string
+}{
+	{NewHash, sha1.New, "Hash_DRBG SHA-1 no reseed",
+		"136CF1C174E5A09F66B962D994396525", "FFF1C6645F19231F", false, "", "", "", "",
+		"0E28130FA5CA11EDD3293CA26FDB8AE1810611F78715082ED3841E7486F16677B28E33FFE0B93D98BA57BA358C1343AB2A26B4EB7940F5BC639384641EE80A25" +
+			"140331076268BD1CE702AD534DDA0ED8"},
+	{NewHash, sha256.New, "Hash_DRBG SHA-256 no reseed",
+		"A65AD0F345DB4E0EFFE875C3A2E71F42C7129D620FF5C119A9EF55F05185E0FB", "8581F9317517276E06E9607DDBCBCC2E", false, "", "", "", "",
+		"D3E160C35B99F340B2628264D1751060E0045DA383FF57A57D73A673D2B8D80DAAF6A6C35A91BB4579D73FD0C8FED111B0391306828ADFED528F018121B3FEBD" +
+			"C343E797B87DBB63DB1333DED9D1ECE177CFA6B71FE8AB1DA46624ED6415E51CCDE2C7CA86E283990EEAEB91120415528B2295910281B02DD431F4C9F70427DF"},
+	{NewHMAC, sha256.New, "HMAC_DRBG SHA-256 no reseed",
+		"CA851911349384BFFE89DE1CBDC46E6831E44D34A4FB935EE285DD14B71A7488", "659BA96C601DC69FC902940805EC0CA8", false, "", "", "", "",
+		"E528E9ABF2DECE54D47C7E75E5FE302149F817EA9FB4BEE6F4199697D04D5B89D54FBB978A15B5C443C9EC21036D2460B6F73EBAD0DC2ABA6E624ABF07745BC1" +
+			"07694BB7547BB0995F70DE25D6B29E2D3011BB19D27676C07162C8B5CCDE0668961DF86803482CB37ED6D5C0BB8D50CF1F50D476AA0458BDABA806F48BE9DCB8"},
+	{NewHMAC, sha256.New, "HMAC_DRBG SHA-256 reseed",
+		"06032CD5EED33F39265F49ECB142C511DA9AFF2AF71203BFFAF34A9CA5BD9C0D", "0E66F71EDC43E42A45AD3C6FC6CDC4DF", true,
+		"01920A4E669ED3A85AE8A33B35A74AD7FB2A6BB4CF395CE00334A9C9A5A5D552", "", "", "",
+		"76FC79FE9B50BECCC991A11B5635783A83536ADD03C157FB30645E611C2898BB2B1BC215000209208CD506CB28DA2A51BDB03826AAF2BD2335D576D519160842" +
+			"E7158AD0949D1A9EC3E66EA1B1A064B005DE914EAC2E9D4F2D72A8616A80225422918250FF66A41BD2F864A6A38CC5B6499DC43F7F2BD09E1E0F8F5885935124"},
+	{NewHMAC, sha256.New, "HMAC_DRBG SHA-256 reseed additional input",
+		"05AC9FC4C62A02E3F90840DA5616218C6DE5743D66B8E0FBF833759C5928B53D", "2B89A17904922ED8F017A63044848545", true,
+		"2791126B8B52EE1FD9392A0A13E0083BED4186DC649B739607AC70EC8DCECF9B", "43BAC13BAE715092CF7EB280A2E10A962FAF7233C41412F69BC74A35A584E54C",
+		"3F2FED4B68D506ECEFA21F3F5BB907BEB0F17DBC30F6FFBBA5E5861408C53A1E", "529030DF50F410985FDE068DF82B935EC23D839CB4B269414C0EDE6CFFEA5B68",
+		"02DDFF5173DA2FCFFA10215B030D660D61179E61ECC22609B1151A75F1CBCBB4363C3A89299B4B63ACA5E581E73C860491010AA35DE3337CC6C09EBEC8C91A62" +
+			"87586F3A74D9694B462D2720EA2E11BBD02AF33ADEFB4A16E6B370FA0EFFD57D607547BDCFBB7831F54DE7073AD2A7DA987A0016A82FA958779A168674B56524"},
+}
+
+func TestCavp(t *testing.T) {
+	for _, rec := range cavpTests {
+		entropy := bytes.NewReader(hex.ToBytesFast(rec.entropyHex + rec.entReseed))
+		d, err := rec.new(rec.h, entropy, hex.ToBytesFast(rec.nonceHex), nil)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", rec.name, err)
+			continue
+		}
+		if rec.reseed {
+			if err := d.Reseed(hex.ToBytesFast(rec.addReseed)); err != nil {
+				t.Errorf("%s: unexpected reseed error %v", rec.name, err)
+				continue
+			}
+		}
+		out := make([]byte, len(rec.returnedBits)/2)
+		d.Generate(out, hex.ToBytesFast(rec.add1), false)
+		d.Generate(out, hex.ToBytesFast(rec.add2), false)
+		test.StringMatchTitle(t, rec.name, "", rec.returnedBits, hex.FromBytes(out))
+	}
+}
+
+// Following the CAVP flow with personalization, reseed and additional input (for
+// hashes CAVP doesn't cover, so regression only)
+var flowTests = []struct {
+	new        ctor
+	h          func() hash.Hash
+	name       string
+	entropy    string
+	nonce      string
+	pers       string
+	entReseed  string
+	addReseed  string
+	add1       string
+	add2       string
+	entPR1     string
+	entPR2     string
+	returned   string //Reseed then two generates
+	returnedPR string //Two prediction resistant generates
+}{
+	{NewHash, ripemd.New160, "ripemd160",
+		"2291D8CDC310411E7EC27378A661C935", "187C07E4D5636E9B", "C3C400B27244B8CD3A97F11AE6510705",
+		"06A68A02F0E161AF37F86CB9078738C3", "70F07E8D3B583BAD38C275F34AED056A",
+		"D6EA8EECA4192FA1FEB9DC4B1EBE55E5", "B8F9B680EFF76C81D4E9AB304D4896F9",
+		"E17FD8F0816496DA087A3EBECC676AAA", "2C5D8CE1B3C6ACBC5F1670A9821BC729",
+		"C651990B623A3E26D0637DF0EF595D7EE5282713BF324C49238E5F474E3B40E7996C1BE55BC630B5B25666ADBAB9CB818B78BC3706C3B0A51BB77124CC06EF22" +
+			"446BA5DDD31B555BBCD50B7301B2D214",
+		"174AF9E988D87CD805EB291A3CFBDC67C308F99494E397863FE9823EB504E901B414383BD48C6DB051E63382D854F00928D064658B7B13DD9BCBCDF558E5AC52" +
+			"0BAB2A29F1BB804DF2534F9E30EB078D"},
+	{NewHash, whirlpool.New, "whirlpool",
+		"85D7645E7DBB07780B4EB4D9FB9D979464A52B2B803AFB03C5338AEBDC8C3B67", "8358F3D8935A75E844A88C9BF5BA0162", "C8DBD2F4E2F0BD83CF2184C78F346DF30E7BDE5D918D33F081697CD05B6A5800",
+		"898A9FC99C54759907CD3AA22D8C952EDC17CC8DCCD9D1EE4108D7F1AC1215DE", "047303C1C1473F441CCC9F2F584A112A284187F32BA845A5B64B74B3527F791D",
+		"064F62576BCB30421B40E6BA82FA35F79B6ED1F9053904652509B8F52972B481", "AD6D8BD538FAF9A1CCB184733986A60765AC93CD52A8A16D0FBC4C20F736E00C",
+		"4E12DB134FEAF04CBE286A904021028FE0D90997D137F6E691752BD3DEDEF9C7", "B49F8209603358193492ACE56E97317E1AF0AA634B817F04539CDF66E6480428",
+		"87D51C8FFEDB66B6DE2F8717AFDAC5C97C8EC75BB3AB4A06ACCED0694063FEFF5EB4AF3CCA49720F0CA31E187966D45CC879DAFEAB507DE696C68294A96601B9" +
+			"08FF769AA4374F45041FB7BD40C9E8DD020C10A21A58B7D2C0A598A6A213C948E3994909932324FAD9F78C5F9680E394D66D6B8C625C5EDA086721C271AB2EC9" +
+			"C50D8B43F03390812E38496D9CB3FA54485683374D336A504BA5F5DAEA807C01052EACE5A6BA2BC322670162DA1C6D7B4DBEFF23F6DAAF00133322972860D06B" +
+			"1F5423424F7B20077E6727C15E8CBB2A87B54166D38347088684E39FAF8B81CAFFAD14319CB7302B9DA835B4ABE8FE2DC6C57599D1F5C3CFE0CF01B9D400557D",
+		"C1D436EC3C3A28EC1CFD74865DBEB75F7CC9B574C1388539A94A758FF7F6E86157F405E2FF5837CBF3C5C87D6C2DF1AD6D4C073586EC61352CAC55D7352E4C71" +
+			"62A15DDACA1E9244CEF9EBB476364CDF952A0DE5D4FE05141633D16774CA1E677D35BD847A61EF411BFEFD0C929954F293C507923E7A3D7550C8C694EFB9CB65" +
+			"16D43D6885A9433FCA0051DBA4E05C3885252BA337377BA8D521265447E5E57E9C5242F1B70365150F045ED66307EA723C85E6E57CDA3B70E41F9E464B449825" +
+			"68BDDBCA0947D5A4B0DDFEF9B14C429FDBF1DA136931013D0BF9F63EE8C3349531748C1A05BD4DD29F97F052EB08891829BDB6E81958F778F23D78B85567AFCA"},
+	{NewHMAC, ripemd.New160, "ripemd160",
+		"33DB53CFFC90C822566D3644AC18D661", "EE8C58EAE1D6AF88", "7CC4FC883C10B90A15222B2AE9893644",
+		"C2559981D7415E56571D4A3CDEF19AC7", "F4B7E37D22948DC51A520A681261DDFD",
+		"C925D420571D9D96C8ED6013928C3990", "14F3445DE44B9088EC1D75E5461BC90B",
+		"D34B039DAB0317691DD3E2CA0A303DC9", "FC966B291D732AAE3D28BED81A6FE9F6",
+		"B2B3A24CD1C00E1695225204A921B032F1377652662A31C66F1B4A126DC8F5FAC1B2EDE34952AFB588DAEC15EE2DB288C5DBDBDACAED3170531AF91967B81EBB" +
+			"8F9A430133F118718AC4C10697114CFE",
+		"D11A3B0D959995FF6CAA11F9F79A2C8CF45871D7C1D26987689521F7EA652FAAD2F8CA9C5DC078DE38F84005884707E5ADB2B56989A52B26E89B0B47CDE52040" +
+			"1BBE40F78F926722E871FFE8BEB10659"},
+	{NewHMAC, whirlpool.New, "whirlpool",
+		"60CEF88AE8D14B8C40B67A501935A6510A0602C9FBEC4BB99851736450661010", "E951F899F8741C4037C89EC7FAE48ADE", "B078A95B422E8A354E323F5C14D14716FBC07217A693A456F03A63F74E0A532F",
+		"51CAD894E4EB4D3E55198B9C94CE98173E3805CE3E6612448DDE12BA1305A202", "4AC0CA5B7E78DCDB271980C7CB531382F3AA2C2DC626FC24D2DD514E1BB583D5",
+		"EB9A4B20E434248BE9B808C750D2E79FCDACE88DD7F1BFFCB0342D4C6E89280C", "B6DCAA3F40C710AEF672CE6E8C408A70D989740265D6562B427C06CBA5EE6AF9",
+		"92040FB15A942397202342FBD4466590662C9C163B7C012D875180E4A6EB70EE", "AFA3BB393D507EAF7AF439B669568F9CE8BAEAA746F8A5380CEB12C382A5E05E",
+		"BBACAEFDD2DEC0E0A425696C7D68E813CAC446B7B321131EDAA81D074FEE33977605F4F9ECF773EA62C14FE1E49E95E9ECC73DBA1974F6653DC6038669E853F6" +
+			"ECEDE2C629C2517DF9F90CEFF497D69C16DCE3AF29ADC5F3E4974DD206ECD4D4E3F1BCDFE8897A16488B9176CB7969158BA83D2579577B0B5CCEC47BDBD8B28A" +
+			"88CD404BE16591D29A19C744D38630AE14E7454C6E2D2EB6D3DC09C47FF35AB5F139C0D5AF94680DBDA9480105872AB693C4E91401CA9ACFDB68B91D1F2F9E09" +
+			"D74B8BB3ED96F40DF2DF551B24C79173423446907EEB7BEFF50EA3DDE093112C87CA321AA7C32A093A60A5C72914EE8090C589FF64FE4F3A38915A6A3A7BFD52",
+		"AE001C4EE02D6A9B09370F9FC8DBD7CE31099EBB046E47C5BE3C249C212EA743AF532FFEFD35091E76D384D4752EE13CAFF04E1FBD8E7B843D7E5540D4B11CF0" +
+			"ED1CABEAAC2765DAA47C83843F71C0668161C3F68A2F7470A06AECF0FCB972F46E2A5F13AC5964EE95B38D876A722D9EED07B3EECD0488CD4BB821DD0A24BD02" +
+			"B2AE8DC37917777A1D26443F58FC7BB2C4AABC7A91257CDC9DB2E8E29D608E39CF7F6CF1C54B9904097D365F5FAF5A54FB5EBBF17164E0D416201EA7601E60CC" +
+			"BC37255C7A7E4F5F92A0B33C5AC46777D75F161E3B75AB7BFC0AD4711F52D3BFDAE35B14D26020E0383A0399F6BADBFE0C890D84B4448BDF0CE3067B1C398A44"},
+}
+
+func TestFlow(t *testing.T) {
+	for _, rec := range flowTests {
+		entropy := bytes.NewReader(hex.ToBytesFast(rec.entropy + rec.entReseed))
+		d, err := rec.new(rec.h, entropy, hex.ToBytesFast(rec.nonce), hex.ToBytesFast(rec.pers))
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", rec.name, err)
+			continue
+		}
+		out := make([]byte, len(rec.returned)/2)
+		d.Reseed(hex.ToBytesFast(rec.addReseed))
+		d.Generate(out, hex.ToBytesFast(rec.add1), false)
+		d.Generate(out, hex.ToBytesFast(rec.add2), false)
+		test.StringMatchTitle(t, rec.name, "", rec.returned, hex.FromBytes(out))
+	}
+}
+
+func TestFlowPredictionResistance(t *testing.T) {
+	for _, rec := range flowTests {
+		entropy := bytes.NewReader(hex.ToBytesFast(rec.entropy + rec.entPR1 + rec.entPR2))
+		d, err := rec.new(rec.h, entropy, hex.ToBytesFast(rec.nonce), hex.ToBytesFast(rec.pers))
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", rec.name, err)
+			continue
+		}
+		out := make([]byte, len(rec.returnedPR)/2)
+		d.Generate(out, hex.ToBytesFast(rec.add1), true)
+		d.Generate(out, hex.ToBytesFast(rec.add2), true)
+		test.StringMatchTitle(t, rec.name+" PR", "", rec.returnedPR, hex.FromBytes(out))
+		//All entropy should have been used
+		if entropy.Len() != 0 {
+			t.Errorf("%s: expecting all entropy to be used, %d bytes left", rec.name, entropy.Len())
+		}
+		//And there's no more, so the next PR request must fail
+		if err = d.Generate(out, nil, true); err == nil {
+			t.Errorf("%s: expecting an entropy error", rec.name)
+		}
+	}
+}
+
+func TestReseedInterval(t *testing.T) {
+	for _, c := range []ctor{NewHash, NewHMAC} {
+		//Enough entropy for instantiate (with nonce) and one reseed
+		entropy := bytes.NewReader(make([]byte, 16+8+16))
+		d, _ := c(ripemd.New160, entropy, nil, nil)
+		d.SetReseedInterval(2)
+		out := make([]byte, 10)
+		for i := 0; i < 2; i++ {
+			if err := d.Generate(out, nil, false); err != nil {
+				t.Errorf("Unexpected error %v", err)
+			}
+		}
+		if entropy.Len() != 16 {
+			t.Errorf("Expecting no reseed yet, %d entropy bytes left", entropy.Len())
+		}
+		//Third request requires a reseed
+		if err := d.Generate(out, nil, false); err != nil {
+			t.Errorf("Unexpected error %v", err)
+		}
+		if entropy.Len() != 0 {
+			t.Errorf("Expecting a reseed, %d entropy bytes left", entropy.Len())
+		}
+		//One more is allowed (the reseeded request counts), then another reseed which
+		// fails (no entropy left)
+		if err := d.Generate(out, nil, false); err != nil {
+			t.Errorf("Unexpected error %v", err)
+		}
+		if err := d.Generate(out, nil, false); !errors.Is(err, io.EOF) {
+			t.Errorf("Expecting entropy EOF, got %v", err)
+		}
+	}
+}
+
+func TestNoEntropySource(t *testing.T) {
+	if _, err := NewHMAC(ripemd.New160, nil, nil, nil); err != ErrReseedRequired {
+		t.Errorf("Expecting ErrReseedRequired, got %v", err)
+	}
+}
+
+func TestRequestTooLarge(t *testing.T) {
+	d, _ := NewHMAC(ripemd.New160, bytes.NewReader(make([]byte, 24)), nil, nil)
+	if err := d.Generate(make([]byte, MaxRequestBytes+1), nil, false); err != ErrRequestTooLarge {
+		t.Errorf("Expecting ErrRequestTooLarge, got %v", err)
+	}
+}
+
+func TestRead(t *testing.T) {
+	//Read splits into max sized requests, so the result should match
+	// generating the pieces
+	a, _ := NewHash(ripemd.New160, bytes.NewReader(make([]byte, 24)), nil, nil)
+	b, _ := NewHash(ripemd.New160, bytes.NewReader(make([]byte, 24)), nil, nil)
+	found := make([]byte, MaxRequestBytes+10)
+	n, err := a.Read(found)
+	if n != len(found) || err != nil {
+		t.Errorf("Expecting %d,nil got %d,%v", len(found), n, err)
+	}
+	expect := make([]byte, MaxRequestBytes+10)
+	b.Generate(expect[:MaxRequestBytes], nil, false)
+	b.Generate(expect[MaxRequestBytes:], nil, false)
+	if !bytes.Equal(expect, found) {
+		t.Errorf("Read doesn't match Generate")
+	}
+}
+
+func TestSecurityStrength(t *testing.T) {
+	tests := []struct {
+		h      func() hash.Hash
+		expect int
+	}{
+		{sha1.New, 128},
+		{ripemd.New160, 128},
+		{sha256.New, 256},
+		{whirlpool.New, 256},
+	}
+	for _, rec := range tests {
+		d, _ := NewHMAC(rec.h, bytes.NewReader(make([]byte, 64)), nil, nil)
+		if d.SecurityStrength() != rec.expect {
+			t.Errorf("Expecting %d, got %d", rec.expect, d.SecurityStrength())
+		}
+	}
+}
+
+func TestAddMod(t *testing.T) {
+	a := []byte{0x00, 0xff, 0xff}
+	addMod(a, []byte{1})
+	if !bytes.Equal(a, []byte{1, 0, 0}) {
+		t.Errorf("Expecting carry, got %x", a)
+	}
+	a = []byte{0xff, 0xff}
+	addMod(a, []byte{0, 1})
+	if !bytes.Equal(a, []byte{0, 0}) {
+		t.Errorf("Expecting wrap, got %x", a)
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+package drbg
+
+import (
+	"encoding/binary"
+	"hash"
+	"io"
+)
+
+// Hash_DRBG (SP 800-90A 10.1.1)
+type hashDrbg struct {
+	h       hash.Hash
+	seedLen int    //Bytes, 55 for hashes up to 256 bits, 111 for larger
+	v       []byte //Value
+	c       []byte //Constant
+	counter uint64 //Reseed counter
+}
+
+// A new Hash_DRBG using hash `h`, reading entropy from `entropy` when instantiating and
+// reseeding.  If nonce is nil one is drawn from the entropy source
+func NewHash(h func() hash.Hash, entropy io.Reader, nonce, personalization []byte) (*DRBG, error) {
+	m := &hashDrbg{h: h()}
+	//SP 800-90A 10.1 Table 2
+	m.seedLen = 440 / 8
+	if m.h.Size() > 32 {
+		m.seedLen = 888 / 8
+	}
+	return newDRBG(m, h, entropy, nonce, personalization)
+}
+
+// Hash derivation function (SP 800-90A 10.3.1), produces seedLen bytes from the inputs
+func (m *hashDrbg) df(inputs ...[]byte) []byte {
+	ret := make([]byte, 0, m.seedLen+m.h.Size())
+	var pre [5]byte
+	binary.BigEndian.PutUint32(pre[1:], uint32(m.seedLen*8))
+	for ctr := byte(1); len(ret) < m.seedLen; ctr++ {
+		pre[0] = ctr
+		m.h.Reset()
+		m.h.Write(pre[:])
+		for _, in := range inputs {
+			m.h.Write(in)
+		}
+		ret = m.h.Sum(ret)
+	}
+	return ret[:m.seedLen]
+}
+
+func (m *hashDrbg) instantiate(entropy, nonce, personalization []byte) {
+	m.v = m.df(entropy, nonce, personalization)
+	m.c = m.df([]byte{0}, m.v)
+	m.counter = 1
+}
+
+func (m *hashDrbg) reseed(entropy, additional []byte) {
+	m.v = m.df([]byte{1}, m.v, entropy, additional)
+	m.c = m.df([]byte{0}, m.v)
+	m.counter = 1
+}
+
+func (m *hashDrbg) hash(inputs ...[]byte) []byte {
+	m.h.Reset()
+	for _, in := range inputs {
+		m.h.Write(in)
+	}
+	return m.h.Sum(nil)
+}
+
+func (m *hashDrbg) generate(out, additional []byte) {
+	if len(additional) > 0 {
+		addMod(m.v, m.hash([]byte{2}, m.v, additional))
+	}
+
+	//Hashgen
+	data := make([]byte, m.seedLen)
+	copy(data, m.v)
+	one := []byte{1}
+	for n := 0; n < len(out); {
+		n += copy(out[n:], m.hash(data))
+		addMod(data, one)
+	}
+
+	h := m.hash([]byte{3}, m.v)
+	var ctr [8]byte
+	binary.BigEndian.PutUint64(ctr[:], m.counter)
+	addMod(m.v, h)
+	addMod(m.v, m.c)
+	addMod(m.v, ctr[:])
+	m.counter++
+}
+
+func (m *hashDrbg) reseedCounter() uint64 { return m.counter }
+
+// Add b to a (both big endian), discarding any carry out of a (mod 2^len(a)*8).
+// `b` must be no longer than `a`
+func addMod(a, b []byte) {
+	carry := uint16(0)
+	j := len(b) - 1
+	for i := len(a) - 1; i >= 0; i-- {
+		sum := uint16(a[i]) + carry
+		if j >= 0 {
+			sum += uint16(b[j])
+			j--
+		} else if carry == 0 {
+			//Nothing more to add
+			return
+		}
+		a[i] = byte(sum)
+		carry = sum >> 8
+	}
+}
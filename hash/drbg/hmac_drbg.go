@@ -0,0 +1,77 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+package drbg
+
+import (
+	"crypto/hmac"
+	"hash"
+	"io"
+)
+
+// HMAC_DRBG (SP 800-90A 10.1.2)
+type hmacDrbg struct {
+	h       func() hash.Hash
+	k       []byte //Key
+	v       []byte //Value
+	counter uint64 //Reseed counter
+}
+
+// A new HMAC_DRBG using HMAC-`h`, reading entropy from `entropy` when instantiating
+// and reseeding.  If nonce is nil one is drawn from the entropy source
+func NewHMAC(h func() hash.Hash, entropy io.Reader, nonce, personalization []byte) (*DRBG, error) {
+	return newDRBG(&hmacDrbg{h: h}, h, entropy, nonce, personalization)
+}
+
+func (m *hmacDrbg) mac(key []byte, inputs ...[]byte) []byte {
+	mac := hmac.New(m.h, key)
+	for _, in := range inputs {
+		mac.Write(in)
+	}
+	return mac.Sum(nil)
+}
+
+// HMAC_DRBG_Update (SP 800-90A 10.1.2.2), provided data may be several slices
+func (m *hmacDrbg) update(data ...[]byte) {
+	empty := true
+	for _, d := range data {
+		empty = empty && len(d) == 0
+	}
+	m.k = m.mac(m.k, append([][]byte{m.v, {0}}, data...)...)
+	m.v = m.mac(m.k, m.v)
+	if empty {
+		return
+	}
+	m.k = m.mac(m.k, append([][]byte{m.v, {1}}, data...)...)
+	m.v = m.mac(m.k, m.v)
+}
+
+func (m *hmacDrbg) instantiate(entropy, nonce, personalization []byte) {
+	n := m.h().Size()
+	m.k = make([]byte, n)
+	m.v = make([]byte, n)
+	for i := range m.v {
+		m.v[i] = 1
+	}
+	m.update(entropy, nonce, personalization)
+	m.counter = 1
+}
+
+func (m *hmacDrbg) reseed(entropy, additional []byte) {
+	m.update(entropy, additional)
+	m.counter = 1
+}
+
+func (m *hmacDrbg) generate(out, additional []byte) {
+	if len(additional) > 0 {
+		m.update(additional)
+	}
+	for n := 0; n < len(out); {
+		m.v = m.mac(m.k, m.v)
+		n += copy(out[n:], m.v)
+	}
+	m.update(additional)
+	m.counter++
+}
+
+func (m *hmacDrbg) reseedCounter() uint64 { return m.counter }
@@ -0,0 +1,93 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+// Hash-based signatures: Lamport and Winternitz (WOTS+) one-time signatures, and a
+// Merkle tree many-time wrapper over WOTS+.  These only depend on the hash function
+// chosen (eg. ripemd.New160, whirlpool.New)
+package hbs
+
+import (
+	"crypto/subtle"
+	"errors"
+	"hash"
+	"io"
+)
+
+//https://en.wikipedia.org/wiki/Lamport_signature
+
+// A one-time key has already been used to sign
+var ErrKeyUsed = errors.New("one-time key has already been used")
+
+// A Lamport one-time private key
+type LamportKey struct {
+	h    func() hash.Hash
+	n    int    //Hash size in bytes
+	sk   []byte //2 secrets (for a 0/1 bit) per digest bit, each n bytes
+	pk   []byte //Hash of each secret
+	used bool
+}
+
+// Generate a new Lamport key using hash `h`, reading secrets from `rand`
+func GenerateLamport(h func() hash.Hash, rand io.Reader) (*LamportKey, error) {
+	d := h()
+	n := d.Size()
+	k := &LamportKey{h: h, n: n, sk: make([]byte, 2*8*n*n)}
+	if _, err := io.ReadFull(rand, k.sk); err != nil {
+		return nil, err
+	}
+	k.pk = make([]byte, 0, len(k.sk))
+	for i := 0; i < len(k.sk); i += n {
+		d.Reset()
+		d.Write(k.sk[i : i+n])
+		k.pk = d.Sum(k.pk)
+	}
+	return k, nil
+}
+
+// The public key, hashes of each secret (2*8*n*n bytes, where n is hash size)
+func (k *LamportKey) PublicKey() []byte {
+	return append([]byte{}, k.pk...)
+}
+
+// Sign a message, which reveals one secret per digest bit.  A key can only be
+// used once, further attempts return ErrKeyUsed
+func (k *LamportKey) Sign(msg []byte) ([]byte, error) {
+	if k.used {
+		return nil, ErrKeyUsed
+	}
+	k.used = true
+
+	digest := hashOf(k.h(), msg)
+	sig := make([]byte, 0, 8*k.n*k.n)
+	for i := 0; i < 8*k.n; i++ {
+		bit := int(digest[i>>3]>>(7-i&7)) & 1
+		pos := (2*i + bit) * k.n
+		sig = append(sig, k.sk[pos:pos+k.n]...)
+	}
+	return sig, nil
+}
+
+// Verify a Lamport signature of `msg` against public key `pub`
+func LamportVerify(h func() hash.Hash, pub, msg, sig []byte) bool {
+	d := h()
+	n := d.Size()
+	if len(pub) != 2*8*n*n || len(sig) != 8*n*n {
+		return false
+	}
+	digest := hashOf(d, msg)
+	ok := 1
+	for i := 0; i < 8*n; i++ {
+		bit := int(digest[i>>3]>>(7-i&7)) & 1
+		pos := (2*i + bit) * n
+		d.Reset()
+		d.Write(sig[i*n : i*n+n])
+		ok &= subtle.ConstantTimeCompare(d.Sum(nil), pub[pos:pos+n])
+	}
+	return ok == 1
+}
+
+func hashOf(d hash.Hash, msg []byte) []byte {
+	d.Reset()
+	d.Write(msg)
+	return d.Sum(nil)
+}
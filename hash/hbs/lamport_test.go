@@ -0,0 +1,71 @@
+package hbs
+
+import (
+	"bytes"
+	"hash"
+	"math/rand"
+	"testing"
+
+	"github.com/gnabgib/gnablib-go/hash/ripemd"
+	"github.com/gnabgib/gnablib-go/hash/whirlpool"
+)
+
+// Deterministic randomness, so the tests repeat
+func testRand() *rand.Rand { return rand.New(rand.NewSource(0x686273)) }
+
+var hashes = []struct {
+	name string
+	h    func() hash.Hash
+}{
+	{"ripemd128", ripemd.New128},
+	{"ripemd160", ripemd.New160},
+	{"whirlpool", whirlpool.New},
+}
+
+func TestLamport(t *testing.T) {
+	msg := []byte("firmware v1.2.3")
+	for _, rec := range hashes {
+		k, err := GenerateLamport(rec.h, testRand())
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", rec.name, err)
+			continue
+		}
+		pub := k.PublicKey()
+		sig, err := k.Sign(msg)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", rec.name, err)
+			continue
+		}
+		if !LamportVerify(rec.h, pub, msg, sig) {
+			t.Errorf("%s: expecting signature to verify", rec.name)
+		}
+		if LamportVerify(rec.h, pub, []byte("firmware v1.2.4"), sig) {
+			t.Errorf("%s: expecting a different message to fail", rec.name)
+		}
+		bad := append([]byte{}, sig...)
+		bad[len(bad)/2] ^= 1
+		if LamportVerify(rec.h, pub, msg, bad) {
+			t.Errorf("%s: expecting a modified signature to fail", rec.name)
+		}
+		if LamportVerify(rec.h, pub, msg, sig[1:]) {
+			t.Errorf("%s: expecting a short signature to fail", rec.name)
+		}
+	}
+}
+
+func TestLamport_oneTime(t *testing.T) {
+	k, _ := GenerateLamport(ripemd.New160, testRand())
+	k.Sign([]byte("a"))
+	if _, err := k.Sign([]byte("b")); err != ErrKeyUsed {
+		t.Errorf("Expecting ErrKeyUsed, got %v", err)
+	}
+}
+
+func TestLamport_publicKeyCopy(t *testing.T) {
+	k, _ := GenerateLamport(ripemd.New160, testRand())
+	pub := k.PublicKey()
+	pub[0] ^= 1
+	if bytes.Equal(pub, k.PublicKey()) {
+		t.Errorf("Expecting PublicKey to return a copy")
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+package hbs
+
+import (
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"io"
+)
+
+//https://en.wikipedia.org/wiki/Merkle_signature_scheme
+
+// Every leaf of a many-time key has been used
+var ErrKeyExhausted = errors.New("all one-time keys have been used")
+
+// Tree heights are limited so the whole tree can be kept in memory
+var ErrHeight = errors.New("height must be 1-20")
+
+const maxHeight = 20
+
+// A Merkle tree of 2^height WOTS+ keys, which can sign 2^height messages
+type MerkleKey struct {
+	p       *Wots
+	height  int
+	skSeed  []byte
+	pubSeed []byte
+	nodes   [][]byte //Levels of the tree, 0=leaves, height=root
+	state   StateStore
+}
+
+// Generate a new Merkle key of `height` reading seeds from `rand`, the used-leaf
+// counter is kept in `state`
+func (p *Wots) GenerateMerkle(height int, rand io.Reader, state StateStore) (*MerkleKey, error) {
+	seeds := make([]byte, 2*p.n)
+	if _, err := io.ReadFull(rand, seeds); err != nil {
+		return nil, err
+	}
+	return p.NewMerkle(height, seeds[:p.n], seeds[p.n:], state)
+}
+
+// Rebuild a Merkle key of `height` from its seeds (see Seeds), the used-leaf counter
+// is kept in `state`
+func (p *Wots) NewMerkle(height int, skSeed, pubSeed []byte, state StateStore) (*MerkleKey, error) {
+	if height < 1 || height > maxHeight {
+		return nil, ErrHeight
+	}
+	if len(skSeed) != p.n || len(pubSeed) != p.n {
+		return nil, errors.New("seeds must be the hash size")
+	}
+	k := &MerkleKey{p: p, height: height, skSeed: skSeed, pubSeed: pubSeed, state: state}
+	d := p.h()
+	nLeaves := 1 << height
+	level := make([][]byte, nLeaves)
+	for i := range level {
+		level[i] = p.leaf(d, pubSeed, uint32(i), p.publicChains(skSeed, pubSeed, uint32(i)))
+	}
+	k.nodes = append(k.nodes, flatten(level))
+	for h := 1; h <= height; h++ {
+		next := make([][]byte, len(level)/2)
+		for i := range next {
+			next[i] = p.node(d, pubSeed, h, uint32(i), level[2*i], level[2*i+1])
+		}
+		k.nodes = append(k.nodes, flatten(next))
+		level = next
+	}
+	return k, nil
+}
+
+func flatten(level [][]byte) []byte {
+	ret := make([]byte, 0, len(level)*len(level[0]))
+	for _, b := range level {
+		ret = append(ret, b...)
+	}
+	return ret
+}
+
+// Compress a WOTS+ public key into a leaf
+func (p *Wots) leaf(d hash.Hash, pubSeed []byte, idx uint32, chains []byte) []byte {
+	var a adrs
+	a.set(12, adrsLeaf)
+	a.set(16, idx)
+	return p.hashPad(d, padH, p.prf(d, pubSeed, &a), chains)
+}
+
+// Combine two children into a node at height `h`
+func (p *Wots) node(d hash.Hash, pubSeed []byte, h int, idx uint32, left, right []byte) []byte {
+	var a adrs
+	a.set(12, adrsTree)
+	a.set(20, uint32(h))
+	a.set(24, idx)
+	return p.hashPad(d, padH, p.prf(d, pubSeed, &a), left, right)
+}
+
+// The secret and public seeds, which (along with height and the state) are all
+// that's needed to rebuild the key with NewMerkle
+func (k *MerkleKey) Seeds() (skSeed, pubSeed []byte) {
+	return append([]byte{}, k.skSeed...), append([]byte{}, k.pubSeed...)
+}
+
+// The public key: public seed followed by the tree root
+func (k *MerkleKey) PublicKey() []byte {
+	return append(append([]byte{}, k.pubSeed...), k.nodes[k.height]...)
+}
+
+// Number of signatures that can still be made
+func (k *MerkleKey) Remaining() (uint64, error) {
+	next, err := k.state.Load()
+	if err != nil {
+		return 0, err
+	}
+	total := uint64(1) << k.height
+	if next >= total {
+		return 0, nil
+	}
+	return total - next, nil
+}
+
+// Sign a message with the next unused leaf.  The state is advanced before the
+// signature is produced, if it can't be saved no signature is returned. Once all
+// leaves are used ErrKeyExhausted is returned
+func (k *MerkleKey) Sign(msg []byte) ([]byte, error) {
+	idx, err := k.state.Load()
+	if err != nil {
+		return nil, err
+	}
+	if idx >= uint64(1)<<k.height {
+		return nil, ErrKeyExhausted
+	}
+	if err = k.state.Save(idx + 1); err != nil {
+		return nil, err
+	}
+
+	n := k.p.n
+	sig := make([]byte, 4, k.p.MerkleSignatureSize(k.height))
+	binary.BigEndian.PutUint32(sig, uint32(idx))
+	sig = append(sig, k.p.sign(k.skSeed, k.pubSeed, uint32(idx), msg)...)
+	//Authentication path, the sibling at each level
+	for h := 0; h < k.height; h++ {
+		sib := (int(idx) >> h) ^ 1
+		sig = append(sig, k.nodes[h][sib*n:(sib+1)*n]...)
+	}
+	return sig, nil
+}
+
+// Size of a Merkle signature in bytes (leaf index, WOTS+ signature and auth path)
+func (p *Wots) MerkleSignatureSize(height int) int {
+	return 4 + p.SignatureSize() + height*p.n
+}
+
+// Verify a Merkle signature of `msg` against public key `pub` for a tree of `height`
+func (p *Wots) VerifyMerkle(height int, pub, msg, sig []byte) bool {
+	if height < 1 || height > maxHeight ||
+		len(pub) != 2*p.n || len(sig) != p.MerkleSignatureSize(height) {
+		return false
+	}
+	idx := binary.BigEndian.Uint32(sig)
+	if uint64(idx) >= uint64(1)<<height {
+		return false
+	}
+	pubSeed := pub[:p.n]
+	d := p.h()
+	sigEnd := 4 + p.SignatureSize()
+	node := p.leaf(d, pubSeed, idx, p.chainsFromSig(pubSeed, idx, msg, sig[4:sigEnd]))
+	auth := sig[sigEnd:]
+	for h := 0; h < height; h++ {
+		sib := auth[h*p.n : (h+1)*p.n]
+		pos := idx >> h
+		if pos&1 == 0 {
+			node = p.node(d, pubSeed, h+1, pos>>1, node, sib)
+		} else {
+			node = p.node(d, pubSeed, h+1, pos>>1, sib, node)
+		}
+	}
+	return subtle.ConstantTimeCompare(node, pub[p.n:]) == 1
+}
@@ -0,0 +1,135 @@
+package hbs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gnabgib/gnablib-go/hash/ripemd"
+	"github.com/gnabgib/gnablib-go/hash/whirlpool"
+)
+
+func TestMerkle(t *testing.T) {
+	for _, rec := range hashes {
+		p, _ := NewWots(rec.h, 16)
+		const height = 3
+		k, err := p.GenerateMerkle(height, testRand(), NewMemoryState(0))
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", rec.name, err)
+			continue
+		}
+		pub := k.PublicKey()
+		for i := 0; i < 1<<height; i++ {
+			msg := []byte{byte(i), 'm', 's', 'g'}
+			sig, err := k.Sign(msg)
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", rec.name, err)
+				break
+			}
+			if len(sig) != p.MerkleSignatureSize(height) {
+				t.Errorf("%s: expecting %d byte signature, got %d", rec.name, p.MerkleSignatureSize(height), len(sig))
+			}
+			if !p.VerifyMerkle(height, pub, msg, sig) {
+				t.Errorf("%s: expecting leaf %d to verify", rec.name, i)
+			}
+			if p.VerifyMerkle(height, pub, []byte("other"), sig) {
+				t.Errorf("%s: expecting a different message to fail", rec.name)
+			}
+			//Tamper with the auth path
+			bad := append([]byte{}, sig...)
+			bad[len(bad)-1] ^= 1
+			if p.VerifyMerkle(height, pub, msg, bad) {
+				t.Errorf("%s: expecting a modified auth path to fail", rec.name)
+			}
+			//Claim a different leaf
+			bad = append([]byte{}, sig...)
+			bad[3] ^= 1
+			if p.VerifyMerkle(height, pub, msg, bad) {
+				t.Errorf("%s: expecting a different leaf index to fail", rec.name)
+			}
+		}
+		if _, err = k.Sign([]byte("one more")); err != ErrKeyExhausted {
+			t.Errorf("%s: expecting ErrKeyExhausted, got %v", rec.name, err)
+		}
+		if n, _ := k.Remaining(); n != 0 {
+			t.Errorf("%s: expecting 0 remaining, got %d", rec.name, n)
+		}
+	}
+}
+
+func TestMerkle_fileStateSurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key.state")
+	p, _ := NewWots(ripemd.New160, 16)
+	state, err := CreateFileState(path)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	k, _ := p.GenerateMerkle(2, testRand(), state)
+	skSeed, pubSeed := k.Seeds()
+	sig1, _ := k.Sign([]byte("one"))
+
+	//"Restart" rebuilding the key from its seeds and state file
+	k2, err := p.NewMerkle(2, skSeed, pubSeed, NewFileState(path))
+	if err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+	if n, _ := k2.Remaining(); n != 3 {
+		t.Errorf("Expecting 3 remaining, got %d", n)
+	}
+	sig2, _ := k2.Sign([]byte("two"))
+	//The leaf index is the first 4 bytes, it must have moved on
+	if string(sig1[:4]) == string(sig2[:4]) {
+		t.Errorf("Leaf was reused after restart")
+	}
+	if !p.VerifyMerkle(2, k.PublicKey(), []byte("two"), sig2) {
+		t.Errorf("Expecting rebuilt key signature to verify")
+	}
+}
+
+type failingState struct{}
+
+func (failingState) Load() (uint64, error) { return 0, nil }
+func (failingState) Save(uint64) error     { return errors.New("disk full") }
+
+func TestMerkle_stateSaveFailure(t *testing.T) {
+	p, _ := NewWots(ripemd.New160, 16)
+	k, _ := p.GenerateMerkle(1, testRand(), failingState{})
+	if sig, err := k.Sign([]byte("a")); err == nil || sig != nil {
+		t.Errorf("Expecting no signature when state can't be saved")
+	}
+}
+
+func TestFileState_missing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key.state")
+	if _, err := NewFileState(path).Load(); err != ErrNoState {
+		t.Errorf("Expecting %v, got %v", ErrNoState, err)
+	}
+	if _, err := CreateFileState(path); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+	if n, err := NewFileState(path).Load(); n != 0 || err != nil {
+		t.Errorf("Expecting 0, got %d %v", n, err)
+	}
+	//Creating again would reset a used key
+	if _, err := CreateFileState(path); !errors.Is(err, os.ErrExist) {
+		t.Errorf("Expecting %v, got %v", os.ErrExist, err)
+	}
+}
+
+func TestFileState_corrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key.state")
+	os.WriteFile(path, []byte{1, 2, 3}, 0600)
+	if _, err := NewFileState(path).Load(); err == nil {
+		t.Errorf("Expecting corrupt state error")
+	}
+}
+
+func TestMerkle_badHeight(t *testing.T) {
+	p, _ := NewWots(whirlpool.New, 16)
+	for _, h := range []int{0, 21} {
+		if _, err := p.GenerateMerkle(h, testRand(), NewMemoryState(0)); err != ErrHeight {
+			t.Errorf("Height %d expecting ErrHeight, got %v", h, err)
+		}
+	}
+}
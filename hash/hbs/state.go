@@ -0,0 +1,111 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+package hbs
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// Stores the index of the next unused leaf of a many-time key.  Reusing a leaf
+// breaks the security of the key, so the state must be saved before a signature
+// is released
+type StateStore interface {
+	//The next unused leaf
+	Load() (uint64, error)
+	//Record that leaves before `next` have been used
+	Save(next uint64) error
+}
+
+type memoryState struct {
+	next uint64
+}
+
+// A state store held in memory, only suitable when the key doesn't outlive the process
+func NewMemoryState(next uint64) StateStore {
+	return &memoryState{next: next}
+}
+
+func (m *memoryState) Load() (uint64, error) { return m.next, nil }
+
+func (m *memoryState) Save(next uint64) error {
+	m.next = next
+	return nil
+}
+
+type fileState struct {
+	path string
+}
+
+// The state file doesn't exist. A lost state file can't be told apart from a
+// new key, so it must be created explicitly (see CreateFileState)
+var ErrNoState = errors.New("state file missing")
+
+// A state store persisted to a file (as a big endian uint64) that must already
+// exist, Load returns ErrNoState if it doesn't.  Saves are written to a temporary
+// file, synced and then renamed over the original
+func NewFileState(path string) StateStore {
+	return &fileState{path: path}
+}
+
+// Create the state file for a new key (no leaves used), fails if the file already
+// exists so an existing key's state can't be reset
+func CreateFileState(path string) (StateStore, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, err
+	}
+	var b [8]byte
+	_, err = file.Write(b[:])
+	if err == nil {
+		err = file.Sync()
+	}
+	if cErr := file.Close(); err == nil {
+		err = cErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+	return &fileState{path: path}, nil
+}
+
+func (f *fileState) Load() (uint64, error) {
+	b, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, ErrNoState
+	}
+	if err != nil {
+		return 0, err
+	}
+	if len(b) != 8 {
+		return 0, errors.New("corrupt state file")
+	}
+	return binary.BigEndian.Uint64(b), nil
+}
+
+func (f *fileState) Save(next uint64) error {
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".*")
+	if err != nil {
+		return err
+	}
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], next)
+	_, err = tmp.Write(b[:])
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if cErr := tmp.Close(); err == nil {
+		err = cErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), f.path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return err
+}
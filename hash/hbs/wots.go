@@ -0,0 +1,231 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+package hbs
+
+import (
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"io"
+	"math/bits"
+)
+
+//https://eprint.iacr.org/2017/965.pdf (WOTS+)
+//https://datatracker.ietf.org/doc/html/rfc8391#section-3 (chaining, F, PRF and address layout)
+
+// The Winternitz parameter isn't supported
+var ErrWinternitz = errors.New("winternitz parameter must be 4, 16 or 256")
+
+const (
+	adrsSize = 32
+	//Address types
+	adrsOts  = 0
+	adrsLeaf = 1
+	adrsTree = 2
+	//Hash function domain separators (RFC 8391 5.1)
+	padF   = 0
+	padH   = 1
+	padPrf = 3
+)
+
+// A hash address (RFC 8391 2.5), layer and tree are always zero here:
+// 12:type, 16:ots/leaf index, 20:chain/height, 24:hash/index, 28:keyAndMask
+type adrs [adrsSize]byte
+
+func (a *adrs) set(pos int, v uint32) { binary.BigEndian.PutUint32(a[pos:], v) }
+
+// WOTS+ parameters, for a hash and Winternitz parameter w
+type Wots struct {
+	h    func() hash.Hash
+	n    int //Hash size in bytes
+	w    int
+	logW int
+	len1 int //Number of message digits
+	len2 int //Number of checksum digits
+}
+
+// WOTS+ parameters for hash `h` and Winternitz parameter `w` (4, 16 or 256). Larger
+// w gives shorter signatures at the cost of more hashing
+func NewWots(h func() hash.Hash, w int) (*Wots, error) {
+	if w != 4 && w != 16 && w != 256 {
+		return nil, ErrWinternitz
+	}
+	p := &Wots{h: h, n: h().Size(), w: w, logW: bits.TrailingZeros(uint(w))}
+	p.len1 = (8*p.n + p.logW - 1) / p.logW
+	//floor(log2(len1*(w-1))/log2(w))+1
+	p.len2 = (bits.Len(uint(p.len1*(w-1)))-1)/p.logW + 1
+	return p, nil
+}
+
+// Number of hash chains (signature length in hashes)
+func (p *Wots) Len() int { return p.len1 + p.len2 }
+
+// Size of a signature in bytes
+func (p *Wots) SignatureSize() int { return p.Len() * p.n }
+
+// Size of a public key in bytes (public seed and chain ends)
+func (p *Wots) PublicKeySize() int { return p.n + p.Len()*p.n }
+
+// Hash of toByte(pad,n) || key || m...
+func (p *Wots) hashPad(d hash.Hash, pad byte, key []byte, m ...[]byte) []byte {
+	d.Reset()
+	pre := make([]byte, p.n)
+	pre[p.n-1] = pad
+	d.Write(pre)
+	d.Write(key)
+	for _, b := range m {
+		d.Write(b)
+	}
+	return d.Sum(nil)
+}
+
+func (p *Wots) prf(d hash.Hash, key []byte, a *adrs) []byte {
+	return p.hashPad(d, padPrf, key, a[:])
+}
+
+// Iterate F on x `steps` times, from chain position `start` (RFC 8391 3.1.2)
+func (p *Wots) chain(d hash.Hash, x []byte, start, steps int, pubSeed []byte, a *adrs) []byte {
+	tmp := append([]byte{}, x...)
+	for j := start; j < start+steps; j++ {
+		a.set(24, uint32(j))
+		a.set(28, 0)
+		key := p.prf(d, pubSeed, a)
+		a.set(28, 1)
+		bm := p.prf(d, pubSeed, a)
+		for i := range tmp {
+			tmp[i] ^= bm[i]
+		}
+		tmp = p.hashPad(d, padF, key, tmp)
+	}
+	return tmp
+}
+
+// Message digest and checksum as base-w digits (RFC 8391 3.1.5)
+func (p *Wots) digits(d hash.Hash, msg []byte) []int {
+	ret := baseW(hashOf(d, msg), p.logW, p.len1)
+	csum := 0
+	for _, v := range ret {
+		csum += p.w - 1 - v
+	}
+	//Left shift so the checksum digits are byte aligned
+	csumBits := p.len2 * p.logW
+	csum <<= (8 - csumBits%8) % 8
+	cb := make([]byte, (csumBits+7)/8)
+	for i := len(cb) - 1; i >= 0; i-- {
+		cb[i] = byte(csum)
+		csum >>= 8
+	}
+	return append(ret, baseW(cb, p.logW, p.len2)...)
+}
+
+// Split bytes into `n` base-2^logW digits (big endian)
+func baseW(b []byte, logW, n int) []int {
+	ret := make([]int, n)
+	in, bitsLeft := 0, 0
+	total := 0
+	for i := 0; i < n; i++ {
+		if bitsLeft == 0 {
+			total = int(b[in])
+			in++
+			bitsLeft = 8
+		}
+		bitsLeft -= logW
+		ret[i] = (total >> bitsLeft) & (1<<logW - 1)
+	}
+	return ret
+}
+
+// The chain secrets for a key at `leaf`, derived from skSeed
+func (p *Wots) secret(d hash.Hash, skSeed []byte, leaf uint32, chain int) []byte {
+	var a adrs
+	a.set(12, adrsOts)
+	a.set(16, leaf)
+	a.set(20, uint32(chain))
+	return p.prf(d, skSeed, &a)
+}
+
+// Chain ends for the key at `leaf`
+func (p *Wots) publicChains(skSeed, pubSeed []byte, leaf uint32) []byte {
+	d := p.h()
+	ret := make([]byte, 0, p.Len()*p.n)
+	var a adrs
+	a.set(12, adrsOts)
+	a.set(16, leaf)
+	for i := 0; i < p.Len(); i++ {
+		a.set(20, uint32(i))
+		ret = append(ret, p.chain(d, p.secret(d, skSeed, leaf, i), 0, p.w-1, pubSeed, &a)...)
+	}
+	return ret
+}
+
+func (p *Wots) sign(skSeed, pubSeed []byte, leaf uint32, msg []byte) []byte {
+	d := p.h()
+	sig := make([]byte, 0, p.SignatureSize())
+	var a adrs
+	a.set(12, adrsOts)
+	a.set(16, leaf)
+	for i, v := range p.digits(d, msg) {
+		a.set(20, uint32(i))
+		sig = append(sig, p.chain(d, p.secret(d, skSeed, leaf, i), 0, v, pubSeed, &a)...)
+	}
+	return sig
+}
+
+// Compute the chain ends from a signature (which should match the public key)
+func (p *Wots) chainsFromSig(pubSeed []byte, leaf uint32, msg, sig []byte) []byte {
+	d := p.h()
+	ret := make([]byte, 0, p.Len()*p.n)
+	var a adrs
+	a.set(12, adrsOts)
+	a.set(16, leaf)
+	for i, v := range p.digits(d, msg) {
+		a.set(20, uint32(i))
+		ret = append(ret, p.chain(d, sig[i*p.n:(i+1)*p.n], v, p.w-1-v, pubSeed, &a)...)
+	}
+	return ret
+}
+
+// A WOTS+ one-time private key
+type WotsKey struct {
+	p       *Wots
+	skSeed  []byte
+	pubSeed []byte
+	pk      []byte
+	used    bool
+}
+
+// Generate a new WOTS+ key, reading seeds from `rand`
+func (p *Wots) GenerateKey(rand io.Reader) (*WotsKey, error) {
+	seeds := make([]byte, 2*p.n)
+	if _, err := io.ReadFull(rand, seeds); err != nil {
+		return nil, err
+	}
+	k := &WotsKey{p: p, skSeed: seeds[:p.n], pubSeed: seeds[p.n:]}
+	k.pk = append(append([]byte{}, k.pubSeed...), p.publicChains(k.skSeed, k.pubSeed, 0)...)
+	return k, nil
+}
+
+// The public key: public seed followed by the chain ends
+func (k *WotsKey) PublicKey() []byte {
+	return append([]byte{}, k.pk...)
+}
+
+// Sign a message.  A key can only be used once, further attempts return ErrKeyUsed
+func (k *WotsKey) Sign(msg []byte) ([]byte, error) {
+	if k.used {
+		return nil, ErrKeyUsed
+	}
+	k.used = true
+	return k.p.sign(k.skSeed, k.pubSeed, 0, msg), nil
+}
+
+// Verify a WOTS+ signature of `msg` against public key `pub`
+func (p *Wots) Verify(pub, msg, sig []byte) bool {
+	if len(pub) != p.PublicKeySize() || len(sig) != p.SignatureSize() {
+		return false
+	}
+	found := p.chainsFromSig(pub[:p.n], 0, msg, sig)
+	return subtle.ConstantTimeCompare(found, pub[p.n:]) == 1
+}
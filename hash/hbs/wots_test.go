@@ -0,0 +1,112 @@
+package hbs
+
+import (
+	"testing"
+
+	"github.com/gnabgib/gnablib-go/hash/ripemd"
+)
+
+var wotsLenTests = []struct {
+	w          int
+	len1, len2 int
+}{
+	//RIPEMD-160 n=20
+	{4, 80, 4},
+	{16, 40, 3},
+	{256, 20, 2},
+}
+
+func TestWotsLen(t *testing.T) {
+	for _, rec := range wotsLenTests {
+		p, _ := NewWots(ripemd.New160, rec.w)
+		if p.len1 != rec.len1 || p.len2 != rec.len2 {
+			t.Errorf("w=%d expecting %d,%d got %d,%d", rec.w, rec.len1, rec.len2, p.len1, p.len2)
+		}
+	}
+}
+
+func TestWots_badW(t *testing.T) {
+	for _, w := range []int{0, 1, 2, 8, 15, 512} {
+		if _, err := NewWots(ripemd.New160, w); err != ErrWinternitz {
+			t.Errorf("w=%d expecting ErrWinternitz, got %v", w, err)
+		}
+	}
+}
+
+func TestBaseW(t *testing.T) {
+	found := baseW([]byte{0x12, 0x34}, 4, 4)
+	for i, v := range []int{1, 2, 3, 4} {
+		if found[i] != v {
+			t.Errorf("Expecting %d at %d, got %d", v, i, found[i])
+		}
+	}
+	found = baseW([]byte{0xe4}, 2, 4)
+	for i, v := range []int{3, 2, 1, 0} {
+		if found[i] != v {
+			t.Errorf("Expecting %d at %d, got %d", v, i, found[i])
+		}
+	}
+}
+
+func TestWots(t *testing.T) {
+	msg := []byte("firmware v1.2.3")
+	for _, rec := range hashes {
+		for _, w := range []int{4, 16, 256} {
+			p, _ := NewWots(rec.h, w)
+			k, err := p.GenerateKey(testRand())
+			if err != nil {
+				t.Errorf("%s/%d: unexpected error %v", rec.name, w, err)
+				continue
+			}
+			pub := k.PublicKey()
+			if len(pub) != p.PublicKeySize() {
+				t.Errorf("%s/%d: expecting %d byte public key, got %d", rec.name, w, p.PublicKeySize(), len(pub))
+			}
+			sig, _ := k.Sign(msg)
+			if len(sig) != p.SignatureSize() {
+				t.Errorf("%s/%d: expecting %d byte signature, got %d", rec.name, w, p.SignatureSize(), len(sig))
+			}
+			if !p.Verify(pub, msg, sig) {
+				t.Errorf("%s/%d: expecting signature to verify", rec.name, w)
+			}
+			if p.Verify(pub, []byte("firmware v1.2.4"), sig) {
+				t.Errorf("%s/%d: expecting a different message to fail", rec.name, w)
+			}
+			bad := append([]byte{}, sig...)
+			bad[0] ^= 1
+			if p.Verify(pub, msg, bad) {
+				t.Errorf("%s/%d: expecting a modified signature to fail", rec.name, w)
+			}
+			if _, err = k.Sign(msg); err != ErrKeyUsed {
+				t.Errorf("%s/%d: expecting ErrKeyUsed, got %v", rec.name, w, err)
+			}
+		}
+	}
+}
+
+func TestWots_checksumBlocksForgery(t *testing.T) {
+	//Advancing a signature chain lets an attacker sign a larger digit, but the
+	// checksum then needs a smaller digit, which isn't possible
+	p, _ := NewWots(ripemd.New160, 16)
+	k, _ := p.GenerateKey(testRand())
+	pub := k.PublicKey()
+	msg := []byte("pay 10")
+	sig, _ := k.Sign(msg)
+	d := p.h()
+	digits := p.digits(d, msg)
+	var a adrs
+	a.set(12, adrsOts)
+	for i := 0; i < p.len1; i++ {
+		if digits[i] < p.w-1 {
+			forged := append([]byte{}, sig...)
+			a.set(20, uint32(i))
+			copy(forged[i*p.n:], p.chain(d, sig[i*p.n:(i+1)*p.n], digits[i], 1, pub[:p.n], &a))
+			//No message can make this verify without also changing the checksum chains,
+			// and it certainly won't for the original message
+			if p.Verify(pub, msg, forged) {
+				t.Errorf("Forged signature verified")
+			}
+			break
+		}
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+package kdf
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash"
+)
+
+// The requested length is negative, or larger than the function can produce
+var ErrLength = errors.New("requested key length is out of range")
+
+// Hash counter mode: hash(z || counter || other) for counter=start..
+// concatenated until `length` bytes are produced. The counter is a 32bit
+// big endian integer, so there's a limit of (2^32-start)*hash size bytes
+func counterKdf(h func() hash.Hash, z, other []byte, length int, start uint32) ([]byte, error) {
+	d := h()
+	hLen := d.Size()
+	if length < 0 || uint64(length) > uint64(hLen)*(uint64(1)<<32-uint64(start)) {
+		return nil, ErrLength
+	}
+
+	ret := make([]byte, 0, length+hLen)
+	var ctr [4]byte
+	for c := start; len(ret) < length; c++ {
+		d.Reset()
+		d.Write(z)
+		binary.BigEndian.PutUint32(ctr[:], c)
+		d.Write(ctr[:])
+		d.Write(other)
+		ret = d.Sum(ret)
+	}
+	return ret[:length], nil
+}
+
+// Mask generation function MGF1 (RFC 8017 B.2.1) of `length` bytes from `seed`
+func MGF1(h func() hash.Hash, seed []byte, length int) ([]byte, error) {
+	return counterKdf(h, seed, nil, length, 0)
+}
+
+// KDF1 (ISO 18033-2 6.2.2) derive `length` bytes from shared secret `z`, with
+// the counter starting at 0
+func KDF1(h func() hash.Hash, z, otherInfo []byte, length int) ([]byte, error) {
+	return counterKdf(h, z, otherInfo, length, 0)
+}
+
+// KDF2 (ISO 18033-2 6.2.3) derive `length` bytes from shared secret `z`, with
+// the counter starting at 1
+func KDF2(h func() hash.Hash, z, otherInfo []byte, length int) ([]byte, error) {
+	return counterKdf(h, z, otherInfo, length, 1)
+}
+
+// ANSI X9.63 KDF (SEC 1 3.6.1) derive `length` bytes from shared secret `z`,
+// which is the same construction as KDF2
+func X963(h func() hash.Hash, z, sharedInfo []byte, length int) ([]byte, error) {
+	return counterKdf(h, z, sharedInfo, length, 1)
+}
@@ -0,0 +1,80 @@
+package kdf
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"hash"
+	"testing"
+
+	"github.com/gnabgib/gnablib-go/encoding/hex"
+	"github.com/gnabgib/gnablib-go/hash/ripemd"
+	"github.com/gnabgib/gnablib-go/hash/whirlpool"
+	"github.com/gnabgib/gnablib-go/test"
+)
+
+var x963Tests = []struct {
+	h       func() hash.Hash
+	zHex    string
+	infoHex string
+	length  int
+	expect  string
+}{
+	//Source: NIST CAVP ANSI X9.63 KDF (SHA-256)
+	{sha256.New, "96C05619D56C328AB95FE84B18264B08725B85E33FD34F08", "", 16,
+		"443024C3DAE66B95E6F5670601558F71"},
+	{sha256.New, "22518B10E70F2A3F243810AE3254139EFBEE04AA57C7AF7D", "75EEF81AA3041E33B80971203D2C0C52", 128,
+		"C498AF77161CC59F2962B9A713E2B215152D139766CE34A776DF11866A69BF2E52A13D9C7C6FC878C50C5EA0BC7B00E0DA2447CFD874F6CF92F30D0097111485500C90C3AF8B487872D04685D14C8D1DC8D7FA08BEB0CE0ABABC11F0BD496269142D43525A78E5BC79A17F59676A5706DC54D54D4D1F0BD7E386128EC26AFC21"},
+	//Cross checked with openssl
+	{ripemd.New160, "22518B10E70F2A3F243810AE3254139EFBEE04AA57C7AF7D", "75EEF81AA3041E33B80971203D2C0C52", 50,
+		"8951EA07812219DBDAB172E997D50A80084C4FD7575C5F55ED2D19C12A0B446B4ED237B1847CEBB72B6FD54FBE4B79C8B775"},
+	{whirlpool.New, "22518B10E70F2A3F243810AE3254139EFBEE04AA57C7AF7D", "75EEF81AA3041E33B80971203D2C0C52", 100,
+		"B1D5873B250B39E370D517AC536A1550E46456AD5420BB003CE32D86B901D6C8D0D5FD1F4454C4D9420ED74F77797C6F9997DD4601F9E6B710D71FBC1BB1C78C7BFB41611268D53944687611DD2357CF4033B2E601EF7F079180EAF1807E89E790DC713E"},
+}
+
+func TestX963(t *testing.T) {
+	for _, rec := range x963Tests {
+		found, err := X963(rec.h, hex.ToBytesFast(rec.zHex), hex.ToBytesFast(rec.infoHex), rec.length)
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+			continue
+		}
+		test.StringMatch(t, rec.expect, hex.FromBytes(found))
+		//KDF2 is the same construction
+		found, _ = KDF2(rec.h, hex.ToBytesFast(rec.zHex), hex.ToBytesFast(rec.infoHex), rec.length)
+		test.StringMatch(t, rec.expect, hex.FromBytes(found))
+	}
+}
+
+func TestKDF1(t *testing.T) {
+	z := hex.ToBytesFast("22518B10E70F2A3F243810AE3254139EFBEE04AA57C7AF7D")
+	info := hex.ToBytesFast("75EEF81AA3041E33B80971203D2C0C52")
+	found, err := KDF1(ripemd.New160, z, info, 50)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	//KDF1 starts the counter at 0, so block 1 onwards matches KDF2
+	test.StringMatch(t,
+		"D613F234970203217231CC86B820224689F5AB108951EA07812219DBDAB172E997D50A80084C4FD7575C5F55ED2D19C12A0B",
+		hex.FromBytes(found))
+}
+
+func TestMGF1(t *testing.T) {
+	found, _ := MGF1(sha1.New, []byte("foo"), 50)
+	test.StringMatch(t,
+		"1AC9075CD427BC90B48A9966828CAB4A04C23FDF4C5EC5C9A25033CAFC76FF871855DFE57AB343E12201D3D6D5E7AA0233A0",
+		hex.FromBytes(found))
+	found, _ = MGF1(ripemd.New160, []byte("bar"), 40)
+	test.StringMatch(t,
+		"2E08D0EAB97342EB62476A8E03473FE690B2F15D3DC21E1EE91CB891D4F81E7FEC685EA371F17E3E",
+		hex.FromBytes(found))
+}
+
+func TestCounterKdf_length(t *testing.T) {
+	if _, err := MGF1(sha1.New, []byte("foo"), -1); err != ErrLength {
+		t.Errorf("Expecting ErrLength, got %v", err)
+	}
+	found, err := KDF2(sha1.New, []byte("foo"), nil, 0)
+	if err != nil || len(found) != 0 {
+		t.Errorf("Expecting empty result, got %x, %v", found, err)
+	}
+}
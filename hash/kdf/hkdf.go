@@ -0,0 +1,51 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+package kdf
+
+import (
+	"crypto/hmac"
+	"hash"
+)
+
+//https://datatracker.ietf.org/doc/html/rfc5869
+
+// Extract a pseudorandom key from input keying material `ikm` (RFC 5869 2.2).
+// A nil/empty salt is treated as a hash-length string of zeros
+func HKDFExtract(h func() hash.Hash, salt, ikm []byte) []byte {
+	if len(salt) == 0 {
+		salt = make([]byte, h().Size())
+	}
+	mac := hmac.New(h, salt)
+	mac.Write(ikm)
+	return mac.Sum(nil)
+}
+
+// Expand a pseudorandom key `prk` into `length` bytes of output keying material,
+// bound to `info` (RFC 5869 2.3).  Length may be at most 255*hash size
+func HKDFExpand(h func() hash.Hash, prk, info []byte, length int) ([]byte, error) {
+	mac := hmac.New(h, prk)
+	hLen := mac.Size()
+	if length < 0 || length > 255*hLen {
+		return nil, ErrLength
+	}
+
+	ret := make([]byte, 0, length+hLen)
+	var t []byte
+	for i := byte(1); len(ret) < length; i++ {
+		//T(i) = HMAC(PRK, T(i-1) | info | i)
+		mac.Reset()
+		mac.Write(t)
+		mac.Write(info)
+		mac.Write([]byte{i})
+		n := len(ret)
+		ret = mac.Sum(ret)
+		t = ret[n:]
+	}
+	return ret[:length], nil
+}
+
+// Derive `length` bytes from `ikm` using HKDF (extract then expand)
+func HKDF(h func() hash.Hash, ikm, salt, info []byte, length int) ([]byte, error) {
+	return HKDFExpand(h, HKDFExtract(h, salt, ikm), info, length)
+}
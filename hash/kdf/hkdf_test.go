@@ -0,0 +1,67 @@
+package kdf
+
+import (
+	"crypto/sha256"
+	"hash"
+	"testing"
+
+	"github.com/gnabgib/gnablib-go/encoding/hex"
+	"github.com/gnabgib/gnablib-go/hash/ripemd"
+	"github.com/gnabgib/gnablib-go/hash/whirlpool"
+	"github.com/gnabgib/gnablib-go/test"
+)
+
+var hkdfTests = []struct {
+	h       func() hash.Hash
+	ikmHex  string
+	saltHex string
+	infoHex string
+	length  int
+	prkHex  string //Empty to skip
+	okmHex  string
+}{
+	//Source: https://datatracker.ietf.org/doc/html/rfc5869#appendix-A (1, 3)
+	{sha256.New, "0B0B0B0B0B0B0B0B0B0B0B0B0B0B0B0B0B0B0B0B0B0B", "000102030405060708090A0B0C", "F0F1F2F3F4F5F6F7F8F9", 42,
+		"077709362C2E32DF0DDC3F0DC47BBA6390B6C73BB50F9C3122EC844AD7C2B3E5",
+		"3CB25F25FAACD57A90434F64D0362F2A2D2D0A90CF1A5A4C5DB02D56ECC4C5BF34007208D5B887185865"},
+	{sha256.New, "0B0B0B0B0B0B0B0B0B0B0B0B0B0B0B0B0B0B0B0B0B0B", "", "", 42,
+		"19EF24A32C717B167F33A91D6F648BDF96596776AFDB6377AC434C1C293CCB04",
+		"8DA4E775A563C18F715F802A063C5A31B8A11F5C5EE1879EC3454E5F3C738D2D9D201395FAA4B61A96C8"},
+	//Cross checked with openssl
+	{ripemd.New160, "0B0B0B0B0B0B0B0B0B0B0B0B0B0B0B0B0B0B0B0B0B0B", "000102030405060708090A0B0C", "F0F1F2F3F4F5F6F7F8F9", 42,
+		"EE783BC4F2019FAF334EC85C466822125EC783FD",
+		"8E2A6E5C36796C02636A4246873F35EDF59684F394DA0EC847B3643AA1F0059CE97DE9843CF9DB968A88"},
+	{whirlpool.New, "0B0B0B0B0B0B0B0B0B0B0B0B0B0B0B0B0B0B0B0B0B0B", "000102030405060708090A0B0C", "F0F1F2F3F4F5F6F7F8F9", 82, "",
+		"0D29F74CCD8640F44B0DD9638111C1B5766EFED752AF358109E2E7C9CD4A28EF2F90B2AD461FBA0744D4826DC931AC03FF337553F3C1EDEFA0601A5E58830765CB87A277DCB4A1363EE52B2D81B811953F02"},
+}
+
+func TestHKDF(t *testing.T) {
+	for _, rec := range hkdfTests {
+		ikm := hex.ToBytesFast(rec.ikmHex)
+		salt := hex.ToBytesFast(rec.saltHex)
+		info := hex.ToBytesFast(rec.infoHex)
+		if rec.prkHex != "" {
+			test.StringMatch(t, rec.prkHex, hex.FromBytes(HKDFExtract(rec.h, salt, ikm)))
+		}
+		found, err := HKDF(rec.h, ikm, salt, info, rec.length)
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+			continue
+		}
+		test.StringMatch(t, rec.okmHex, hex.FromBytes(found))
+	}
+}
+
+func TestHKDF_length(t *testing.T) {
+	prk := HKDFExtract(ripemd.New160, nil, []byte("ikm"))
+	found, err := HKDFExpand(ripemd.New160, prk, nil, 255*20)
+	if err != nil || len(found) != 255*20 {
+		t.Errorf("Expecting max length to work, got %d, %v", len(found), err)
+	}
+	if _, err = HKDFExpand(ripemd.New160, prk, nil, 255*20+1); err != ErrLength {
+		t.Errorf("Expecting ErrLength, got %v", err)
+	}
+	if _, err = HKDFExpand(ripemd.New160, prk, nil, -1); err != ErrLength {
+		t.Errorf("Expecting ErrLength, got %v", err)
+	}
+}
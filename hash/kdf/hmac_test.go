@@ -0,0 +1,73 @@
+package kdf
+
+import (
+	"crypto/hmac"
+	"hash"
+	"testing"
+
+	"github.com/gnabgib/gnablib-go/encoding/hex"
+	"github.com/gnabgib/gnablib-go/hash/ripemd"
+	"github.com/gnabgib/gnablib-go/hash/whirlpool"
+	"github.com/gnabgib/gnablib-go/test"
+)
+
+// The KDFs all rely on crypto/hmac working with the library's hashes
+
+var hmacTests = []struct {
+	keyHex string
+	data   string
+	r160   string
+	whirl  string
+}{
+	//RIPEMD-160 source: https://datatracker.ietf.org/doc/html/rfc2286#section-2
+	//Whirlpool: NESSIE only publishes vectors for the hash (checked in the whirlpool
+	// package), so HMAC-Whirlpool is cross checked with openssl 3
+	// (`openssl dgst -whirlpool -mac HMAC`, legacy provider)
+	{"0B0B0B0B0B0B0B0B0B0B0B0B0B0B0B0B0B0B0B0B", "Hi There",
+		"24CB4BD67D20FC1A5D2ED7732DCC39377F0A5668",
+		"8A2C9B1CCF4B28660DE78AF9DB15B7C94D129EC960CA9A950A665EA5E88362E24F4474354E18512D956D9BB7E6BBBB50B9BA0D3093B0A17C6EC2AA91E57169CE"},
+	{"4A656665", "what do ya want for nothing?",
+		"DDA6C0213A485A9E24F4742064A7F033B43C4069",
+		"3D595CCD1D4F4CFD045AF53BA7D5C8283FEE6DED6EAF1269071B6B4EA64800056B5077C6A942CFA1221BD4E5AED791276E5DD46A407D2B8007163D3E7CD1DE66"},
+	{"AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA", string(repeat(0xdd, 50)),
+		"B0B105360DE759960AB4F35298E116E295D8E7C1",
+		"EA252F252E230E3D1950CF44679E31D9DE70D1DEC6F41DBE38A12D76E2B54CFFA2637F0408A48A0A387315EF1118055D373DC295BBA3563276F846A0957FB823"},
+	{"0102030405060708090A0B0C0D0E0F10111213141516171819", string(repeat(0xcd, 50)),
+		"D5CA862F4D21D5E610E18B4CF1BEB97A4365ECF4",
+		"35BC33E2ED71E1CB01C140DDD3291AE3F84E9F0DCE18005A1123DF199983A211FE744B244449A1C093B17584069359BC6A95352271D78E2EF7A6F21DC28AB3C1"},
+	{"0C0C0C0C0C0C0C0C0C0C0C0C0C0C0C0C0C0C0C0C", "Test With Truncation",
+		"7619693978F91D90539AE786500FF3D8E0518E39",
+		"0B65A88CA3F6709DEF0758B525729EE92413D372E07D1E4A65E16ADBAB5793C35431061C56F6B8EB269C90F8D39EE8AC4D2E68091D4F3D4631CDF04C1C42D480"},
+	{hex.FromBytes(repeat(0xaa, 80)), "Test Using Larger Than Block-Size Key - Hash Key First",
+		"6466CA07AC5EAC29E1BD523E5ADA7605B791FD8B",
+		"DD90BD637CFCD27CA914C290F33402CF68576D6E70601AF0295F6B9DAFA9D988D8B8FB4FDE8605AC544638158DBA2BAA90A2BF882546CD0B876D59AB3F18962E"},
+	{hex.FromBytes(repeat(0xaa, 80)), "Test Using Larger Than Block-Size Key and Larger Than One Block-Size Data",
+		"69EA60798D71616CCE5FD0871E23754CD75D5A0A",
+		"FAF529AF18A0F569480FD001122BB446B32A3CF758C385CEC4ADAD9C73C77327812E260B7082011A3EB1071E71A50E1C3D37A8963F8B6A64A31CC017DB1D619C"},
+}
+
+func repeat(b byte, n int) []byte {
+	ret := make([]byte, n)
+	for i := range ret {
+		ret[i] = b
+	}
+	return ret
+}
+
+func hmacHex(h func() hash.Hash, keyHex, data string) string {
+	mac := hmac.New(h, hex.ToBytesFast(keyHex))
+	mac.Write([]byte(data))
+	return hex.FromBytes(mac.Sum(nil))
+}
+
+func TestHmacRipe160(t *testing.T) {
+	for _, rec := range hmacTests {
+		test.StringMatch(t, rec.r160, hmacHex(ripemd.New160, rec.keyHex, rec.data))
+	}
+}
+
+func TestHmacWhirlpool(t *testing.T) {
+	for _, rec := range hmacTests {
+		test.StringMatch(t, rec.whirl, hmacHex(whirlpool.New, rec.keyHex, rec.data))
+	}
+}
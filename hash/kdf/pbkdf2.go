@@ -0,0 +1,58 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+// Key derivation functions, parameterised by any hash (eg. ripemd.New160, whirlpool.New)
+package kdf
+
+import (
+	"crypto/hmac"
+	"encoding/binary"
+	"errors"
+	"hash"
+)
+
+// The iteration count is less than one
+var ErrIterations = errors.New("iteration count must be at least 1")
+
+//https://datatracker.ietf.org/doc/html/rfc8018#section-5.2
+
+// Derive a key of `keyLen` bytes from a password and salt using PBKDF2 with
+// HMAC-`h` as the pseudorandom function and `iter` iterations (RFC 8018).
+// KeyLen may be at most (2^32-1)*hash size
+func PBKDF2(h func() hash.Hash, password, salt []byte, iter, keyLen int) ([]byte, error) {
+	if iter < 1 {
+		return nil, ErrIterations
+	}
+	prf := hmac.New(h, password)
+	hLen := prf.Size()
+	if keyLen < 0 || uint64(keyLen) > uint64(hLen)*(uint64(1)<<32-1) {
+		return nil, ErrLength
+	}
+	nBlocks := (keyLen + hLen - 1) / hLen
+
+	ret := make([]byte, 0, nBlocks*hLen)
+	var ctr [4]byte
+	u := make([]byte, 0, hLen)
+	for block := 1; block <= nBlocks; block++ {
+		//U_1 = PRF(P, S || INT(i))
+		prf.Reset()
+		prf.Write(salt)
+		binary.BigEndian.PutUint32(ctr[:], uint32(block))
+		prf.Write(ctr[:])
+		u = prf.Sum(u[:0])
+
+		//T = U_1 ^ U_2 ^ ... ^ U_c
+		n := len(ret)
+		ret = append(ret, u...)
+		t := ret[n:]
+		for i := 1; i < iter; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range u {
+				t[j] ^= u[j]
+			}
+		}
+	}
+	return ret[:keyLen], nil
+}
@@ -0,0 +1,83 @@
+package kdf
+
+import (
+	"crypto/sha1"
+	"hash"
+	"testing"
+
+	"github.com/gnabgib/gnablib-go/encoding/hex"
+	"github.com/gnabgib/gnablib-go/hash/ripemd"
+	"github.com/gnabgib/gnablib-go/hash/whirlpool"
+	"github.com/gnabgib/gnablib-go/test"
+)
+
+var pbkdf2Tests = []struct {
+	password string
+	salt     string
+	iter     int
+	keyLen   int
+	sha1     string
+	r160     string
+	whirl    string
+}{
+	//SHA1 source: https://datatracker.ietf.org/doc/html/rfc6070
+	//RIPEMD-160 cross checked with python hashlib, Whirlpool with openssl 3
+	// (`openssl kdf -kdfopt digest:whirlpool .. PBKDF2`, legacy provider)
+	{"password", "salt", 1, 20,
+		"0C60C80F961F0E71F3A9B524AF6012062FE037A6",
+		"B725258B125E0BACB0E2307E34FEB16A4D0D6AED",
+		"7E25009BF8AFADE8AB33911D331B5B3E987FC7C3"},
+	{"password", "salt", 2, 20,
+		"EA6C014DC72D6F8CCD1ED92ACE1D41F0D8DE8957",
+		"768DCC27B7BFDEF794A1FF9D935090FCF598555E",
+		"110B2E4266F03C334F6085BF421A68D6976A2F76"},
+	{"password", "salt", 4096, 20,
+		"4B007901B765489ABEAD49D926F721D065A429C1",
+		"99A40D3FE4EE95869791D9FAA248645627827621",
+		"4F4C0307915B7E3F948DAAF41EE7805CD2967513"},
+	//Key longer than the digest (for sha1/ripemd160)
+	{"passwordPASSWORDpassword", "saltSALTsaltSALTsaltSALTsaltSALTsalt", 4096, 25,
+		"3D2EEC4FE41C849B80C8D83662C0E44A8B291A964CF2F07038",
+		"503B9A069633B261B2D3E4F21C5D0CAFEB3F5008AEC25ED214",
+		"B704488BCC9371A5FA3A7EB6E7555549A96EAE3D572C0D505E"},
+	{"pass\x00word", "sa\x00lt", 4096, 16,
+		"56FA6AA75548099DCC37D7F03425E0C3",
+		"7B2F446AFB201A536F7C9BF53FD2F14A",
+		"A5A8F2ABE3B0CD5A4084987DE2F6EF48"},
+}
+
+func TestPBKDF2(t *testing.T) {
+	for _, rec := range pbkdf2Tests {
+		pw := []byte(rec.password)
+		salt := []byte(rec.salt)
+		test.StringMatch(t, rec.sha1, pbkdf2Hex(t, sha1.New, pw, salt, rec.iter, rec.keyLen))
+		test.StringMatch(t, rec.r160, pbkdf2Hex(t, ripemd.New160, pw, salt, rec.iter, rec.keyLen))
+		test.StringMatch(t, rec.whirl, pbkdf2Hex(t, whirlpool.New, pw, salt, rec.iter, rec.keyLen))
+	}
+}
+
+func pbkdf2Hex(t *testing.T, h func() hash.Hash, pw, salt []byte, iter, keyLen int) string {
+	key, err := PBKDF2(h, pw, salt, iter, keyLen)
+	if err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+	return hex.FromBytes(key)
+}
+
+func TestPBKDF2_empty(t *testing.T) {
+	found, err := PBKDF2(ripemd.New160, []byte("password"), []byte("salt"), 1, 0)
+	if err != nil || len(found) != 0 {
+		t.Errorf("Expecting empty key, got %x, %v", found, err)
+	}
+}
+
+func TestPBKDF2_invalid(t *testing.T) {
+	if _, err := PBKDF2(ripemd.New160, []byte("password"), []byte("salt"), 1, -1); err != ErrLength {
+		t.Errorf("Expecting ErrLength, got %v", err)
+	}
+	for _, iter := range []int{0, -1} {
+		if _, err := PBKDF2(ripemd.New160, []byte("password"), []byte("salt"), iter, 20); err != ErrIterations {
+			t.Errorf("iter=%d expecting ErrIterations, got %v", iter, err)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+package ripemd
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash"
+)
+
+//https://en.wikipedia.org/wiki/Length_extension_attack
+//
+// RipeMD is a Merkle–Damgård construction: the digest is the full internal state
+// after the padded message.  Anyone with a digest and the length of the message
+// can continue hashing, producing H(m || padding || extra) without knowing m.
+// This is why a MAC of H(secret || message) is broken, and HMAC is required.
+
+// The digest isn't the size of the hash being extended
+var ErrDigestSize = errors.New("digest is the wrong size for this hash")
+
+// The glue padding appended to a message of `msgLen` bytes before it's digested.
+// An extended hash covers m || Padding(len(m)) || extra
+func Padding(msgLen uint64) []byte {
+	return padding(msgLen)
+}
+
+// Rebuild a hash from its published `digest`, and the (assumed) length of the
+// message in bytes.  The returned hash continues as if the original message and
+// glue padding had been written, so writing extra data and calling Sum gives
+// H(m || Padding(msgLen) || extra)
+func extend(hashFn func(*ripeCtx), stateLen int, digest []byte, msgLen uint64) (hash.Hash, error) {
+	if len(digest) != stateLen*u32Size {
+		return nil, ErrDigestSize
+	}
+	c := &ripeCtx{hash: hashFn, stateLen: stateLen}
+	for i := 0; i < stateLen; i++ {
+		c.state[i] = binary.LittleEndian.Uint32(digest[i*u32Size:])
+	}
+	//Length includes the glue padding, which always ends on a block boundary
+	c.len = msgLen + uint64(len(padding(msgLen)))
+	return c, nil
+}
+
+// Rebuild a RipeMD-128 hash from a `digest` of a message of `msgLen` bytes, see Padding
+func Extend128(digest []byte, msgLen uint64) (hash.Hash, error) {
+	return extend(hash128, hashSize128u32, digest, msgLen)
+}
+
+// Rebuild a RipeMD-160 hash from a `digest` of a message of `msgLen` bytes, see Padding
+func Extend160(digest []byte, msgLen uint64) (hash.Hash, error) {
+	return extend(hash160, hashSize160u32, digest, msgLen)
+}
+
+// Rebuild a RipeMD-256 hash from a `digest` of a message of `msgLen` bytes, see Padding
+func Extend256(digest []byte, msgLen uint64) (hash.Hash, error) {
+	return extend(hash256, hashSize256u32, digest, msgLen)
+}
+
+// Rebuild a RipeMD-320 hash from a `digest` of a message of `msgLen` bytes, see Padding
+func Extend320(digest []byte, msgLen uint64) (hash.Hash, error) {
+	return extend(hash320, hashSize320u32, digest, msgLen)
+}
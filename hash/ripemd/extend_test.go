@@ -0,0 +1,139 @@
+package ripemd
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"hash"
+	"testing"
+
+	"github.com/gnabgib/gnablib-go/encoding/hex"
+)
+
+var extendVariants = []struct {
+	name   string
+	new    func() hash.Hash
+	extend func([]byte, uint64) (hash.Hash, error)
+}{
+	{"128", New128, Extend128},
+	{"160", New160, Extend160},
+	{"256", New256, Extend256},
+	{"320", New320, Extend320},
+}
+
+func TestPadding(t *testing.T) {
+	tests := []struct {
+		msgLen uint64
+		padLen int
+	}{
+		{0, 64},
+		{1, 63},
+		{55, 9},
+		//No longer space for the size
+		{56, 72},
+		{63, 65},
+		{64, 64},
+		{119, 9},
+		{120, 72},
+	}
+	for _, rec := range tests {
+		p := Padding(rec.msgLen)
+		if len(p) != rec.padLen {
+			t.Errorf("Length %d expecting %d bytes padding, got %d", rec.msgLen, rec.padLen, len(p))
+		}
+		if p[0] != 0x80 {
+			t.Errorf("Length %d expecting separator, got %x", rec.msgLen, p[0])
+		}
+	}
+	//Size is in bits, little endian
+	p := Padding(0x0102)
+	found := hex.FromBytes(p[len(p)-8:])
+	if found != "1008000000000000" {
+		t.Errorf("Expecting 1008000000000000, got %s", found)
+	}
+}
+
+func TestExtend(t *testing.T) {
+	//Check at lengths either side of the padding overflow
+	for _, v := range extendVariants {
+		for _, n := range []int{0, 1, 3, 55, 56, 63, 64, 65, 119, 120, 200} {
+			msg := bytes.Repeat([]byte{'m'}, n)
+			extra := []byte("&admin=true")
+
+			h := v.new()
+			h.Write(msg)
+			digest := h.Sum(nil)
+
+			e, err := v.extend(digest, uint64(n))
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", v.name, err)
+				continue
+			}
+			e.Write(extra)
+
+			full := v.new()
+			full.Write(msg)
+			full.Write(Padding(uint64(n)))
+			full.Write(extra)
+			if !bytes.Equal(e.Sum(nil), full.Sum(nil)) {
+				t.Errorf("%s: length %d extended digest doesn't match", v.name, n)
+			}
+		}
+	}
+}
+
+func TestExtend_badDigest(t *testing.T) {
+	for _, v := range extendVariants {
+		if _, err := v.extend(make([]byte, 3), 0); err != ErrDigestSize {
+			t.Errorf("%s: expecting ErrDigestSize, got %v", v.name, err)
+		}
+	}
+}
+
+// A naive MAC: H(secret || message)
+func naiveMac(h func() hash.Hash, secret, msg []byte) []byte {
+	d := h()
+	d.Write(secret)
+	d.Write(msg)
+	return d.Sum(nil)
+}
+
+func TestExtend_forgesSecretPrefixMac(t *testing.T) {
+	secret := []byte("sup3r s3cret k3y")
+	msg := []byte("user=guest")
+	extra := []byte(";role=admin")
+
+	for _, v := range extendVariants {
+		tag := naiveMac(v.new, secret, msg)
+
+		//The attacker knows msg and tag, and guesses the secret length
+		found := false
+		for guess := 0; guess < 32; guess++ {
+			glued := uint64(guess + len(msg))
+			e, _ := v.extend(tag, glued)
+			e.Write(extra)
+			forgedTag := e.Sum(nil)
+			forgedMsg := append(append(append([]byte{}, msg...), Padding(glued)...), extra...)
+
+			if bytes.Equal(naiveMac(v.new, secret, forgedMsg), forgedTag) {
+				found = true
+				if guess != len(secret) {
+					t.Errorf("%s: forgery worked with the wrong length %d", v.name, guess)
+				}
+
+				//HMAC doesn't have this weakness, the same trick doesn't produce a valid tag
+				mac := hmac.New(v.new, secret)
+				mac.Write(msg)
+				e, _ = v.extend(mac.Sum(nil), glued)
+				e.Write(extra)
+				mac = hmac.New(v.new, secret)
+				mac.Write(forgedMsg)
+				if hmac.Equal(mac.Sum(nil), e.Sum(nil)) {
+					t.Errorf("%s: HMAC was extended", v.name)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("%s: expecting secret-prefix MAC to be forged", v.name)
+		}
+	}
+}
@@ -46,3 +46,23 @@ func TestDoubleWrite128Sum(t *testing.T) {
 	test.HashTest(t, d, []byte("a"), "86BE7AFA339D0FC7CFC785E72F578D33")
 	test.HashTest(t, d, []byte("bc"), "C14A12199C66E4BA84636B0F69144C77")
 }
+
+// Vectors short enough to be split at every byte boundary
+func conformanceVectors(pairs []ripePair) []test.HashVector {
+	ret := []test.HashVector{}
+	for _, rec := range pairs {
+		if len(rec.in) > 1000 {
+			continue
+		}
+		ret = append(ret, test.HashVector{In: rec.in, Hex: rec.hex})
+	}
+	return ret
+}
+
+func TestRipe128Conformance(t *testing.T) {
+	test.HashConformance(t, New128, conformanceVectors(ripe128pairs))
+}
+
+func TestRipe128MillionA(t *testing.T) {
+	test.HashLongTest(t, New128(), "a", 1000000, "4A7F5723F954EBA1216C9D8F6320431F")
+}
@@ -35,3 +35,11 @@ func TestRipe160(t *testing.T) {
 		test.HashTest(t, d, []byte(rec.in), rec.hex)
 	}
 }
+
+func TestRipe160Conformance(t *testing.T) {
+	test.HashConformance(t, New160, conformanceVectors(ripe160pairs))
+}
+
+func TestRipe160MillionA(t *testing.T) {
+	test.HashLongTest(t, New160(), "a", 1000000, "52783243C1697BDBE16D37F97F68F08325DC1528")
+}
@@ -38,3 +38,11 @@ func TestRipe256(t *testing.T) {
 		test.HashTest(t, d, []byte(rec.in), rec.hex)
 	}
 }
+
+func TestRipe256Conformance(t *testing.T) {
+	test.HashConformance(t, New256, conformanceVectors(ripe256pairs))
+}
+
+func TestRipe256MillionA(t *testing.T) {
+	test.HashLongTest(t, New256(), "a", 1000000, "AC953744E10E31514C150D4D8D7B677342E33399788296E43AE4850CE4F97978")
+}
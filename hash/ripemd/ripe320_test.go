@@ -37,3 +37,11 @@ func TestRipe320(t *testing.T) {
 		test.HashTest(t, d, []byte(rec.in), rec.hex)
 	}
 }
+
+func TestRipe320Conformance(t *testing.T) {
+	test.HashConformance(t, New320, conformanceVectors(ripe320pairs))
+}
+
+func TestRipe320MillionA(t *testing.T) {
+	test.HashLongTest(t, New320(), "a", 1000000, "BDEE37F4371E20646B8B0D862DDA16292AE36F40965E8C8509E63D1DBDDECC503E2B63EB9245BB66")
+}
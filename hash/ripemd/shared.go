@@ -5,8 +5,6 @@ package ripemd
 
 import (
 	"encoding/binary"
-
-	"github.com/gnabgib/gnablib-go/bytes"
 )
 
 //https://en.wikipedia.org/wiki/RIPEMD
@@ -171,32 +169,9 @@ func (c *ripeCtx) Sum(in []byte) []byte {
 	t := *c
 	h := &t
 
-	//Because of the way write works, we must always have at least one
-	// byte free (if there was zero, it would be hashed and there'd be 64)
-	h.block[h.bPos] = 0x80
-	h.bPos++
-	//If we don't have enough space for the size, add zeros and hash
-	if h.bPos > sizeSpace {
-		bytes.Zero(h.block[h.bPos:])
-		h.hash(h)
-	}
-
-	//Zero leaving space for the size
-	bytes.Zero(h.block[h.bPos:sizeSpace])
-	h.bPos = sizeSpace
+	//Padding always completes the final block (so it's hashed by Write)
+	h.Write(padding(h.len))
 
-	//Write the size.. in bits (it's stored in bytes *8 = <<3)
-	h.block[h.bPos] = byte(h.len << 3)
-	h.block[h.bPos+1] = byte(h.len >> 5)
-	h.block[h.bPos+2] = byte(h.len >> 13)
-	h.block[h.bPos+3] = byte(h.len >> 21)
-	h.block[h.bPos+4] = byte(h.len >> 29)
-	h.block[h.bPos+5] = byte(h.len >> 37)
-	h.block[h.bPos+6] = byte(h.len >> 45)
-	h.block[h.bPos+7] = byte(h.len >> 53)
-	(*h).bPos += 8
-
-	h.hash(h)
 	//Append the state (which is the hash) to the input
 	var out = make([]byte, h.stateLen*u32Size)
 	for i := 0; i < h.stateLen; i++ {
@@ -205,6 +180,22 @@ func (c *ripeCtx) Sum(in []byte) []byte {
 	return append(in, out...) //Shake it all about
 }
 
+// MD padding for a message of `msgLen` bytes: a 1 bit, zeros until there's just
+// space for the size in the last block, and then the size in bits (little endian)
+func padding(msgLen uint64) []byte {
+	//There's always at least one byte (the separator)
+	n := blockSizeBytes - int(msgLen%blockSizeBytes)
+	//If we don't have enough space for the size, pad into another block
+	if n <= blockSizeBytes-sizeSpace {
+		n += blockSizeBytes
+	}
+	pad := make([]byte, n)
+	pad[0] = 0x80
+	//Write the size.. in bits (it's stored in bytes *8 = <<3)
+	binary.LittleEndian.PutUint64(pad[n-8:], msgLen<<3)
+	return pad
+}
+
 func (c *ripeCtx) BlockSize() int { return blockSizeBytes }
 
 func (c *ripeCtx) Size() int { return c.stateLen * u32Size }
@@ -0,0 +1,122 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+// Tools that work with any hash.Hash (including the checksum Hash8/Hash16 types)
+package hash
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	goHash "hash"
+	"io"
+
+	"github.com/gnabgib/gnablib-go/encoding/hex"
+)
+
+// The content didn't match the expected digest (use errors.Is to test for this)
+var ErrDigestMismatch = errors.New("digest mismatch")
+
+// Digest mismatch error, includes the expected and actual digests
+type DigestMismatchError struct {
+	Expect string //Hex of the expected digest
+	Found  string //Hex of the computed digest
+}
+
+func (e DigestMismatchError) Error() string {
+	return fmt.Sprintf("Digest mismatch: expected %s, found %s", e.Expect, e.Found)
+}
+
+func (e DigestMismatchError) Unwrap() error { return ErrDigestMismatch }
+
+// Compare a digest to the expected value in constant time, returns a
+// DigestMismatchError if they differ
+func Verify(expect, found []byte) error {
+	if subtle.ConstantTimeCompare(expect, found) == 1 {
+		return nil
+	}
+	return DigestMismatchError{Expect: hex.FromBytes(expect), Found: hex.FromBytes(found)}
+}
+
+type verifyingReader struct {
+	r      io.Reader
+	h      goHash.Hash
+	expect []byte
+	err    error //Sticky error once the end is reached
+}
+
+// A reader that passes data through from `r`, while writing it to `h`.  When `r`
+// is exhausted the digest is compared to `expect`, if it matches io.EOF is returned
+// otherwise a DigestMismatchError.  `h` should be freshly created (or Reset)
+func NewVerifyingReader(r io.Reader, h goHash.Hash, expect []byte) io.Reader {
+	return &verifyingReader{r: r, h: h, expect: expect}
+}
+
+func (v *verifyingReader) Read(p []byte) (n int, err error) {
+	if v.err != nil {
+		return 0, v.err
+	}
+	n, err = v.r.Read(p)
+	//hash.Hash.Write never returns an error
+	v.h.Write(p[:n])
+	if err == io.EOF {
+		err = Verify(v.expect, v.h.Sum(nil))
+		if err == nil {
+			err = io.EOF
+		}
+		v.err = err
+	}
+	return
+}
+
+// A writer that copies writes to an underlying writer (if provided) and a
+// collection of hashes, useful to compute several digests in one pass
+type TeeWriter struct {
+	w      io.Writer
+	hashes []goHash.Hash
+}
+
+// A new TeeWriter that writes to `w` (which may be nil) and every hash
+func NewTeeWriter(w io.Writer, hashes ...goHash.Hash) *TeeWriter {
+	return &TeeWriter{w: w, hashes: hashes}
+}
+
+func (t *TeeWriter) Write(p []byte) (n int, err error) {
+	if t.w != nil {
+		n, err = t.w.Write(p)
+		//Only hash what was written
+		p = p[:n]
+	} else {
+		n = len(p)
+	}
+	for _, h := range t.hashes {
+		h.Write(p)
+	}
+	return
+}
+
+// The current digests of each hash (in construction order)
+func (t *TeeWriter) Sums() [][]byte {
+	ret := make([][]byte, len(t.hashes))
+	for i, h := range t.hashes {
+		ret[i] = h.Sum(nil)
+	}
+	return ret
+}
+
+// Compare each hash's digest to the expected values (in construction order), a nil
+// expectation skips that hash. The first mismatch is returned as a DigestMismatchError
+func (t *TeeWriter) Verify(expect ...[]byte) error {
+	if len(expect) != len(t.hashes) {
+		return fmt.Errorf("Expected %d digests, got %d", len(t.hashes), len(expect))
+	}
+	for i, h := range t.hashes {
+		if expect[i] == nil {
+			continue
+		}
+		if err := Verify(expect[i], h.Sum(nil)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
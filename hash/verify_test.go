@@ -0,0 +1,132 @@
+package hash
+
+import (
+	"bytes"
+	"errors"
+	goHash "hash"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/gnabgib/gnablib-go/checksum/bcc"
+	"github.com/gnabgib/gnablib-go/checksum/fletcher"
+	"github.com/gnabgib/gnablib-go/encoding/hex"
+	"github.com/gnabgib/gnablib-go/hash/ripemd"
+	"github.com/gnabgib/gnablib-go/hash/whirlpool"
+)
+
+var verifyTests = []struct {
+	name string
+	h    func() goHash.Hash
+	in   string
+	hex  string
+}{
+	{"ripemd160", ripemd.New160, "abc", "8EB208F7E05D987A9B044A8E98C6B087F15A0BFC"},
+	{"ripemd320", ripemd.New320, "", "22D65D5661536CDC75C1FDF5C6DE7B41B9F27325EBC61E8557177D705A0EC880151C3A32A00899B8"},
+	{"whirlpool", whirlpool.New, "abc",
+		"4E2448A4C6F486BB16B6562C73B4020BF3043E3A731BCE721AE1B303D97E6D4C7181EEBDB6C57E277D0E34957114CBD6C797FC9D95D8B582D225292076D4EEF5"},
+	{"fletcher16", func() goHash.Hash { return fletcher.New16() }, "abcde", "C8F0"},
+	{"fletcher64", func() goHash.Hash { return fletcher.New64() }, "abcde", "C8C6C527646362C6"},
+	{"bcc", func() goHash.Hash { return bcc.New() }, "Wikipedia", "45"},
+}
+
+func TestVerifyingReader(t *testing.T) {
+	for _, rec := range verifyTests {
+		expect := hex.ToBytesFast(rec.hex)
+		//Small reads make sure the hash is built over several writes
+		r := NewVerifyingReader(iotest.OneByteReader(strings.NewReader(rec.in)), rec.h(), expect)
+		found, err := io.ReadAll(r)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", rec.name, err)
+		}
+		if string(found) != rec.in {
+			t.Errorf("%s: expecting passthrough %q, got %q", rec.name, rec.in, found)
+		}
+	}
+}
+
+func TestVerifyingReader_mismatch(t *testing.T) {
+	for _, rec := range verifyTests {
+		expect := hex.ToBytesFast(rec.hex)
+		r := NewVerifyingReader(strings.NewReader(rec.in+"!"), rec.h(), expect)
+		_, err := io.ReadAll(r)
+		if !errors.Is(err, ErrDigestMismatch) {
+			t.Errorf("%s: expecting ErrDigestMismatch, got %v", rec.name, err)
+			continue
+		}
+		var dm DigestMismatchError
+		if !errors.As(err, &dm) {
+			t.Errorf("%s: expecting DigestMismatchError, got %T", rec.name, err)
+			continue
+		}
+		if dm.Expect != rec.hex {
+			t.Errorf("%s: expecting %s, got %s", rec.name, rec.hex, dm.Expect)
+		}
+		h := rec.h()
+		h.Write([]byte(rec.in + "!"))
+		if dm.Found != hex.FromBytes(h.Sum(nil)) {
+			t.Errorf("%s: expecting found %s, got %s", rec.name, hex.FromBytes(h.Sum(nil)), dm.Found)
+		}
+		//The error is sticky
+		if _, err2 := r.Read(make([]byte, 1)); err2 != err {
+			t.Errorf("%s: expecting repeat error, got %v", rec.name, err2)
+		}
+	}
+}
+
+func TestVerifyingReader_readError(t *testing.T) {
+	bad := errors.New("bad read")
+	r := NewVerifyingReader(iotest.ErrReader(bad), ripemd.New160(), nil)
+	_, err := io.ReadAll(r)
+	if err != bad {
+		t.Errorf("Expecting read error to pass through, got %v", err)
+	}
+}
+
+func TestTeeWriter(t *testing.T) {
+	hashes := []goHash.Hash{}
+	expect := [][]byte{}
+	for _, rec := range verifyTests {
+		if rec.in != "abc" {
+			continue
+		}
+		hashes = append(hashes, rec.h())
+		expect = append(expect, hex.ToBytesFast(rec.hex))
+	}
+	var buf bytes.Buffer
+	w := NewTeeWriter(&buf, hashes...)
+	io.Copy(w, iotest.OneByteReader(strings.NewReader("abc")))
+	if buf.String() != "abc" {
+		t.Errorf("Expecting passthrough, got %q", buf.String())
+	}
+	for i, sum := range w.Sums() {
+		if !bytes.Equal(sum, expect[i]) {
+			t.Errorf("Sum %d expecting %x, got %x", i, expect[i], sum)
+		}
+	}
+	if err := w.Verify(expect...); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+	//Skip the first, break the second
+	expect[0] = nil
+	expect[1] = []byte{1, 2, 3}
+	if err := w.Verify(expect...); !errors.Is(err, ErrDigestMismatch) {
+		t.Errorf("Expecting ErrDigestMismatch, got %v", err)
+	}
+	if err := w.Verify(); err == nil {
+		t.Errorf("Expecting count error")
+	}
+}
+
+func TestTeeWriter_noWriter(t *testing.T) {
+	h := ripemd.New160()
+	w := NewTeeWriter(nil, h)
+	n, err := w.Write([]byte("abc"))
+	if n != 3 || err != nil {
+		t.Errorf("Expecting 3,nil got %d,%v", n, err)
+	}
+	if err := w.Verify(hex.ToBytesFast("8EB208F7E05D987A9B044A8E98C6B087F15A0BFC")); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
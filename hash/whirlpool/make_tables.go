@@ -20,6 +20,7 @@ import (
 	"os"
 
 	"github.com/gnabgib/gnablib-go/encoding/hex"
+	"github.com/gnabgib/gnablib-go/math/gf"
 )
 
 const (
@@ -56,6 +57,9 @@ package whirlpool
 
 `)
 
+// GF(2^8) over gfPoly
+var field = gf.MustField(gfPoly)
+
 var (
 	rc = [rounds]uint64{}  // roundConstants
 	ct = [8 * 256]uint64{} // circulantTable
@@ -136,25 +140,12 @@ func writeU64LE(w io.Writer, v uint64) {
 
 func buildCirculantTable() {
 	for x := 0; x < 256; x++ {
-		v1 := uint64(sBox[x])
-		v2 := v1 << 1
-		if v2 >= 0x100 {
-			v2 ^= gfPoly
-		}
-		v4 := v2 << 1
-		if v4 >= 0x100 {
-			v4 ^= gfPoly
-		}
-		v5 := v4 ^ v1
-		v8 := v4 << 1
-		if v8 >= 0x100 {
-			v8 ^= gfPoly
-		}
-		v9 := v8 ^ v1
-		ct[x] = (v1 << 56) | (v1 << 48) |
-			(v4 << 40) | (v1 << 32) |
-			(v8 << 24) | (v5 << 16) |
-			(v2 << 8) | v9
+		v1 := sBox[x]
+		mul := func(c byte) uint64 { return uint64(field.Mul(v1, c)) }
+		ct[x] = (mul(1) << 56) | (mul(1) << 48) |
+			(mul(4) << 40) | (mul(1) << 32) |
+			(mul(8) << 24) | (mul(5) << 16) |
+			(mul(2) << 8) | mul(9)
 		for t := 1; t < 8; t++ {
 			ct[(t<<8)|x] = bits.RotateLeft64(ct[((t-1)<<8)|x], -8)
 		}
@@ -57,3 +57,20 @@ func TestWhirlpool(t *testing.T) {
 		test.HashTest(t, d, []byte(rec.in), rec.hex)
 	}
 }
+
+func TestWhirlpoolConformance(t *testing.T) {
+	vectors := []test.HashVector{}
+	for _, rec := range whirlpoolTests {
+		//Splitting the million a at every boundary would take a while
+		if len(rec.in) > 1000 {
+			continue
+		}
+		vectors = append(vectors, test.HashVector{In: rec.in, Hex: rec.hex})
+	}
+	test.HashConformance(t, New, vectors)
+}
+
+func TestWhirlpoolMillionA(t *testing.T) {
+	test.HashLongTest(t, New(), "a", 1000000,
+		"0C99005BEB57EFF50A7CF005560DDF5D29057FD86B20BFD62DECA0F1CCEA4AF51FC15490EDDC47AF32BB2B66C34FF9AD8C6008AD677F77126953B226E4ED8B01")
+}
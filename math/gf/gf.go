@@ -0,0 +1,172 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+// Galois field arithmetic: GF(2^8) and GF(2^16) with log/antilog tables, and
+// carry-less GF(2^128) multiplication (GHASH)
+package gf
+
+//https://en.wikipedia.org/wiki/Finite_field_arithmetic
+
+import "errors"
+
+// The polynomial isn't the right degree, or the generator doesn't produce every
+// non-zero element
+var ErrPoly = errors.New("polynomial must be primitive (or the generator must generate the field)")
+
+// Division by zero, or the inverse of zero
+var ErrZero = errors.New("gf: division by zero")
+
+// The matrix isn't square
+var ErrShape = errors.New("matrix must be square")
+
+// The matrix has no inverse
+var ErrSingular = errors.New("matrix is singular")
+
+// GF(2^8) over a polynomial with log/antilog tables
+type Field struct {
+	poly int
+	gen  byte
+	exp  [510]byte //exp[i]=gen^i, doubled so the sum of two logs needs no mod
+	log  [256]byte //log[0] is undefined
+}
+
+// GF(2^8) with x^8+x^4+x^3+x^2+1 (0x11d) as used by QR codes, Reed-Solomon and
+// Whirlpool
+var GF256 = MustField(0x11d)
+
+// GF(2^8) with x^8+x^4+x^3+x+1 (0x11b) as used by AES, it isn't primitive so 3
+// is the generator
+var AES = MustFieldGenerator(0x11b, 3)
+
+// Carry-less multiply of a and b, reduced by poly of degree `deg`
+func clmul(a, b, poly, deg int) int {
+	r := 0
+	for b != 0 {
+		if b&1 != 0 {
+			r ^= a
+		}
+		b >>= 1
+		a <<= 1
+		if a>>deg != 0 {
+			a ^= poly
+		}
+	}
+	return r
+}
+
+// GF(2^8) over a primitive polynomial of degree 8 (the generator is x, 2)
+func NewField(poly int) (*Field, error) {
+	return NewFieldGenerator(poly, 2)
+}
+
+// GF(2^8) over a polynomial of degree 8, where powers of `gen` produce all 255
+// non-zero elements
+func NewFieldGenerator(poly int, gen byte) (*Field, error) {
+	if poly>>8 != 1 || gen < 2 {
+		return nil, ErrPoly
+	}
+	f := &Field{poly: poly, gen: gen}
+	var seen [256]bool
+	seen[0] = true
+	x := 1
+	for i := 0; i < 255; i++ {
+		if seen[x] {
+			//Cycled early, or hit 0 (the polynomial is reducible)
+			return nil, ErrPoly
+		}
+		seen[x] = true
+		f.exp[i] = byte(x)
+		f.log[x] = byte(i)
+		x = clmul(x, int(gen), poly, 8)
+	}
+	copy(f.exp[255:], f.exp[:255])
+	return f, nil
+}
+
+// Like NewField but panics on an invalid polynomial (for package level vars)
+func MustField(poly int) *Field {
+	return MustFieldGenerator(poly, 2)
+}
+
+// Like NewFieldGenerator but panics on an invalid polynomial or generator
+func MustFieldGenerator(poly int, gen byte) *Field {
+	f, err := NewFieldGenerator(poly, gen)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// The field polynomial
+func (f *Field) Poly() int { return f.poly }
+
+// The generator (primitive element)
+func (f *Field) Generator() byte { return f.gen }
+
+// The antilog table, gen^i for i 0-254
+func (f *Field) ExpTable() [255]byte {
+	var ret [255]byte
+	copy(ret[:], f.exp[:])
+	return ret
+}
+
+// The log table, the entry for 0 is undefined (0)
+func (f *Field) LogTable() [256]byte { return f.log }
+
+// gen^e, for any e (negative is the inverse)
+func (f *Field) Exp(e int) byte {
+	e %= 255
+	if e < 0 {
+		e += 255
+	}
+	return f.exp[e]
+}
+
+// The log (base gen) of x, -1 for 0
+func (f *Field) Log(x byte) int {
+	if x == 0 {
+		return -1
+	}
+	return int(f.log[x])
+}
+
+// a+b (and a-b)
+func Add(a, b byte) byte { return a ^ b }
+
+// a*b
+func (f *Field) Mul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return f.exp[int(f.log[a])+int(f.log[b])]
+}
+
+// a/b, panics with ErrZero if b is 0
+func (f *Field) Div(a, b byte) byte {
+	if b == 0 {
+		panic(ErrZero)
+	}
+	if a == 0 {
+		return 0
+	}
+	return f.exp[int(f.log[a])+255-int(f.log[b])]
+}
+
+// 1/a, panics with ErrZero if a is 0
+func (f *Field) Inv(a byte) byte {
+	return f.Div(1, a)
+}
+
+// a^e, negative e is a power of the inverse (panics with ErrZero if a is 0)
+func (f *Field) Pow(a byte, e int) byte {
+	if a == 0 {
+		switch {
+		case e == 0:
+			return 1
+		case e < 0:
+			panic(ErrZero)
+		}
+		return 0
+	}
+	return f.Exp(int(f.log[a]) * (e % 255))
+}
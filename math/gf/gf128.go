@@ -0,0 +1,67 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+package gf
+
+//https://nvlpubs.nist.gov/nistpubs/Legacy/SP/nistspecialpublication800-38d.pdf
+
+import "encoding/binary"
+
+// The carry-less product of a and b (polynomials over GF(2)), as a 128bit value
+func ClMul64(a, b uint64) (hi, lo uint64) {
+	for i := 0; i < 64; i++ {
+		if b>>i&1 != 0 {
+			lo ^= a << i
+			if i > 0 {
+				hi ^= a >> (64 - i)
+			}
+		}
+	}
+	return
+}
+
+// x*y in GF(2^128) with x^128+x^7+x^2+x+1, in the GCM bit order (the most
+// significant bit of byte 0 is the coefficient of x^0). Not constant time
+func Mul128(x, y [16]byte) [16]byte {
+	xh, xl := binary.BigEndian.Uint64(x[:8]), binary.BigEndian.Uint64(x[8:])
+	vh, vl := binary.BigEndian.Uint64(y[:8]), binary.BigEndian.Uint64(y[8:])
+	var zh, zl uint64
+	for i := 0; i < 128; i++ {
+		var bit uint64
+		if i < 64 {
+			bit = xh >> (63 - i) & 1
+		} else {
+			bit = xl >> (127 - i) & 1
+		}
+		if bit != 0 {
+			zh ^= vh
+			zl ^= vl
+		}
+		//v*=x, reversed so it's a right shift, reduce by R=11100001||0^120
+		carry := vl & 1
+		vl = vl>>1 | vh<<63
+		vh >>= 1
+		if carry != 0 {
+			vh ^= 0xe1 << 56
+		}
+	}
+	var ret [16]byte
+	binary.BigEndian.PutUint64(ret[:8], zh)
+	binary.BigEndian.PutUint64(ret[8:], zl)
+	return ret
+}
+
+// GHASH of data (zero padded to 16 byte blocks) under hash key h
+func GHASH(h [16]byte, data []byte) [16]byte {
+	var y, blk [16]byte
+	for len(data) > 0 {
+		blk = [16]byte{}
+		n := copy(blk[:], data)
+		data = data[n:]
+		for i := range y {
+			y[i] ^= blk[i]
+		}
+		y = Mul128(y, h)
+	}
+	return y
+}
@@ -0,0 +1,81 @@
+package gf
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"encoding/hex"
+	"math/rand"
+	"testing"
+)
+
+func TestClMul64(t *testing.T) {
+	tests := []struct {
+		a, b, hi, lo uint64
+	}{
+		{3, 3, 0, 5},
+		{0x8000000000000000, 2, 1, 0},
+		{0xffffffffffffffff, 0xffffffffffffffff, 0x5555555555555555, 0x5555555555555555},
+	}
+	for _, rec := range tests {
+		hi, lo := ClMul64(rec.a, rec.b)
+		if hi != rec.hi || lo != rec.lo {
+			t.Errorf("ClMul64(%x,%x) expecting %x:%x, got %x:%x", rec.a, rec.b, rec.hi, rec.lo, hi, lo)
+		}
+	}
+}
+
+func block(s string) [16]byte {
+	var ret [16]byte
+	hex.Decode(ret[:], []byte(s))
+	return ret
+}
+
+func TestGHASH(t *testing.T) {
+	//SP 800-38D test case 2 (GCM spec)
+	h := block("66e94bd4ef8a2c3b884cfa59ca342b2e")
+	data, _ := hex.DecodeString("0388dace60b6a392f328c2b971b2fe78" + "00000000000000000000000000000080")
+	found := GHASH(h, data)
+	if found != block("f38cbb1ad69223dcc3457ae5b6b0f885") {
+		t.Errorf("GHASH expecting f38cbb1ad69223dcc3457ae5b6b0f885, got %x", found)
+	}
+	//1 is x^0, the multiplicative identity
+	one := block("80000000000000000000000000000000")
+	if Mul128(h, one) != h {
+		t.Errorf("Mul128(h,1) expecting h")
+	}
+}
+
+func TestGHASHMatchesGCM(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 20; trial++ {
+		key := make([]byte, 16)
+		nonce := make([]byte, 12)
+		aad := make([]byte, rng.Intn(40))
+		pt := make([]byte, rng.Intn(70))
+		rng.Read(key)
+		rng.Read(nonce)
+		rng.Read(aad)
+		rng.Read(pt)
+		b, _ := aes.NewCipher(key)
+		g, _ := cipher.NewGCM(b)
+		sealed := g.Seal(nil, nonce, pt, aad)
+		ct, tag := sealed[:len(pt)], sealed[len(pt):]
+
+		var h, ek [16]byte
+		b.Encrypt(h[:], h[:])
+		j0 := append(append([]byte{}, nonce...), 0, 0, 0, 1)
+		b.Encrypt(ek[:], j0)
+		pad := func(p []byte) []byte { return append(p, make([]byte, (16-len(p)%16)%16)...) }
+		in := append(pad(append([]byte{}, aad...)), pad(append([]byte{}, ct...))...)
+		in = binary.BigEndian.AppendUint64(in, uint64(len(aad))*8)
+		in = binary.BigEndian.AppendUint64(in, uint64(len(ct))*8)
+		s := GHASH(h, in)
+		for i := range s {
+			s[i] ^= ek[i]
+		}
+		if hex.EncodeToString(s[:]) != hex.EncodeToString(tag) {
+			t.Errorf("GHASH tag expecting %x, got %x", tag, s)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+package gf
+
+import "sync"
+
+// GF(2^16) over a primitive polynomial with log/antilog tables (~384KiB)
+type Field16 struct {
+	poly int
+	exp  []uint16 //exp[i]=x^i, doubled so the sum of two logs needs no mod
+	log  []uint16 //log[0] is undefined
+}
+
+const order16 = 1<<16 - 1
+
+var (
+	gf65536     *Field16
+	gf65536Once sync.Once
+)
+
+// GF(2^16) with x^16+x^12+x^3+x+1 (0x1100b) as used by PAR2, the tables are
+// built on first use (rather than at init)
+func GF65536() *Field16 {
+	gf65536Once.Do(func() { gf65536 = MustField16(0x1100b) })
+	return gf65536
+}
+
+// GF(2^16) over a primitive polynomial of degree 16 (the generator is x, 2)
+func NewField16(poly int) (*Field16, error) {
+	if poly>>16 != 1 {
+		return nil, ErrPoly
+	}
+	f := &Field16{poly: poly, exp: make([]uint16, 2*order16), log: make([]uint16, order16+1)}
+	seen := make([]bool, order16+1)
+	seen[0] = true
+	x := 1
+	for i := 0; i < order16; i++ {
+		if seen[x] {
+			//Cycled early, or hit 0 (the polynomial is reducible)
+			return nil, ErrPoly
+		}
+		seen[x] = true
+		f.exp[i] = uint16(x)
+		f.log[x] = uint16(i)
+		x <<= 1
+		if x>>16 != 0 {
+			x ^= poly
+		}
+	}
+	copy(f.exp[order16:], f.exp[:order16])
+	return f, nil
+}
+
+// Like NewField16 but panics on an invalid polynomial (for package level vars)
+func MustField16(poly int) *Field16 {
+	f, err := NewField16(poly)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// The field polynomial
+func (f *Field16) Poly() int { return f.poly }
+
+// x^e, for any e (negative is the inverse)
+func (f *Field16) Exp(e int) uint16 {
+	e %= order16
+	if e < 0 {
+		e += order16
+	}
+	return f.exp[e]
+}
+
+// The log (base x) of a, -1 for 0
+func (f *Field16) Log(a uint16) int {
+	if a == 0 {
+		return -1
+	}
+	return int(f.log[a])
+}
+
+// a*b
+func (f *Field16) Mul(a, b uint16) uint16 {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return f.exp[int(f.log[a])+int(f.log[b])]
+}
+
+// a/b, panics with ErrZero if b is 0
+func (f *Field16) Div(a, b uint16) uint16 {
+	if b == 0 {
+		panic(ErrZero)
+	}
+	if a == 0 {
+		return 0
+	}
+	return f.exp[int(f.log[a])+order16-int(f.log[b])]
+}
+
+// 1/a, panics with ErrZero if a is 0
+func (f *Field16) Inv(a uint16) uint16 {
+	return f.Div(1, a)
+}
+
+// a^e, negative e is a power of the inverse (panics with ErrZero if a is 0)
+func (f *Field16) Pow(a uint16, e int) uint16 {
+	if a == 0 {
+		switch {
+		case e == 0:
+			return 1
+		case e < 0:
+			panic(ErrZero)
+		}
+		return 0
+	}
+	return f.Exp(int(f.log[a]) * (e % order16))
+}
@@ -0,0 +1,168 @@
+package gf
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestField(t *testing.T) {
+	tests := []struct {
+		f       *Field
+		a, b    byte
+		product byte
+	}{
+		//FIPS-197 4.2
+		{AES, 0x57, 0x83, 0xc1},
+		{AES, 0x57, 0x13, 0xfe},
+		{GF256, 0x02, 0x80, 0x1d},
+		{GF256, 0x00, 0x80, 0x00},
+		{GF256, 0x01, 0x9f, 0x9f},
+	}
+	for _, rec := range tests {
+		if found := rec.f.Mul(rec.a, rec.b); found != rec.product {
+			t.Errorf("%x Mul(%02x,%02x) expecting %02x, got %02x", rec.f.Poly(), rec.a, rec.b, rec.product, found)
+		}
+		if rec.b != 0 && rec.product != 0 {
+			if found := rec.f.Div(rec.product, rec.b); found != rec.a {
+				t.Errorf("%x Div(%02x,%02x) expecting %02x, got %02x", rec.f.Poly(), rec.product, rec.b, rec.a, found)
+			}
+		}
+	}
+	//FIPS-197 5.1.1
+	if found := AES.Inv(0x53); found != 0xca {
+		t.Errorf("AES Inv(53) expecting ca, got %02x", found)
+	}
+	for _, f := range []*Field{GF256, AES, MustField(0x187)} {
+		for a := 1; a < 256; a++ {
+			x := byte(a)
+			if f.Mul(x, f.Inv(x)) != 1 {
+				t.Errorf("%x %02x*Inv(%02x) expecting 1", f.Poly(), x, x)
+			}
+			if f.Exp(f.Log(x)) != x {
+				t.Errorf("%x Exp(Log(%02x)) expecting %02x", f.Poly(), x, x)
+			}
+			if f.Pow(x, 3) != f.Mul(x, f.Mul(x, x)) || f.Pow(x, -1) != f.Inv(x) || f.Pow(x, 255) != 1 {
+				t.Errorf("%x Pow(%02x) mismatch", f.Poly(), x)
+			}
+		}
+	}
+	if GF256.Pow(0, 0) != 1 || GF256.Pow(0, 5) != 0 || GF256.Log(0) != -1 || GF256.Exp(-1) != GF256.Inv(2) {
+		t.Errorf("Pow/Log/Exp edge cases mismatch")
+	}
+	if exp := GF256.ExpTable(); exp[8] != 0x1d || exp[0] != 1 {
+		t.Errorf("ExpTable expecting 01..1d, got %x %x", exp[0], exp[8])
+	}
+	if log := GF256.LogTable(); log[0x1d] != 8 {
+		t.Errorf("LogTable[1d] expecting 8, got %d", log[0x1d])
+	}
+}
+
+func TestNewField(t *testing.T) {
+	tests := []struct {
+		poly int
+		gen  byte
+		ok   bool
+	}{
+		{0x11d, 2, true},
+		{0x11b, 2, false},
+		{0x11b, 3, true},
+		{0x1d, 2, false},
+		{0x21d, 2, false},
+		{0x11d, 1, false},
+		//Reducible
+		{0x100, 2, false},
+	}
+	for _, rec := range tests {
+		_, err := NewFieldGenerator(rec.poly, rec.gen)
+		if (err == nil) != rec.ok || (err != nil && err != ErrPoly) {
+			t.Errorf("NewFieldGenerator(%x,%d) expecting ok=%v, got %v", rec.poly, rec.gen, rec.ok, err)
+		}
+	}
+}
+
+func TestDivZero(t *testing.T) {
+	defer func() {
+		if r := recover(); r != ErrZero {
+			t.Errorf("Inv(0) expecting panic %v, got %v", ErrZero, r)
+		}
+	}()
+	GF256.Inv(0)
+}
+
+func TestPoly(t *testing.T) {
+	f := GF256
+	//(x+1)(x+2)=x^2+3x+2
+	p := f.PolyMul([]byte{1, 1}, []byte{2, 1})
+	if fmt.Sprint(p) != "[2 3 1]" {
+		t.Errorf("PolyMul expecting [2 3 1], got %v", p)
+	}
+	if f.PolyEval(p, 1) != 0 || f.PolyEval(p, 2) != 0 || f.PolyEval(p, 3) != 2 {
+		t.Errorf("PolyEval roots mismatch")
+	}
+	a := []byte{7, 0, 0x55, 0x13, 0xff, 9}
+	b := []byte{3, 0x80, 0x21}
+	q, r := f.PolyDivMod(a, b)
+	back := f.PolyMul(q, b)
+	for i := range r {
+		back[i] ^= r[i]
+	}
+	if !bytes.Equal(back, a) || len(r) != 2 {
+		t.Errorf("PolyDivMod expecting q*b+r=a, got q=%v r=%v", q, r)
+	}
+	q, r = f.PolyDivMod([]byte{5}, b)
+	if q != nil || fmt.Sprint(r) != "[5]" {
+		t.Errorf("PolyDivMod low degree expecting nil [5], got %v %v", q, r)
+	}
+}
+
+func TestInvert(t *testing.T) {
+	m := [][]byte{{1, 2, 3}, {4, 5, 6}, {7, 8, 10}}
+	inv, err := GF256.Invert(m)
+	if err != nil {
+		t.Fatalf("Invert unexpected error %v", err)
+	}
+	id := GF256.MatMul(m, inv)
+	for i := range id {
+		for j := range id[i] {
+			expect := byte(0)
+			if i == j {
+				expect = 1
+			}
+			if id[i][j] != expect {
+				t.Errorf("m×Invert(m) expecting identity, got %v", id)
+				return
+			}
+		}
+	}
+	if _, err := GF256.Invert([][]byte{{1, 2}, {2, 4}}); err != ErrSingular {
+		t.Errorf("Invert expecting %v, got %v", ErrSingular, err)
+	}
+	if _, err := GF256.Invert([][]byte{{1, 2}}); err != ErrShape {
+		t.Errorf("Invert expecting %v, got %v", ErrShape, err)
+	}
+}
+
+func TestField16(t *testing.T) {
+	if gf65536 != nil {
+		t.Errorf("GF65536 built before first use")
+	}
+	f := GF65536()
+	if GF65536() != f {
+		t.Errorf("GF65536 expecting the same field each call")
+	}
+	if f.Exp(16) != 0x100b {
+		t.Errorf("Exp(16) expecting 100b, got %x", f.Exp(16))
+	}
+	for _, a := range []uint16{1, 2, 0x1234, 0x8000, 0xffff} {
+		if f.Mul(a, f.Inv(a)) != 1 || f.Exp(f.Log(a)) != a || f.Pow(a, 65535) != 1 {
+			t.Errorf("GF(2^16) %x identities mismatch", a)
+		}
+	}
+	if f.Mul(0x8000, 2) != 0x100b || f.Div(0x100b, 2) != 0x8000 {
+		t.Errorf("GF(2^16) reduction mismatch")
+	}
+	if _, err := NewField16(0x1100a); err != ErrPoly {
+		t.Errorf("NewField16 expecting %v, got %v", ErrPoly, err)
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+package gf
+
+// Polynomials are coefficients in ascending powers, p[i] is the coefficient of x^i
+
+// Evaluate p at x
+func (f *Field) PolyEval(p []byte, x byte) byte {
+	var y byte
+	for i := len(p) - 1; i >= 0; i-- {
+		y = f.Mul(y, x) ^ p[i]
+	}
+	return y
+}
+
+// a*b
+func (f *Field) PolyMul(a, b []byte) []byte {
+	if len(a) == 0 || len(b) == 0 {
+		return nil
+	}
+	ret := make([]byte, len(a)+len(b)-1)
+	for i, x := range a {
+		if x == 0 {
+			continue
+		}
+		for j, y := range b {
+			ret[i+j] ^= f.Mul(x, y)
+		}
+	}
+	return ret
+}
+
+// Quotient and remainder of a/b, panics with ErrZero if b is zero
+func (f *Field) PolyDivMod(a, b []byte) (q, r []byte) {
+	db := len(b) - 1
+	for db >= 0 && b[db] == 0 {
+		db--
+	}
+	if db < 0 {
+		panic(ErrZero)
+	}
+	r = append([]byte{}, a...)
+	if len(a) <= db {
+		return nil, r
+	}
+	q = make([]byte, len(a)-db)
+	lead := b[db]
+	for i := len(a) - 1; i >= db; i-- {
+		c := r[i]
+		if c == 0 {
+			continue
+		}
+		c = f.Div(c, lead)
+		q[i-db] = c
+		for j := 0; j <= db; j++ {
+			r[i-db+j] ^= f.Mul(c, b[j])
+		}
+	}
+	return q, r[:db]
+}
+
+// The matrix product a×b, the columns of a must match the rows of b
+func (f *Field) MatMul(a, b [][]byte) [][]byte {
+	ret := make([][]byte, len(a))
+	for i, row := range a {
+		ret[i] = make([]byte, len(b[0]))
+		for t, c := range row {
+			if c == 0 {
+				continue
+			}
+			for j, v := range b[t] {
+				ret[i][j] ^= f.Mul(c, v)
+			}
+		}
+	}
+	return ret
+}
+
+// The inverse of a square matrix (m is unchanged), ErrShape or ErrSingular
+func (f *Field) Invert(m [][]byte) ([][]byte, error) {
+	n := len(m)
+	//Gauss-Jordan on [m|I]
+	a := make([][]byte, n)
+	for i := range a {
+		if len(m[i]) != n {
+			return nil, ErrShape
+		}
+		a[i] = make([]byte, 2*n)
+		copy(a[i], m[i])
+		a[i][n+i] = 1
+	}
+	for c := 0; c < n; c++ {
+		p := c
+		for p < n && a[p][c] == 0 {
+			p++
+		}
+		if p == n {
+			return nil, ErrSingular
+		}
+		a[c], a[p] = a[p], a[c]
+		if s := a[c][c]; s != 1 {
+			s = f.Inv(s)
+			for j := range a[c] {
+				a[c][j] = f.Mul(a[c][j], s)
+			}
+		}
+		for r := 0; r < n; r++ {
+			if r == c || a[r][c] == 0 {
+				continue
+			}
+			s := a[r][c]
+			for j := range a[r] {
+				a[r][j] ^= f.Mul(s, a[c][j])
+			}
+		}
+	}
+	for i := range a {
+		a[i] = a[i][n:]
+	}
+	return a, nil
+}
@@ -0,0 +1,95 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+package modbus
+
+import (
+	"github.com/gnabgib/gnablib-go/checksum/crc"
+	"github.com/gnabgib/gnablib-go/checksum/lrc"
+	"github.com/gnabgib/gnablib-go/encoding/hex"
+)
+
+// Encode an RTU frame: address, function, data then CRC-16/MODBUS (low byte
+// first)
+func EncodeRTU(a ADU) ([]byte, error) {
+	if err := a.check(); err != nil {
+		return nil, err
+	}
+	ret := make([]byte, 0, len(a.Data)+4)
+	ret = append(ret, a.Address, byte(a.Function))
+	ret = append(ret, a.Data...)
+	c := uint16(crc.CRC16Modbus.Checksum(ret))
+	return append(ret, byte(c), byte(c>>8)), nil
+}
+
+// Decode an RTU frame, returns a FrameError if it's too short or long, or a
+// ChecksumError if the CRC doesn't match. The data aliases `frame`
+func DecodeRTU(frame []byte) (ADU, error) {
+	if len(frame) < 4 {
+		return ADU{}, FrameError{"RTU frame shorter than 4 bytes"}
+	}
+	if len(frame) > MaxPDU+3 {
+		return ADU{}, FrameError{"RTU frame longer than 256 bytes"}
+	}
+	n := len(frame) - 2
+	found := uint16(frame[n]) | uint16(frame[n+1])<<8
+	if expect := uint16(crc.CRC16Modbus.Checksum(frame[:n])); expect != found {
+		return ADU{}, ChecksumError{"CRC", expect, found}
+	}
+	return ADU{Address: frame[0], PDU: PDU{Function: FunctionCode(frame[1]), Data: frame[2:n]}}, nil
+}
+
+func lrcOf(p []byte) byte {
+	d := lrc.New()
+	d.Write(p)
+	return d.Sum8()
+}
+
+// Encode an ASCII frame: ':', the hex of address, function, data and LRC, then
+// CR LF
+func EncodeASCII(a ADU) ([]byte, error) {
+	if err := a.check(); err != nil {
+		return nil, err
+	}
+	raw := make([]byte, 0, len(a.Data)+3)
+	raw = append(raw, a.Address, byte(a.Function))
+	raw = append(raw, a.Data...)
+	raw = append(raw, lrcOf(raw))
+	return []byte(":" + hex.FromBytes(raw) + "\r\n"), nil
+}
+
+// Decode an ASCII frame (hex is case insensitive), returns a FrameError if
+// the start/end are missing or the length is wrong, an InvalidHexAt error
+// (with the position in the frame) for a bad character, or a ChecksumError if
+// the LRC doesn't match
+func DecodeASCII(frame []byte) (ADU, error) {
+	n := len(frame)
+	if n < 1 || frame[0] != ':' {
+		return ADU{}, FrameError{"ASCII frame doesn't start with ':'"}
+	}
+	if n < 3 || frame[n-2] != '\r' || frame[n-1] != '\n' {
+		return ADU{}, FrameError{"ASCII frame doesn't end with CR LF"}
+	}
+	body := frame[1 : n-2]
+	for i, b := range body {
+		if !(b >= '0' && b <= '9' || b >= 'A' && b <= 'F' || b >= 'a' && b <= 'f') {
+			return ADU{}, hex.InvalidHexAt(b, i+1)
+		}
+	}
+	if len(body)&1 != 0 {
+		return ADU{}, FrameError{"ASCII frame has an odd number of hex characters"}
+	}
+	if len(body) < 6 {
+		return ADU{}, FrameError{"ASCII frame shorter than 3 bytes"}
+	}
+	if len(body) > 2*(MaxPDU+2) {
+		return ADU{}, FrameError{"ASCII frame longer than 255 bytes"}
+	}
+	//Always succeeds, the characters and length were checked
+	raw, _ := hex.ToBytes(string(body))
+	last := len(raw) - 1
+	if expect := lrcOf(raw[:last]); expect != raw[last] {
+		return ADU{}, ChecksumError{"LRC", uint16(expect), uint16(raw[last])}
+	}
+	return ADU{Address: raw[0], PDU: PDU{Function: FunctionCode(raw[1]), Data: raw[2:last]}}, nil
+}
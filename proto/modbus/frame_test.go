@@ -0,0 +1,120 @@
+package modbus
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/gnabgib/gnablib-go/encoding/hex"
+)
+
+var frameTests = []struct {
+	adu   ADU
+	rtu   string
+	ascii string
+}{
+	//Simply Modbus examples
+	{ADU{1, PDU{ReadHoldingRegisters, []byte{0x00, 0x00, 0x00, 0x0A}}}, "01030000000AC5CD", ":01030000000AF2\r\n"},
+	{ADU{0x11, PDU{ReadHoldingRegisters, []byte{0x00, 0x6B, 0x00, 0x03}}}, "1103006B00037687", ":1103006B00037E\r\n"},
+	{ADU{0x11, PDU{WriteMultipleCoils, []byte{0x00, 0x13, 0x00, 0x0A, 0x02, 0xCD, 0x01}}}, "110F0013000A02CD01BF0B", ":110F0013000A02CD01F3\r\n"},
+	//No published vector, regression only
+	{ADU{1, PDU{WriteSingleCoil, []byte{0x00, 0x02, 0xFF, 0x00}}}, "01050002FF002DFA", ":01050002FF00F9\r\n"},
+	{ADU{1, PDU{WriteMultipleRegisters, []byte{0x01, 0x00, 0x00, 0x02, 0x04, 0x01, 0x02, 0xAB, 0xCD}}}, "011001000002040102ABCDE0A6", ":011001000002040102ABCD6D\r\n"},
+	{ADU{1, PDU{ReadHoldingRegisters | 0x80, []byte{0x02}}}, "018302C0F1", ""},
+	{ADU{1, PDU{ReadCoils, []byte{0x03, 0xCD, 0x6B, 0x05}}}, "010103CD6B054282", ":010103CD6B05BE\r\n"},
+}
+
+func TestRTU(t *testing.T) {
+	for _, rec := range frameTests {
+		frame, err := EncodeRTU(rec.adu)
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+		if found := hex.FromBytes(frame); found != rec.rtu {
+			t.Errorf("Expecting %s, got %s", rec.rtu, found)
+		}
+		adu, err := DecodeRTU(frame)
+		if err != nil {
+			t.Errorf("%s unexpected error %v", rec.rtu, err)
+		}
+		if adu.Address != rec.adu.Address || adu.Function != rec.adu.Function || !bytes.Equal(adu.Data, rec.adu.Data) {
+			t.Errorf("%s expecting %v, got %v", rec.rtu, rec.adu, adu)
+		}
+	}
+}
+
+func TestASCII(t *testing.T) {
+	for _, rec := range frameTests {
+		if rec.ascii == "" {
+			continue
+		}
+		frame, err := EncodeASCII(rec.adu)
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+		if string(frame) != rec.ascii {
+			t.Errorf("Expecting %q, got %q", rec.ascii, frame)
+		}
+		for _, in := range []string{rec.ascii, string(bytes.ToLower([]byte(rec.ascii)))} {
+			adu, err := DecodeASCII([]byte(in))
+			if err != nil {
+				t.Errorf("%q unexpected error %v", in, err)
+			}
+			if adu.Address != rec.adu.Address || adu.Function != rec.adu.Function || !bytes.Equal(adu.Data, rec.adu.Data) {
+				t.Errorf("%q expecting %v, got %v", in, rec.adu, adu)
+			}
+		}
+	}
+}
+
+func TestDecodeRTU_errors(t *testing.T) {
+	tests := []struct {
+		hex string
+		err error
+	}{
+		{"010300", FrameError{"RTU frame shorter than 4 bytes"}},
+		{"01030000000AC5CE", ChecksumError{"CRC", 0xCDC5, 0xCEC5}},
+		{"01030000000BC5CD", ChecksumError{"CRC", 0x0D04, 0xCDC5}},
+	}
+	for _, rec := range tests {
+		frame, _ := hex.ToBytes(rec.hex)
+		if _, err := DecodeRTU(frame); err != rec.err {
+			t.Errorf("%s expecting %v, got %v", rec.hex, rec.err, err)
+		}
+	}
+	if _, err := DecodeRTU(make([]byte, 257)); !errors.Is(err, ErrFrame) {
+		t.Errorf("Expecting ErrFrame, got %v", err)
+	}
+	if _, err := EncodeRTU(ADU{1, PDU{ReadCoils, make([]byte, MaxPDU)}}); !errors.Is(err, ErrField) {
+		t.Errorf("Expecting ErrField, got %v", err)
+	}
+	for _, addr := range []byte{MaxAddress + 1, 0xff} {
+		if _, err := EncodeRTU(ADU{addr, PDU{ReadCoils, []byte{0, 0, 0, 1}}}); !errors.Is(err, ErrField) {
+			t.Errorf("Address %d expecting ErrField, got %v", addr, err)
+		}
+		if _, err := EncodeASCII(ADU{addr, PDU{ReadCoils, []byte{0, 0, 0, 1}}}); !errors.Is(err, ErrField) {
+			t.Errorf("Address %d expecting ErrField, got %v", addr, err)
+		}
+	}
+}
+
+func TestDecodeASCII_errors(t *testing.T) {
+	tests := []struct {
+		frame string
+		err   error
+	}{
+		{"1103006B00037E\r\n", FrameError{"ASCII frame doesn't start with ':'"}},
+		{":1103006B00037E", FrameError{"ASCII frame doesn't end with CR LF"}},
+		{":1103006B00037E\n", FrameError{"ASCII frame doesn't end with CR LF"}},
+		{":1103006G00037E\r\n", hex.InvalidHexAt('G', 8)},
+		{":1103006B00037\r\n", FrameError{"ASCII frame has an odd number of hex characters"}},
+		{":1103\r\n", FrameError{"ASCII frame shorter than 3 bytes"}},
+		{":1103006B00037F\r\n", ChecksumError{"LRC", 0x7E, 0x7F}},
+		{":1103006B00047E\r\n", ChecksumError{"LRC", 0x7D, 0x7E}},
+	}
+	for _, rec := range tests {
+		if _, err := DecodeASCII([]byte(rec.frame)); err != rec.err {
+			t.Errorf("%q expecting %v, got %v", rec.frame, rec.err, err)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+// Modbus serial line framing (RTU with CRC-16/MODBUS, ASCII with LRC), and
+// PDU builders/parsers for the common function codes
+package modbus
+
+//https://modbus.org/docs/Modbus_Application_Protocol_V1_1b3.pdf
+//https://modbus.org/docs/Modbus_over_serial_line_V1_02.pdf
+
+import (
+	"errors"
+	"fmt"
+)
+
+// The frame is malformed (use errors.Is to test for this)
+var ErrFrame = errors.New("invalid modbus frame")
+
+// The CRC or LRC doesn't match (use errors.Is to test for this)
+var ErrChecksum = errors.New("modbus checksum mismatch")
+
+// A PDU field is out of range or inconsistent (use errors.Is to test for this)
+var ErrField = errors.New("invalid modbus field")
+
+// A malformed frame, with the reason
+type FrameError struct {
+	Reason string
+}
+
+func (e FrameError) Error() string {
+	return "Invalid modbus frame: " + e.Reason
+}
+
+func (e FrameError) Unwrap() error { return ErrFrame }
+
+// The checksum (CRC or LRC) expected from the content, and the one found
+type ChecksumError struct {
+	Kind   string
+	Expect uint16
+	Found  uint16
+}
+
+func (e ChecksumError) Error() string {
+	return fmt.Sprintf("Modbus %s mismatch: expected %04X, found %04X", e.Kind, e.Expect, e.Found)
+}
+
+func (e ChecksumError) Unwrap() error { return ErrChecksum }
+
+// An invalid PDU field, with its value
+type FieldError struct {
+	Field  string
+	Value  int
+	Reason string
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("Invalid modbus %s: %d (%s)", e.Field, e.Value, e.Reason)
+}
+
+func (e FieldError) Unwrap() error { return ErrField }
+
+// Modbus function code
+type FunctionCode byte
+
+const (
+	ReadCoils                  FunctionCode = 0x01
+	ReadDiscreteInputs         FunctionCode = 0x02
+	ReadHoldingRegisters       FunctionCode = 0x03
+	ReadInputRegisters         FunctionCode = 0x04
+	WriteSingleCoil            FunctionCode = 0x05
+	WriteSingleRegister        FunctionCode = 0x06
+	WriteMultipleCoils         FunctionCode = 0x0F
+	WriteMultipleRegisters     FunctionCode = 0x10
+	ReadWriteMultipleRegisters FunctionCode = 0x17
+
+	// Set in the function code of an exception response
+	exceptionFlag = 0x80
+)
+
+// Modbus exception code
+type ExceptionCode byte
+
+const (
+	IllegalFunction                    ExceptionCode = 0x01
+	IllegalDataAddress                 ExceptionCode = 0x02
+	IllegalDataValue                   ExceptionCode = 0x03
+	ServerDeviceFailure                ExceptionCode = 0x04
+	Acknowledge                        ExceptionCode = 0x05
+	ServerDeviceBusy                   ExceptionCode = 0x06
+	MemoryParityError                  ExceptionCode = 0x08
+	GatewayPathUnavailable             ExceptionCode = 0x0A
+	GatewayTargetDeviceFailedToRespond ExceptionCode = 0x0B
+)
+
+// An exception response, as an error
+type Exception struct {
+	Function FunctionCode
+	Code     ExceptionCode
+}
+
+func (e Exception) Error() string {
+	return fmt.Sprintf("Modbus exception %02X for function %02X", byte(e.Code), byte(e.Function))
+}
+
+// Exception response PDU
+func (e Exception) PDU() PDU {
+	return PDU{Function: e.Function | exceptionFlag, Data: []byte{byte(e.Code)}}
+}
+
+// Protocol data unit: a function code and its data
+type PDU struct {
+	Function FunctionCode
+	Data     []byte
+}
+
+// Whether this is an exception response
+func (p PDU) IsException() bool { return p.Function&exceptionFlag != 0 }
+
+// Application data unit (serial line): the server address and a PDU
+type ADU struct {
+	Address byte //0 is broadcast, 1-247 servers
+	PDU
+}
+
+const (
+	// Largest PDU (function and data)
+	MaxPDU = 253
+	// Highest unicast server address
+	MaxAddress = 247
+)
+
+func (a *ADU) check() error {
+	if a.Address > MaxAddress {
+		return FieldError{"address", int(a.Address), fmt.Sprintf("must be at most %d", MaxAddress)}
+	}
+	if len(a.Data)+1 > MaxPDU {
+		return FieldError{"PDU length", len(a.Data) + 1, fmt.Sprintf("must be at most %d", MaxPDU)}
+	}
+	return nil
+}
@@ -0,0 +1,338 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+package modbus
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Quantity limits (so responses fit in a PDU)
+const (
+	MaxReadBits           = 2000 //01, 02
+	MaxReadRegisters      = 125  //03, 04, 17 (read)
+	MaxWriteBits          = 1968 //0F
+	MaxWriteRegisters     = 123  //10
+	MaxReadWriteRegisters = 121  //17 (write)
+)
+
+const (
+	coilOn  = 0xFF00
+	coilOff = 0x0000
+)
+
+// A request for one of the supported function codes
+type Request struct {
+	Function     FunctionCode
+	Address      uint16   //Starting address (the read address for 17)
+	Quantity     uint16   //Bits or registers to read (01-04, 17)
+	WriteAddress uint16   //Starting write address (17)
+	Coils        []bool   //Values to write (05 takes one, 0F)
+	Registers    []uint16 //Values to write (06 takes one, 10, 17)
+}
+
+// A response to one of the supported function codes
+type Response struct {
+	Function  FunctionCode
+	Address   uint16   //Echoed starting address (05, 06, 0F, 10)
+	Quantity  uint16   //Echoed quantity written (0F, 10)
+	Coils     []bool   //Bits read (01, 02), or the value written (05)
+	Registers []uint16 //Registers read (03, 04, 17), or the value written (06)
+}
+
+func quantity(field string, q, max int) error {
+	if q < 1 || q > max {
+		return FieldError{field, q, fmt.Sprintf("must be 1-%d", max)}
+	}
+	return nil
+}
+
+func unsupported(f FunctionCode) error {
+	return FieldError{"function", int(f), "unsupported"}
+}
+
+func dataLength(f FunctionCode, found, expect int) error {
+	if found != expect {
+		return FieldError{"data length", found, fmt.Sprintf("function %02X expects %d", byte(f), expect)}
+	}
+	return nil
+}
+
+// Pack bits, LSB first
+func packBits(bits []bool) []byte {
+	ret := make([]byte, (len(bits)+7)/8)
+	for i, b := range bits {
+		if b {
+			ret[i/8] |= 1 << (i % 8)
+		}
+	}
+	return ret
+}
+
+func unpackBits(p []byte, n int) []bool {
+	ret := make([]bool, n)
+	for i := range ret {
+		ret[i] = p[i/8]&(1<<(i%8)) != 0
+	}
+	return ret
+}
+
+func appendRegisters(p []byte, regs []uint16) []byte {
+	for _, r := range regs {
+		p = binary.BigEndian.AppendUint16(p, r)
+	}
+	return p
+}
+
+func readRegisters(p []byte) []uint16 {
+	ret := make([]uint16, len(p)/2)
+	for i := range ret {
+		ret[i] = binary.BigEndian.Uint16(p[2*i:])
+	}
+	return ret
+}
+
+func u16(a, b uint16) []byte {
+	return []byte{byte(a >> 8), byte(a), byte(b >> 8), byte(b)}
+}
+
+// Build the request PDU, returns a FieldError if a quantity is out of range or
+// the function is unsupported
+func (r Request) Encode() (PDU, error) {
+	p := PDU{Function: r.Function}
+	switch r.Function {
+	case ReadCoils, ReadDiscreteInputs:
+		if err := quantity("quantity", int(r.Quantity), MaxReadBits); err != nil {
+			return p, err
+		}
+		p.Data = u16(r.Address, r.Quantity)
+	case ReadHoldingRegisters, ReadInputRegisters:
+		if err := quantity("quantity", int(r.Quantity), MaxReadRegisters); err != nil {
+			return p, err
+		}
+		p.Data = u16(r.Address, r.Quantity)
+	case WriteSingleCoil:
+		if len(r.Coils) != 1 {
+			return p, FieldError{"coil count", len(r.Coils), "must be 1"}
+		}
+		v := uint16(coilOff)
+		if r.Coils[0] {
+			v = coilOn
+		}
+		p.Data = u16(r.Address, v)
+	case WriteSingleRegister:
+		if len(r.Registers) != 1 {
+			return p, FieldError{"register count", len(r.Registers), "must be 1"}
+		}
+		p.Data = u16(r.Address, r.Registers[0])
+	case WriteMultipleCoils:
+		if err := quantity("coil count", len(r.Coils), MaxWriteBits); err != nil {
+			return p, err
+		}
+		packed := packBits(r.Coils)
+		p.Data = append(u16(r.Address, uint16(len(r.Coils))), byte(len(packed)))
+		p.Data = append(p.Data, packed...)
+	case WriteMultipleRegisters:
+		if err := quantity("register count", len(r.Registers), MaxWriteRegisters); err != nil {
+			return p, err
+		}
+		p.Data = append(u16(r.Address, uint16(len(r.Registers))), byte(2*len(r.Registers)))
+		p.Data = appendRegisters(p.Data, r.Registers)
+	case ReadWriteMultipleRegisters:
+		if err := quantity("quantity", int(r.Quantity), MaxReadRegisters); err != nil {
+			return p, err
+		}
+		if err := quantity("register count", len(r.Registers), MaxReadWriteRegisters); err != nil {
+			return p, err
+		}
+		p.Data = u16(r.Address, r.Quantity)
+		p.Data = append(p.Data, u16(r.WriteAddress, uint16(len(r.Registers)))...)
+		p.Data = append(p.Data, byte(2*len(r.Registers)))
+		p.Data = appendRegisters(p.Data, r.Registers)
+	default:
+		return p, unsupported(r.Function)
+	}
+	return p, nil
+}
+
+// Parse a request PDU, returns a FieldError naming the field that's invalid
+// (a "function" FieldError when it's unsupported)
+func ParseRequest(p PDU) (Request, error) {
+	r := Request{Function: p.Function}
+	d := p.Data
+	switch p.Function {
+	case ReadCoils, ReadDiscreteInputs, ReadHoldingRegisters, ReadInputRegisters:
+		if err := dataLength(p.Function, len(d), 4); err != nil {
+			return r, err
+		}
+		r.Address = binary.BigEndian.Uint16(d)
+		r.Quantity = binary.BigEndian.Uint16(d[2:])
+		max := MaxReadRegisters
+		if p.Function == ReadCoils || p.Function == ReadDiscreteInputs {
+			max = MaxReadBits
+		}
+		return r, quantity("quantity", int(r.Quantity), max)
+	case WriteSingleCoil:
+		if err := dataLength(p.Function, len(d), 4); err != nil {
+			return r, err
+		}
+		r.Address = binary.BigEndian.Uint16(d)
+		v := binary.BigEndian.Uint16(d[2:])
+		if v != coilOn && v != coilOff {
+			return r, FieldError{"coil value", int(v), "must be FF00 or 0000"}
+		}
+		r.Coils = []bool{v == coilOn}
+	case WriteSingleRegister:
+		if err := dataLength(p.Function, len(d), 4); err != nil {
+			return r, err
+		}
+		r.Address = binary.BigEndian.Uint16(d)
+		r.Registers = []uint16{binary.BigEndian.Uint16(d[2:])}
+	case WriteMultipleCoils, WriteMultipleRegisters:
+		if len(d) < 5 {
+			return r, dataLength(p.Function, len(d), 5)
+		}
+		r.Address = binary.BigEndian.Uint16(d)
+		q := int(binary.BigEndian.Uint16(d[2:]))
+		count, expect := int(d[4]), 2*q
+		if p.Function == WriteMultipleCoils {
+			if err := quantity("quantity", q, MaxWriteBits); err != nil {
+				return r, err
+			}
+			expect = (q + 7) / 8
+		} else if err := quantity("quantity", q, MaxWriteRegisters); err != nil {
+			return r, err
+		}
+		if count != expect {
+			return r, FieldError{"byte count", count, fmt.Sprintf("quantity %d needs %d", q, expect)}
+		}
+		if err := dataLength(p.Function, len(d), 5+count); err != nil {
+			return r, err
+		}
+		if p.Function == WriteMultipleCoils {
+			r.Coils = unpackBits(d[5:], q)
+		} else {
+			r.Registers = readRegisters(d[5:])
+		}
+	case ReadWriteMultipleRegisters:
+		if len(d) < 9 {
+			return r, dataLength(p.Function, len(d), 9)
+		}
+		r.Address = binary.BigEndian.Uint16(d)
+		r.Quantity = binary.BigEndian.Uint16(d[2:])
+		r.WriteAddress = binary.BigEndian.Uint16(d[4:])
+		q := int(binary.BigEndian.Uint16(d[6:]))
+		if err := quantity("quantity", int(r.Quantity), MaxReadRegisters); err != nil {
+			return r, err
+		}
+		if err := quantity("write quantity", q, MaxReadWriteRegisters); err != nil {
+			return r, err
+		}
+		if count := int(d[8]); count != 2*q {
+			return r, FieldError{"byte count", count, fmt.Sprintf("write quantity %d needs %d", q, 2*q)}
+		}
+		if err := dataLength(p.Function, len(d), 9+2*q); err != nil {
+			return r, err
+		}
+		r.Registers = readRegisters(d[9:])
+	default:
+		return r, unsupported(p.Function)
+	}
+	return r, nil
+}
+
+// Build the response PDU
+func (r Response) Encode() (PDU, error) {
+	p := PDU{Function: r.Function}
+	switch r.Function {
+	case ReadCoils, ReadDiscreteInputs:
+		if err := quantity("coil count", len(r.Coils), MaxReadBits); err != nil {
+			return p, err
+		}
+		packed := packBits(r.Coils)
+		p.Data = append([]byte{byte(len(packed))}, packed...)
+	case ReadHoldingRegisters, ReadInputRegisters, ReadWriteMultipleRegisters:
+		if err := quantity("register count", len(r.Registers), MaxReadRegisters); err != nil {
+			return p, err
+		}
+		p.Data = appendRegisters([]byte{byte(2 * len(r.Registers))}, r.Registers)
+	case WriteSingleCoil, WriteSingleRegister:
+		return Request{Function: r.Function, Address: r.Address, Coils: r.Coils, Registers: r.Registers}.Encode()
+	case WriteMultipleCoils, WriteMultipleRegisters:
+		p.Data = u16(r.Address, r.Quantity)
+	default:
+		return p, unsupported(r.Function)
+	}
+	return p, nil
+}
+
+// Parse the response to `req`. An exception response is returned as an
+// Exception error, otherwise a FieldError names the field that's invalid or
+// doesn't match the request
+func ParseResponse(req Request, p PDU) (Response, error) {
+	r := Response{Function: p.Function}
+	if p.IsException() {
+		if fn := p.Function &^ exceptionFlag; fn != req.Function {
+			return r, FieldError{"function", int(fn), fmt.Sprintf("request was %02X", byte(req.Function))}
+		}
+		if len(p.Data) != 1 {
+			return r, dataLength(p.Function, len(p.Data), 1)
+		}
+		return r, Exception{p.Function &^ exceptionFlag, ExceptionCode(p.Data[0])}
+	}
+	if p.Function != req.Function {
+		return r, FieldError{"function", int(p.Function), fmt.Sprintf("request was %02X", byte(req.Function))}
+	}
+	d := p.Data
+	switch p.Function {
+	case ReadCoils, ReadDiscreteInputs, ReadHoldingRegisters, ReadInputRegisters, ReadWriteMultipleRegisters:
+		bits := p.Function == ReadCoils || p.Function == ReadDiscreteInputs
+		expect := 2 * int(req.Quantity)
+		if bits {
+			expect = (int(req.Quantity) + 7) / 8
+		}
+		if len(d) < 1 {
+			return r, dataLength(p.Function, len(d), 1+expect)
+		}
+		if int(d[0]) != expect {
+			return r, FieldError{"byte count", int(d[0]), fmt.Sprintf("quantity %d needs %d", req.Quantity, expect)}
+		}
+		if err := dataLength(p.Function, len(d), 1+expect); err != nil {
+			return r, err
+		}
+		if bits {
+			r.Coils = unpackBits(d[1:], int(req.Quantity))
+		} else {
+			r.Registers = readRegisters(d[1:])
+		}
+	case WriteSingleCoil, WriteSingleRegister:
+		echo, err := ParseRequest(p)
+		if err != nil {
+			return r, err
+		}
+		if echo.Address != req.Address {
+			return r, FieldError{"address", int(echo.Address), fmt.Sprintf("request was %d", req.Address)}
+		}
+		r.Address, r.Coils, r.Registers = echo.Address, echo.Coils, echo.Registers
+	case WriteMultipleCoils, WriteMultipleRegisters:
+		if err := dataLength(p.Function, len(d), 4); err != nil {
+			return r, err
+		}
+		r.Address = binary.BigEndian.Uint16(d)
+		r.Quantity = binary.BigEndian.Uint16(d[2:])
+		if r.Address != req.Address {
+			return r, FieldError{"address", int(r.Address), fmt.Sprintf("request was %d", req.Address)}
+		}
+		n := len(req.Registers)
+		if p.Function == WriteMultipleCoils {
+			n = len(req.Coils)
+		}
+		if int(r.Quantity) != n {
+			return r, FieldError{"quantity", int(r.Quantity), fmt.Sprintf("request was %d", n)}
+		}
+	default:
+		return r, unsupported(p.Function)
+	}
+	return r, nil
+}
@@ -0,0 +1,167 @@
+package modbus
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gnabgib/gnablib-go/encoding/hex"
+)
+
+// Application protocol spec examples
+var requestTests = []struct {
+	req Request
+	hex string
+}{
+	{Request{Function: ReadCoils, Address: 0x13, Quantity: 0x25}, "0100130025"},
+	{Request{Function: ReadDiscreteInputs, Address: 0xC4, Quantity: 0x16}, "0200C40016"},
+	{Request{Function: ReadHoldingRegisters, Address: 0x6B, Quantity: 3}, "03006B0003"},
+	{Request{Function: ReadInputRegisters, Address: 0x08, Quantity: 1}, "0400080001"},
+	{Request{Function: WriteSingleCoil, Address: 0xAC, Coils: []bool{true}}, "0500ACFF00"},
+	{Request{Function: WriteSingleCoil, Address: 0xAC, Coils: []bool{false}}, "0500AC0000"},
+	{Request{Function: WriteSingleRegister, Address: 0x01, Registers: []uint16{3}}, "0600010003"},
+	{Request{Function: WriteMultipleCoils, Address: 0x13, Coils: []bool{true, false, true, true, false, false, true, true, true, false}}, "0F0013000A02CD01"},
+	{Request{Function: WriteMultipleRegisters, Address: 0x01, Registers: []uint16{0x000A, 0x0102}}, "100001000204000A0102"},
+	{Request{Function: ReadWriteMultipleRegisters, Address: 0x03, Quantity: 6, WriteAddress: 0x0E, Registers: []uint16{0x00FF, 0x00FF, 0x00FF}}, "1700030006000E00030600FF00FF00FF"},
+}
+
+func TestRequest(t *testing.T) {
+	for _, rec := range requestTests {
+		p, err := rec.req.Encode()
+		if err != nil {
+			t.Fatalf("%v unexpected error %v", rec.req, err)
+		}
+		raw := append([]byte{byte(p.Function)}, p.Data...)
+		if found := hex.FromBytes(raw); found != rec.hex {
+			t.Errorf("Expecting %s, got %s", rec.hex, found)
+		}
+		parsed, err := ParseRequest(p)
+		if err != nil {
+			t.Errorf("%s unexpected error %v", rec.hex, err)
+		}
+		if !reflect.DeepEqual(parsed, rec.req) {
+			t.Errorf("%s expecting %v, got %v", rec.hex, rec.req, parsed)
+		}
+	}
+}
+
+func TestRequest_errors(t *testing.T) {
+	tests := []struct {
+		req   Request
+		field string
+	}{
+		{Request{Function: ReadCoils, Quantity: 0}, "quantity"},
+		{Request{Function: ReadDiscreteInputs, Quantity: MaxReadBits + 1}, "quantity"},
+		{Request{Function: ReadHoldingRegisters, Quantity: MaxReadRegisters + 1}, "quantity"},
+		{Request{Function: WriteSingleCoil}, "coil count"},
+		{Request{Function: WriteSingleRegister, Registers: []uint16{1, 2}}, "register count"},
+		{Request{Function: WriteMultipleCoils, Coils: make([]bool, MaxWriteBits+1)}, "coil count"},
+		{Request{Function: WriteMultipleRegisters}, "register count"},
+		{Request{Function: ReadWriteMultipleRegisters, Quantity: 1, Registers: make([]uint16, MaxReadWriteRegisters+1)}, "register count"},
+		{Request{Function: 0x2B}, "function"},
+	}
+	for _, rec := range tests {
+		_, err := rec.req.Encode()
+		var fe FieldError
+		if !errors.As(err, &fe) || fe.Field != rec.field {
+			t.Errorf("%v expecting a %s FieldError, got %v", rec.req, rec.field, err)
+		}
+	}
+}
+
+func TestParseRequest_errors(t *testing.T) {
+	tests := []struct {
+		hex   string
+		field string
+	}{
+		{"01001300", "data length"},
+		{"0100130000", "quantity"},
+		{"03006B007E", "quantity"},
+		{"0500AC1234", "coil value"},
+		{"06000100", "data length"},
+		{"0F0013000A01CD", "byte count"},
+		{"0F0013000A02CD", "data length"},
+		{"0F0013", "data length"},
+		{"100001000203000A01", "byte count"},
+		{"100001000000", "quantity"},
+		{"1700030006000E00030400FF00FF", "byte count"},
+		{"1700030000000E00010200FF", "quantity"},
+		{"1700030006000E0003", "data length"},
+		{"2B0E0100", "function"},
+	}
+	for _, rec := range tests {
+		raw, _ := hex.ToBytes(rec.hex)
+		_, err := ParseRequest(PDU{FunctionCode(raw[0]), raw[1:]})
+		var fe FieldError
+		if !errors.As(err, &fe) || fe.Field != rec.field {
+			t.Errorf("%s expecting a %s FieldError, got %v", rec.hex, rec.field, err)
+		}
+	}
+}
+
+// Application protocol spec examples
+var responseTests = []struct {
+	req Request
+	res Response
+	hex string
+}{
+	{requestTests[0].req, Response{Function: ReadCoils, Coils: unpackBits([]byte{0xCD, 0x6B, 0xB2, 0x0E, 0x1B}, 0x25)}, "0105CD6BB20E1B"},
+	{requestTests[2].req, Response{Function: ReadHoldingRegisters, Registers: []uint16{0x022B, 0x0000, 0x0064}}, "0306022B00000064"},
+	{requestTests[4].req, Response{Function: WriteSingleCoil, Address: 0xAC, Coils: []bool{true}}, "0500ACFF00"},
+	{requestTests[6].req, Response{Function: WriteSingleRegister, Address: 0x01, Registers: []uint16{3}}, "0600010003"},
+	{requestTests[7].req, Response{Function: WriteMultipleCoils, Address: 0x13, Quantity: 10}, "0F0013000A"},
+	{requestTests[8].req, Response{Function: WriteMultipleRegisters, Address: 0x01, Quantity: 2}, "1000010002"},
+	{requestTests[9].req, Response{Function: ReadWriteMultipleRegisters, Registers: []uint16{0xFE, 0xACF0, 0x1FE, 0xCD, 0x1FE, 0xCD}}, "170C00FEACF001FE00CD01FE00CD"},
+}
+
+func TestResponse(t *testing.T) {
+	for _, rec := range responseTests {
+		p, err := rec.res.Encode()
+		if err != nil {
+			t.Fatalf("%v unexpected error %v", rec.res, err)
+		}
+		raw := append([]byte{byte(p.Function)}, p.Data...)
+		if found := hex.FromBytes(raw); found != rec.hex {
+			t.Errorf("Expecting %s, got %s", rec.hex, found)
+		}
+		parsed, err := ParseResponse(rec.req, p)
+		if err != nil {
+			t.Errorf("%s unexpected error %v", rec.hex, err)
+		}
+		if !reflect.DeepEqual(parsed, rec.res) {
+			t.Errorf("%s expecting %v, got %v", rec.hex, rec.res, parsed)
+		}
+	}
+}
+
+func TestParseResponse_errors(t *testing.T) {
+	tests := []struct {
+		req   Request
+		hex   string
+		field string
+	}{
+		{requestTests[0].req, "0104CD6BB20E", "byte count"},
+		{requestTests[0].req, "0105CD6BB20E", "data length"},
+		{requestTests[0].req, "01", "data length"},
+		{requestTests[2].req, "0306022B0000", "data length"},
+		{requestTests[2].req, "0400000000", "function"},
+		{requestTests[4].req, "0500ADFF00", "address"},
+		{requestTests[7].req, "0F0014000A", "address"},
+		{requestTests[7].req, "0F0013000B", "quantity"},
+		{requestTests[8].req, "10000100", "data length"},
+		{requestTests[2].req, "830203", "data length"},
+		{requestTests[2].req, "8102", "function"},
+	}
+	for _, rec := range tests {
+		raw, _ := hex.ToBytes(rec.hex)
+		_, err := ParseResponse(rec.req, PDU{FunctionCode(raw[0]), raw[1:]})
+		var fe FieldError
+		if !errors.As(err, &fe) || fe.Field != rec.field {
+			t.Errorf("%s expecting a %s FieldError, got %v", rec.hex, rec.field, err)
+		}
+	}
+	_, err := ParseResponse(requestTests[2].req, PDU{0x83, []byte{0x02}})
+	if err != (Exception{ReadHoldingRegisters, IllegalDataAddress}) {
+		t.Errorf("Expecting an exception, got %v", err)
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+package modbus
+
+import (
+	"errors"
+	"sync"
+)
+
+// No response frame is waiting (a real line would time out)
+var ErrNoResponse = errors.New("no modbus response")
+
+// An in memory Modbus server, a stand-in for a device in tests
+type Server struct {
+	Address          byte
+	Coils            []bool
+	DiscreteInputs   []bool
+	HoldingRegisters []uint16
+	InputRegisters   []uint16
+	mu               sync.Mutex
+}
+
+// A new server at `address` with `size` of each coil, input and register
+func NewServer(address byte, size int) *Server {
+	return &Server{
+		Address:          address,
+		Coils:            make([]bool, size),
+		DiscreteInputs:   make([]bool, size),
+		HoldingRegisters: make([]uint16, size),
+		InputRegisters:   make([]uint16, size),
+	}
+}
+
+func inRange(addr, n, size int) bool {
+	return addr+n <= size
+}
+
+// Process a request PDU and build the response (which may be an exception)
+func (s *Server) Handle(p PDU) PDU {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	req, err := ParseRequest(p)
+	if err != nil {
+		if fe, ok := err.(FieldError); ok && fe.Field == "function" {
+			return Exception{p.Function, IllegalFunction}.PDU()
+		}
+		return Exception{p.Function, IllegalDataValue}.PDU()
+	}
+	addr := int(req.Address)
+	res := Response{Function: req.Function, Address: req.Address}
+	fail := func(c ExceptionCode) PDU { return Exception{p.Function, c}.PDU() }
+	switch req.Function {
+	case ReadCoils, ReadDiscreteInputs:
+		src := s.Coils
+		if req.Function == ReadDiscreteInputs {
+			src = s.DiscreteInputs
+		}
+		if !inRange(addr, int(req.Quantity), len(src)) {
+			return fail(IllegalDataAddress)
+		}
+		res.Coils = append([]bool{}, src[addr:addr+int(req.Quantity)]...)
+	case ReadHoldingRegisters, ReadInputRegisters:
+		src := s.HoldingRegisters
+		if req.Function == ReadInputRegisters {
+			src = s.InputRegisters
+		}
+		if !inRange(addr, int(req.Quantity), len(src)) {
+			return fail(IllegalDataAddress)
+		}
+		res.Registers = append([]uint16{}, src[addr:addr+int(req.Quantity)]...)
+	case WriteSingleCoil, WriteMultipleCoils:
+		if !inRange(addr, len(req.Coils), len(s.Coils)) {
+			return fail(IllegalDataAddress)
+		}
+		copy(s.Coils[addr:], req.Coils)
+		res.Coils = req.Coils
+		res.Quantity = uint16(len(req.Coils))
+	case WriteSingleRegister, WriteMultipleRegisters:
+		if !inRange(addr, len(req.Registers), len(s.HoldingRegisters)) {
+			return fail(IllegalDataAddress)
+		}
+		copy(s.HoldingRegisters[addr:], req.Registers)
+		res.Registers = req.Registers
+		res.Quantity = uint16(len(req.Registers))
+	case ReadWriteMultipleRegisters:
+		wAddr := int(req.WriteAddress)
+		if !inRange(addr, int(req.Quantity), len(s.HoldingRegisters)) ||
+			!inRange(wAddr, len(req.Registers), len(s.HoldingRegisters)) {
+			return fail(IllegalDataAddress)
+		}
+		//The write happens before the read
+		copy(s.HoldingRegisters[wAddr:], req.Registers)
+		res.Registers = append([]uint16{}, s.HoldingRegisters[addr:addr+int(req.Quantity)]...)
+	}
+	//Always succeeds, the request was valid
+	out, _ := res.Encode()
+	return out
+}
+
+// Serve an ADU: nil if it's for another server, or a broadcast (which is still
+// processed)
+func (s *Server) serve(a ADU) *ADU {
+	if a.Address != 0 && a.Address != s.Address {
+		return nil
+	}
+	res := s.Handle(a.PDU)
+	if a.Address == 0 {
+		return nil
+	}
+	return &ADU{Address: s.Address, PDU: res}
+}
+
+// Serve an RTU request frame, the response frame is nil when there's no reply.
+// Errors are from decoding (a real server stays silent)
+func (s *Server) ServeRTU(frame []byte) ([]byte, error) {
+	a, err := DecodeRTU(frame)
+	if err != nil {
+		return nil, err
+	}
+	if res := s.serve(a); res != nil {
+		return EncodeRTU(*res)
+	}
+	return nil, nil
+}
+
+// Serve an ASCII request frame, the response frame is nil when there's no
+// reply. Errors are from decoding (a real server stays silent)
+func (s *Server) ServeASCII(frame []byte) ([]byte, error) {
+	a, err := DecodeASCII(frame)
+	if err != nil {
+		return nil, err
+	}
+	if res := s.serve(a); res != nil {
+		return EncodeASCII(*res)
+	}
+	return nil, nil
+}
+
+// A loopback line to a Server: each Write is a request frame, the next Read
+// gets the response frame (or ErrNoResponse when the server stayed silent)
+type Loopback struct {
+	s        *Server
+	ascii    bool
+	response []byte
+}
+
+// A new loopback to `s` using RTU framing, or ASCII when `ascii` is true
+func NewLoopback(s *Server, ascii bool) *Loopback {
+	return &Loopback{s: s, ascii: ascii}
+}
+
+func (l *Loopback) Write(frame []byte) (int, error) {
+	if l.ascii {
+		l.response, _ = l.s.ServeASCII(frame)
+	} else {
+		l.response, _ = l.s.ServeRTU(frame)
+	}
+	return len(frame), nil
+}
+
+// Read the response frame, which must fit in `p` (256 bytes for RTU, 513 for
+// ASCII)
+func (l *Loopback) Read(p []byte) (int, error) {
+	if l.response == nil {
+		return 0, ErrNoResponse
+	}
+	if len(p) < len(l.response) {
+		return 0, FrameError{"read buffer smaller than the response"}
+	}
+	n := copy(p, l.response)
+	l.response = nil
+	return n, nil
+}
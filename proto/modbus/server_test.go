@@ -0,0 +1,126 @@
+package modbus
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Send a request over a loopback line and parse the response
+func roundTrip(t *testing.T, l *Loopback, address byte, req Request) (Response, error) {
+	t.Helper()
+	p, err := req.Encode()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	var frame []byte
+	if l.ascii {
+		frame, err = EncodeASCII(ADU{address, p})
+	} else {
+		frame, err = EncodeRTU(ADU{address, p})
+	}
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	l.Write(frame)
+	buf := make([]byte, 513)
+	n, err := l.Read(buf)
+	if err != nil {
+		return Response{}, err
+	}
+	var a ADU
+	if l.ascii {
+		a, err = DecodeASCII(buf[:n])
+	} else {
+		a, err = DecodeRTU(buf[:n])
+	}
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if a.Address != address {
+		t.Errorf("Expecting a response from %d, got %d", address, a.Address)
+	}
+	return ParseResponse(req, a.PDU)
+}
+
+func TestServer(t *testing.T) {
+	for _, ascii := range []bool{false, true} {
+		s := NewServer(17, 100)
+		s.DiscreteInputs[3] = true
+		s.InputRegisters[8] = 0x000A
+		l := NewLoopback(s, ascii)
+		tests := []struct {
+			req    Request
+			expect Response
+		}{
+			{Request{Function: WriteMultipleCoils, Address: 2, Coils: []bool{true, false, true}}, Response{Function: WriteMultipleCoils, Address: 2, Quantity: 3}},
+			{Request{Function: WriteSingleCoil, Address: 3, Coils: []bool{true}}, Response{Function: WriteSingleCoil, Address: 3, Coils: []bool{true}}},
+			{Request{Function: ReadCoils, Address: 1, Quantity: 5}, Response{Function: ReadCoils, Coils: []bool{false, true, true, true, false}}},
+			{Request{Function: ReadDiscreteInputs, Address: 2, Quantity: 2}, Response{Function: ReadDiscreteInputs, Coils: []bool{false, true}}},
+			{Request{Function: WriteMultipleRegisters, Address: 10, Registers: []uint16{1, 2, 3}}, Response{Function: WriteMultipleRegisters, Address: 10, Quantity: 3}},
+			{Request{Function: WriteSingleRegister, Address: 11, Registers: []uint16{0xBEEF}}, Response{Function: WriteSingleRegister, Address: 11, Registers: []uint16{0xBEEF}}},
+			{Request{Function: ReadHoldingRegisters, Address: 9, Quantity: 4}, Response{Function: ReadHoldingRegisters, Registers: []uint16{0, 1, 0xBEEF, 3}}},
+			{Request{Function: ReadInputRegisters, Address: 8, Quantity: 1}, Response{Function: ReadInputRegisters, Registers: []uint16{0x000A}}},
+			//Written before the read
+			{Request{Function: ReadWriteMultipleRegisters, Address: 12, Quantity: 3, WriteAddress: 13, Registers: []uint16{7, 8}}, Response{Function: ReadWriteMultipleRegisters, Registers: []uint16{3, 7, 8}}},
+		}
+		for _, rec := range tests {
+			res, err := roundTrip(t, l, 17, rec.req)
+			if err != nil {
+				t.Errorf("ascii=%v %v unexpected error %v", ascii, rec.req, err)
+			}
+			if !reflect.DeepEqual(res, rec.expect) {
+				t.Errorf("ascii=%v expecting %v, got %v", ascii, rec.expect, res)
+			}
+		}
+	}
+}
+
+func TestServerExceptions(t *testing.T) {
+	l := NewLoopback(NewServer(1, 10), false)
+	tests := []struct {
+		req    Request
+		expect Exception
+	}{
+		{Request{Function: ReadCoils, Address: 8, Quantity: 3}, Exception{ReadCoils, IllegalDataAddress}},
+		{Request{Function: WriteSingleRegister, Address: 10, Registers: []uint16{1}}, Exception{WriteSingleRegister, IllegalDataAddress}},
+		{Request{Function: ReadWriteMultipleRegisters, Address: 0, Quantity: 1, WriteAddress: 9, Registers: []uint16{1, 2}}, Exception{ReadWriteMultipleRegisters, IllegalDataAddress}},
+	}
+	for _, rec := range tests {
+		if _, err := roundTrip(t, l, 1, rec.req); err != rec.expect {
+			t.Errorf("%v expecting %v, got %v", rec.req, rec.expect, err)
+		}
+	}
+	s := NewServer(1, 10)
+	if found := s.Handle(PDU{0x2B, []byte{0x0E, 0x01, 0x00}}); found.Function != 0xAB || found.Data[0] != byte(IllegalFunction) {
+		t.Errorf("Expecting an illegal function exception, got %v", found)
+	}
+	if found := s.Handle(PDU{ReadCoils, []byte{0, 0, 0, 0}}); found.Function != 0x81 || found.Data[0] != byte(IllegalDataValue) {
+		t.Errorf("Expecting an illegal data value exception, got %v", found)
+	}
+}
+
+func TestServerSilent(t *testing.T) {
+	s := NewServer(5, 10)
+	l := NewLoopback(s, false)
+	//Another server
+	if _, err := roundTrip(t, l, 6, Request{Function: ReadCoils, Address: 0, Quantity: 1}); err != ErrNoResponse {
+		t.Errorf("Expecting ErrNoResponse, got %v", err)
+	}
+	//Broadcasts are processed, but not answered
+	if _, err := roundTrip(t, l, 0, Request{Function: WriteSingleRegister, Address: 2, Registers: []uint16{42}}); err != ErrNoResponse {
+		t.Errorf("Expecting ErrNoResponse, got %v", err)
+	}
+	if s.HoldingRegisters[2] != 42 {
+		t.Errorf("Expecting the broadcast to be written, got %d", s.HoldingRegisters[2])
+	}
+	//A corrupt frame
+	frame, _ := EncodeRTU(ADU{5, PDU{ReadCoils, []byte{0, 0, 0, 1}}})
+	frame[2] ^= 1
+	l.Write(frame)
+	if _, err := l.Read(make([]byte, 256)); err != ErrNoResponse {
+		t.Errorf("Expecting ErrNoResponse, got %v", err)
+	}
+	if _, err := s.ServeRTU(frame); err == nil {
+		t.Errorf("Expecting a checksum error")
+	}
+}
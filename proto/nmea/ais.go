@@ -0,0 +1,164 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+package nmea
+
+import (
+	"errors"
+	"strconv"
+)
+
+// The AIS payload contains a character outside the 6bit armour
+var ErrArmour = errors.New("invalid AIS payload armour")
+
+// AIVDM/AIVDO: an AIS radio message fragment
+type VDM struct {
+	Own     bool   //VDO (own vessel) rather than VDM
+	Count   int    //Fragments in the message
+	Number  int    //This fragment, from 1
+	SeqID   int    //Multi-fragment sequential message ID, -1 when empty
+	Channel string //A or B (1 or 2 on some receivers)
+	Payload string //6bit armoured data
+	Fill    int    //Fill bits at the end of the payload (0-5)
+}
+
+// Decode an AIVDM or AIVDO sentence
+func ParseVDM(s Sentence) (VDM, error) {
+	f := &fields{s: s}
+	if s.Type != "VDM" && s.Type != "VDO" {
+		return VDM{}, ErrType
+	}
+	v := VDM{
+		Own:     s.Type == "VDO",
+		Count:   f.int(0, 0),
+		Number:  f.int(1, 0),
+		SeqID:   f.int(2, -1),
+		Channel: f.str(3),
+		Payload: f.str(4),
+		Fill:    f.int(5, 0),
+	}
+	if v.Count < 1 || v.Number < 1 || v.Number > v.Count {
+		f.fail(1)
+	}
+	if v.Fill < 0 || v.Fill > 5 {
+		f.fail(5)
+	}
+	return v, f.err
+}
+
+// The sentence for a VDM fragment, talker AI
+func (v VDM) Sentence() Sentence {
+	typ := "VDM"
+	if v.Own {
+		typ = "VDO"
+	}
+	seq := ""
+	if v.SeqID >= 0 {
+		seq = strconv.Itoa(v.SeqID)
+	}
+	return Sentence{'!', "AI", typ, []string{
+		strconv.Itoa(v.Count), strconv.Itoa(v.Number), seq, v.Channel, v.Payload, strconv.Itoa(v.Fill),
+	}}
+}
+
+// Decode a 6bit armoured payload into bits (packed MSB first), `fill` bits are
+// dropped from the end. Returns the bits and their count
+func Dearmour(payload string, fill int) ([]byte, int, error) {
+	n := len(payload)*6 - fill
+	if fill < 0 || fill > 5 || n < 0 {
+		return nil, 0, ErrArmour
+	}
+	ret := make([]byte, (len(payload)*6+7)/8)
+	bit := 0
+	for i := 0; i < len(payload); i++ {
+		c := payload[i]
+		if c < '0' || c > 'w' || (c > 'W' && c < '`') {
+			return nil, 0, ErrArmour
+		}
+		c -= '0'
+		if c > 40 {
+			c -= 8
+		}
+		for j := 5; j >= 0; j-- {
+			if c>>j&1 != 0 {
+				ret[bit>>3] |= 0x80 >> (bit & 7)
+			}
+			bit++
+		}
+	}
+	ret = ret[:(n+7)/8]
+	if n&7 != 0 {
+		ret[len(ret)-1] &= 0xff << (8 - n&7)
+	}
+	return ret, n, nil
+}
+
+// Encode the first `nbits` of data (packed MSB first) as a 6bit armoured
+// payload, returns the payload and the fill bit count
+func Armour(data []byte, nbits int) (string, int) {
+	chars := (nbits + 5) / 6
+	ret := make([]byte, chars)
+	for i := range ret {
+		var c byte
+		for j := 0; j < 6; j++ {
+			bit := i*6 + j
+			c <<= 1
+			if bit < nbits && data[bit>>3]&(0x80>>(bit&7)) != 0 {
+				c |= 1
+			}
+		}
+		if c >= 40 {
+			c += 8
+		}
+		ret[i] = c + '0'
+	}
+	return string(ret), chars*6 - nbits
+}
+
+// An unsigned field of `n` bits (n<=64) starting at bit `at` of dearmoured data
+func Bits(data []byte, at, n int) uint64 {
+	var ret uint64
+	for i := at; i < at+n; i++ {
+		ret <<= 1
+		if i>>3 < len(data) && data[i>>3]&(0x80>>(i&7)) != 0 {
+			ret |= 1
+		}
+	}
+	return ret
+}
+
+// Joins multi-fragment AIS messages, fragments are grouped by channel and
+// sequential ID. A fragment out of order drops the partial message
+type Assembler struct {
+	parts map[string][]VDM
+}
+
+// Add a fragment, when it completes a message the joined payload and its fill
+// bits are returned with ok true
+func (a *Assembler) Add(v VDM) (payload string, fill int, ok bool) {
+	if v.Count == 1 {
+		return v.Payload, v.Fill, true
+	}
+	if a.parts == nil {
+		a.parts = make(map[string][]VDM)
+	}
+	key := v.Channel + "/" + strconv.Itoa(v.SeqID)
+	got := a.parts[key]
+	if v.Number != len(got)+1 || (len(got) > 0 && got[0].Count != v.Count) {
+		delete(a.parts, key)
+		if v.Number != 1 {
+			return "", 0, false
+		}
+		got = nil
+	}
+	got = append(got, v)
+	if len(got) < v.Count {
+		a.parts[key] = got
+		return "", 0, false
+	}
+	delete(a.parts, key)
+	for _, p := range got {
+		payload += p.Payload
+	}
+	return payload, v.Fill, true
+}
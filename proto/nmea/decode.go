@@ -0,0 +1,160 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+package nmea
+
+import (
+	"errors"
+	"time"
+)
+
+// The sentence isn't of the type the decoder expects
+var ErrType = errors.New("wrong NMEA sentence type")
+
+// Empty numeric fields decode as NaN (floats), -1 (times, ints where 0 is
+// valid) or 0 (characters)
+
+// GGA: Global positioning system fix data
+type GGA struct {
+	Time        time.Duration //UTC time of day
+	Lat, Lon    float64       //Degrees, south and west are negative
+	Quality     int           //0 invalid, 1 GPS, 2 DGPS, 4 RTK fixed, 5 RTK float, 6 estimated..
+	Satellites  int
+	HDOP        float64
+	Altitude    float64 //Above mean sea level, meters
+	GeoidSep    float64 //Geoid separation, meters
+	DGPSAge     float64 //Seconds since the last DGPS update
+	DGPSStation int     //-1 when empty
+}
+
+// RMC: Recommended minimum specific GNSS data
+type RMC struct {
+	Time      time.Time //UTC, the zero time when either the date or time is empty
+	Valid     bool      //Status A (valid) rather than V (warning)
+	Lat, Lon  float64   //Degrees, south and west are negative
+	Speed     float64   //Knots
+	Course    float64   //Degrees true
+	Variation float64   //Magnetic variation in degrees, west is negative
+	Mode      byte      //FAA mode indicator (NMEA 2.3+), 0 when absent
+}
+
+// VTG: Track made good and ground speed
+type VTG struct {
+	CourseTrue     float64 //Degrees
+	CourseMagnetic float64 //Degrees
+	SpeedKnots     float64
+	SpeedKmh       float64
+	Mode           byte //FAA mode indicator (NMEA 2.3+), 0 when absent
+}
+
+// GSA: GNSS DOP and active satellites
+type GSA struct {
+	Auto             bool //Selection mode A (automatic) rather than M (manual)
+	Fix              int  //1 none, 2 2D, 3 3D
+	PRN              []int
+	PDOP, HDOP, VDOP float64
+}
+
+// One satellite in view
+type SatInfo struct {
+	PRN       int
+	Elevation int //Degrees, -1 when empty
+	Azimuth   int //Degrees true, -1 when empty
+	SNR       int //dB, -1 when not tracking
+}
+
+// GSV: GNSS satellites in view, one sentence of a group
+type GSV struct {
+	Total      int //Sentences in the group
+	Number     int //This sentence, from 1
+	InView     int //Satellites in view across the group
+	Satellites []SatInfo
+}
+
+func expect(s Sentence, typ string) *fields {
+	f := &fields{s: s}
+	if s.Type != typ {
+		f.err = ErrType
+	}
+	return f
+}
+
+// Decode a GGA sentence
+func ParseGGA(s Sentence) (GGA, error) {
+	f := expect(s, "GGA")
+	g := GGA{
+		Time:        f.clock(0),
+		Lat:         f.coord(1, 'N', 'S'),
+		Lon:         f.coord(3, 'E', 'W'),
+		Quality:     f.int(5, 0),
+		Satellites:  f.int(6, 0),
+		HDOP:        f.float(7),
+		Altitude:    f.float(8),
+		GeoidSep:    f.float(10),
+		DGPSAge:     f.float(12),
+		DGPSStation: f.int(13, -1),
+	}
+	return g, f.err
+}
+
+// Decode an RMC sentence
+func ParseRMC(s Sentence) (RMC, error) {
+	f := expect(s, "RMC")
+	clock := f.clock(0)
+	r := RMC{
+		Valid:     f.char(1) == 'A',
+		Lat:       f.coord(2, 'N', 'S'),
+		Lon:       f.coord(4, 'E', 'W'),
+		Speed:     f.float(6),
+		Course:    f.float(7),
+		Variation: f.signed(f.float(9), 10, 'E', 'W'),
+		Mode:      f.char(11),
+	}
+	y, m, d := f.date(8)
+	if y != 0 && clock >= 0 {
+		r.Time = time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Add(clock)
+	}
+	return r, f.err
+}
+
+// Decode a VTG sentence (the pre NMEA 2.0 form without unit fields isn't supported)
+func ParseVTG(s Sentence) (VTG, error) {
+	f := expect(s, "VTG")
+	v := VTG{
+		CourseTrue:     f.float(0),
+		CourseMagnetic: f.float(2),
+		SpeedKnots:     f.float(4),
+		SpeedKmh:       f.float(6),
+		Mode:           f.char(8),
+	}
+	return v, f.err
+}
+
+// Decode a GSA sentence, empty PRN slots are skipped
+func ParseGSA(s Sentence) (GSA, error) {
+	f := expect(s, "GSA")
+	g := GSA{Auto: f.char(0) == 'A', Fix: f.int(1, 0)}
+	for i := 2; i < 14; i++ {
+		if f.str(i) != "" {
+			g.PRN = append(g.PRN, f.int(i, 0))
+		}
+	}
+	g.PDOP, g.HDOP, g.VDOP = f.float(14), f.float(15), f.float(16)
+	return g, f.err
+}
+
+// Decode a GSV sentence
+func ParseGSV(s Sentence) (GSV, error) {
+	f := expect(s, "GSV")
+	g := GSV{Total: f.int(0, 0), Number: f.int(1, 0), InView: f.int(2, 0)}
+	//Up to 4 satellites, NMEA 4.1 may add a trailing signal ID field
+	for i := 3; i+3 < len(s.Fields); i += 4 {
+		g.Satellites = append(g.Satellites, SatInfo{
+			PRN:       f.int(i, 0),
+			Elevation: f.int(i+1, -1),
+			Azimuth:   f.int(i+2, -1),
+			SNR:       f.int(i+3, -1),
+		})
+	}
+	return g, f.err
+}
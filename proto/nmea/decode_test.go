@@ -0,0 +1,247 @@
+package nmea
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func mustParse(t *testing.T, line string) Sentence {
+	s, err := Parse(line)
+	if err != nil {
+		t.Fatalf("Parse(%q) unexpected error %v", line, err)
+	}
+	return s
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9 || (math.IsNaN(a) && math.IsNaN(b))
+}
+
+func TestParseGGA(t *testing.T) {
+	g, err := ParseGGA(mustParse(t, gga))
+	if err != nil {
+		t.Fatalf("ParseGGA unexpected error %v", err)
+	}
+	if g.Time != 12*time.Hour+35*time.Minute+19*time.Second {
+		t.Errorf("Time expecting 12:35:19, got %v", g.Time)
+	}
+	floats := []struct {
+		name          string
+		expect, found float64
+	}{
+		{"Lat", 48 + 7.038/60, g.Lat},
+		{"Lon", 11 + 31.0/60, g.Lon},
+		{"HDOP", 0.9, g.HDOP},
+		{"Altitude", 545.4, g.Altitude},
+		{"GeoidSep", 46.9, g.GeoidSep},
+		{"DGPSAge", math.NaN(), g.DGPSAge},
+	}
+	for _, rec := range floats {
+		if !near(rec.expect, rec.found) {
+			t.Errorf("%s expecting %v, got %v", rec.name, rec.expect, rec.found)
+		}
+	}
+	if g.Quality != 1 || g.Satellites != 8 || g.DGPSStation != -1 {
+		t.Errorf("expecting quality 1, 8 satellites, no station, got %d, %d, %d", g.Quality, g.Satellites, g.DGPSStation)
+	}
+}
+
+func TestParseRMC(t *testing.T) {
+	r, err := ParseRMC(mustParse(t, rmc))
+	if err != nil {
+		t.Fatalf("ParseRMC unexpected error %v", err)
+	}
+	expectTime := time.Date(1994, 3, 23, 12, 35, 19, 0, time.UTC)
+	if !r.Time.Equal(expectTime) {
+		t.Errorf("Time expecting %v, got %v", expectTime, r.Time)
+	}
+	if !r.Valid || r.Mode != 0 {
+		t.Errorf("expecting valid without mode, got %v %q", r.Valid, r.Mode)
+	}
+	floats := []struct {
+		name          string
+		expect, found float64
+	}{
+		{"Lat", 48 + 7.038/60, r.Lat},
+		{"Lon", 11 + 31.0/60, r.Lon},
+		{"Speed", 22.4, r.Speed},
+		{"Course", 84.4, r.Course},
+		{"Variation", -3.1, r.Variation},
+	}
+	for _, rec := range floats {
+		if !near(rec.expect, rec.found) {
+			t.Errorf("%s expecting %v, got %v", rec.name, rec.expect, rec.found)
+		}
+	}
+}
+
+func TestParseVTG(t *testing.T) {
+	v, err := ParseVTG(mustParse(t, vtg))
+	if err != nil {
+		t.Fatalf("ParseVTG unexpected error %v", err)
+	}
+	expect := VTG{54.7, 34.4, 5.5, 10.2, 0}
+	if v != expect {
+		t.Errorf("ParseVTG expecting %v, got %v", expect, v)
+	}
+}
+
+func TestParseGSA(t *testing.T) {
+	g, err := ParseGSA(mustParse(t, gsa))
+	if err != nil {
+		t.Fatalf("ParseGSA unexpected error %v", err)
+	}
+	expect := GSA{true, 3, []int{4, 5, 9, 12, 24}, 2.5, 1.3, 2.1}
+	if !reflect.DeepEqual(g, expect) {
+		t.Errorf("ParseGSA expecting %v, got %v", expect, g)
+	}
+}
+
+func TestParseGSV(t *testing.T) {
+	g, err := ParseGSV(mustParse(t, gsv))
+	if err != nil {
+		t.Fatalf("ParseGSV unexpected error %v", err)
+	}
+	expect := GSV{2, 1, 8, []SatInfo{{1, 40, 83, 46}, {2, 17, 308, 41}, {12, 7, 344, 39}, {14, 22, 228, 45}}}
+	if !reflect.DeepEqual(g, expect) {
+		t.Errorf("ParseGSV expecting %v, got %v", expect, g)
+	}
+}
+
+func TestDecodeError(t *testing.T) {
+	if _, err := ParseGGA(mustParse(t, rmc)); err != ErrType {
+		t.Errorf("ParseGGA(RMC) expecting %v, got %v", ErrType, err)
+	}
+	tests := []struct {
+		line  string
+		index int
+	}{
+		//Bad hemisphere
+		{Sentence{'$', "GP", "GGA", []string{"123519", "4807.038", "X", "01131.000", "E", "1"}}.String(), 2},
+		//Bad time
+		{Sentence{'$', "GP", "GGA", []string{"1235"}}.String(), 0},
+		{Sentence{'$', "GP", "GGA", []string{"126019"}}.String(), 0},
+		//Bad number
+		{Sentence{'$', "GP", "GGA", []string{"", "", "", "", "", "1", "x8"}}.String(), 6},
+	}
+	for _, rec := range tests {
+		_, err := ParseGGA(mustParse(t, rec.line))
+		var fe FieldError
+		if !errors.As(err, &fe) || fe.Index != rec.index {
+			t.Errorf("ParseGGA(%q) expecting field %d error, got %v", rec.line, rec.index, err)
+		}
+	}
+	dates := []struct {
+		date string
+		ok   bool
+	}{
+		{"320394", false},
+		{"310494", false},
+		{"300494", true},
+		{"290200", true}, //2000 is a leap year
+		{"290201", false},
+		{"290296", true},
+		{"311299", true},
+	}
+	for _, rec := range dates {
+		_, err := ParseRMC(mustParse(t, Sentence{'$', "GP", "RMC", []string{"123519", "A", "", "", "", "", "", "", rec.date}}.String()))
+		if rec.ok && err != nil {
+			t.Errorf("ParseRMC %s unexpected error %v", rec.date, err)
+		}
+		if !rec.ok && !errors.Is(err, ErrField) {
+			t.Errorf("ParseRMC %s expecting %v, got %v", rec.date, ErrField, err)
+		}
+	}
+}
+
+func TestParseVDM(t *testing.T) {
+	v, err := ParseVDM(mustParse(t, vdm))
+	if err != nil {
+		t.Fatalf("ParseVDM unexpected error %v", err)
+	}
+	expect := VDM{false, 1, 1, -1, "B", "177KQJ5000G?tO`K>RA1wUbN0TKH", 0}
+	if v != expect {
+		t.Errorf("ParseVDM expecting %v, got %v", expect, v)
+	}
+	if found := v.Sentence().String(); found != vdm {
+		t.Errorf("Sentence() expecting %q, got %q", vdm, found)
+	}
+	data, n, err := Dearmour(v.Payload, v.Fill)
+	if err != nil {
+		t.Fatalf("Dearmour unexpected error %v", err)
+	}
+	//https://gpsd.gitlab.io/gpsd/AIVDM.html worked example
+	fields := []struct {
+		name      string
+		at, width int
+		expect    uint64
+	}{
+		{"type", 0, 6, 1},
+		{"repeat", 6, 2, 0},
+		{"mmsi", 8, 30, 477553000},
+		{"status", 38, 4, 5},
+		{"speed", 50, 10, 0},
+		{"course", 116, 12, 510},
+	}
+	if n != 168 {
+		t.Errorf("Dearmour expecting 168 bits, got %d", n)
+	}
+	for _, rec := range fields {
+		if found := Bits(data, rec.at, rec.width); found != rec.expect {
+			t.Errorf("%s expecting %d, got %d", rec.name, rec.expect, found)
+		}
+	}
+	lon := int64(Bits(data, 61, 28)<<36) >> 36
+	if !near(float64(lon)/600000, -122.34583333333333) {
+		t.Errorf("lon expecting -122.345833, got %v", float64(lon)/600000)
+	}
+}
+
+func TestArmour(t *testing.T) {
+	data := []byte{0x04, 0x71, 0xdb, 0x85, 0xa1, 0x40, 0x00, 0x05, 0xcf, 0xf1, 0xfa, 0x1b, 0x3a, 0x24, 0x41, 0xfe, 0x5a, 0x9e, 0x02, 0x46, 0xd8}
+	payload, fill := Armour(data, 168)
+	if payload != "177KQJ5000G?tO`K>RA1wUbN0TKH" || fill != 0 {
+		t.Errorf("Armour expecting 177KQJ5000G?tO`K>RA1wUbN0TKH/0, got %s/%d", payload, fill)
+	}
+	for n := 0; n <= 24; n++ {
+		p, fill := Armour([]byte{0xa5, 0xff, 0x3c}, n)
+		back, bn, err := Dearmour(p, fill)
+		if err != nil || bn != n || Bits(back, 0, n) != Bits([]byte{0xa5, 0xff, 0x3c}, 0, n) {
+			t.Errorf("Armour/Dearmour %d bits round trip failed: %x %d %v", n, back, bn, err)
+		}
+	}
+	for _, bad := range []string{"1X", "1a ", "x"} {
+		if _, _, err := Dearmour(bad, 0); err != ErrArmour {
+			t.Errorf("Dearmour(%q) expecting %v, got %v", bad, ErrArmour, err)
+		}
+	}
+}
+
+func TestAssembler(t *testing.T) {
+	frag := func(n, seq int, ch, p string, fill int) VDM {
+		return VDM{Count: 2, Number: n, SeqID: seq, Channel: ch, Payload: p, Fill: fill}
+	}
+	var a Assembler
+	steps := []struct {
+		v      VDM
+		expect string
+		ok     bool
+	}{
+		{frag(1, 3, "A", "5abc", 0), "", false},
+		{frag(1, 4, "B", "5xyz", 0), "", false},
+		{frag(2, 3, "A", "def", 2), "5abcdef", true},
+		//Out of order, the partial is dropped
+		{frag(2, 3, "A", "def", 2), "", false},
+		{VDM{Count: 1, Number: 1, SeqID: -1, Payload: "1abc"}, "1abc", true},
+		{frag(2, 4, "B", "uvw", 2), "5xyzuvw", true},
+	}
+	for i, rec := range steps {
+		p, _, ok := a.Add(rec.v)
+		if p != rec.expect || ok != rec.ok {
+			t.Errorf("Add step %d expecting %q %v, got %q %v", i, rec.expect, rec.ok, p, ok)
+		}
+	}
+}
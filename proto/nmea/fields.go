@@ -0,0 +1,151 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+package nmea
+
+import (
+	"math"
+	"strconv"
+	"time"
+)
+
+// Field reader for a sentence, the first error is kept
+type fields struct {
+	s   Sentence
+	err error
+}
+
+func (f *fields) fail(i int) {
+	if f.err == nil {
+		v := ""
+		if i < len(f.s.Fields) {
+			v = f.s.Fields[i]
+		}
+		f.err = FieldError{f.s.Type, i, v}
+	}
+}
+
+func (f *fields) str(i int) string {
+	if i < len(f.s.Fields) {
+		return f.s.Fields[i]
+	}
+	return ""
+}
+
+// A float, NaN when empty
+func (f *fields) float(i int) float64 {
+	v := f.str(i)
+	if v == "" {
+		return math.NaN()
+	}
+	ret, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		f.fail(i)
+	}
+	return ret
+}
+
+// An int, `empty` when empty
+func (f *fields) int(i, empty int) int {
+	v := f.str(i)
+	if v == "" {
+		return empty
+	}
+	ret, err := strconv.Atoi(v)
+	if err != nil {
+		f.fail(i)
+	}
+	return ret
+}
+
+// A single character, 0 when empty
+func (f *fields) char(i int) byte {
+	v := f.str(i)
+	if len(v) > 1 {
+		f.fail(i)
+	}
+	if v == "" {
+		return 0
+	}
+	return v[0]
+}
+
+// Latitude (ddmm.mmm) or longitude (dddmm.mmm) at i with the hemisphere at
+// i+1, in signed degrees (NaN when empty)
+func (f *fields) coord(i int, pos, neg byte) float64 {
+	v := f.float(i)
+	if math.IsNaN(v) {
+		return v
+	}
+	deg := math.Trunc(v / 100)
+	return f.signed(deg+(v-deg*100)/60, i+1, pos, neg)
+}
+
+// A float at i with its direction at i+1, negative for `neg` (NaN when empty)
+func (f *fields) signed(v float64, i int, pos, neg byte) float64 {
+	if math.IsNaN(v) {
+		return v
+	}
+	switch f.char(i) {
+	case pos:
+	case neg:
+		v = -v
+	default:
+		f.fail(i)
+	}
+	return v
+}
+
+// UTC time of day (hhmmss.sss), -1 when empty
+func (f *fields) clock(i int) time.Duration {
+	v := f.str(i)
+	if v == "" {
+		return -1
+	}
+	if len(v) < 6 {
+		f.fail(i)
+		return -1
+	}
+	h, e1 := strconv.Atoi(v[:2])
+	m, e2 := strconv.Atoi(v[2:4])
+	s, e3 := strconv.ParseFloat(v[4:], 64)
+	if e1 != nil || e2 != nil || e3 != nil || h > 23 || m > 59 || s >= 61 {
+		f.fail(i)
+		return -1
+	}
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute +
+		time.Duration(math.Round(s*1000))*time.Millisecond
+}
+
+// Date (ddmmyy), two digit years are 1980-2079, the day must exist in the month
+func (f *fields) date(i int) (year int, month time.Month, day int) {
+	v := f.str(i)
+	if v == "" {
+		return
+	}
+	d, e1 := strconv.Atoi(v[:min(2, len(v))])
+	m, e2 := strconv.Atoi(v[min(2, len(v)):min(4, len(v))])
+	y, e3 := strconv.Atoi(v[min(4, len(v)):])
+	if len(v) != 6 || e1 != nil || e2 != nil || e3 != nil || m < 1 || m > 12 || d < 1 || d > 31 {
+		f.fail(i)
+		return
+	}
+	if y < 80 {
+		y += 2000
+	} else {
+		y += 1900
+	}
+	//Day zero of the next month is the last day of this one
+	if d > time.Date(y, time.Month(m)+1, 0, 0, 0, 0, 0, time.UTC).Day() {
+		f.fail(i)
+		return 0, 0, 0
+	}
+	return y, time.Month(m), d
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,136 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+// NMEA 0183 sentences: checksum, parse/generate, decoders for common sentence
+// types and AIS payload armouring
+package nmea
+
+//https://gpsd.gitlab.io/gpsd/NMEA.html
+//https://gpsd.gitlab.io/gpsd/AIVDM.html
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gnabgib/gnablib-go/checksum/bcc"
+)
+
+// The sentence doesn't start with '$' or '!' (use errors.Is to test for this)
+var ErrStart = errors.New("NMEA sentence must start with '$' or '!'")
+
+// The sentence has no '*' checksum, or it isn't two hex digits
+var ErrChecksumFormat = errors.New("NMEA sentence needs a two hex digit checksum")
+
+// The checksum doesn't match (use errors.Is to test for this)
+var ErrChecksum = errors.New("NMEA checksum mismatch")
+
+// A field is invalid (use errors.Is to test for this)
+var ErrField = errors.New("invalid NMEA field")
+
+// The checksum calculated from the content, and the one found
+type ChecksumError struct {
+	Expect byte
+	Found  byte
+}
+
+func (e ChecksumError) Error() string {
+	return fmt.Sprintf("NMEA checksum mismatch: expected %02X, found %02X", e.Expect, e.Found)
+}
+
+func (e ChecksumError) Unwrap() error { return ErrChecksum }
+
+// The FieldError index of the address (talker and sentence type)
+const AddressIndex = -1
+
+// An invalid field, by sentence type and field index (0 is the first field
+// after the address, AddressIndex for the address itself)
+type FieldError struct {
+	Type  string
+	Index int
+	Value string
+}
+
+func (e FieldError) Error() string {
+	if e.Index == AddressIndex {
+		return fmt.Sprintf("Invalid NMEA address: %q", e.Value)
+	}
+	return fmt.Sprintf("Invalid NMEA %s field %d: %q", e.Type, e.Index, e.Value)
+}
+
+func (e FieldError) Unwrap() error { return ErrField }
+
+// A sentence split into its parts
+type Sentence struct {
+	Start  byte   //'$' for parametric sentences, '!' for encapsulated (AIS)
+	Talker string //eg. GP, GN, AI (P for proprietary)
+	Type   string //eg. GGA, RMC, VDM
+	Fields []string
+}
+
+// The 8bit XOR (BCC) of the content between the start and '*'
+func Checksum(body string) byte {
+	d := bcc.New()
+	d.Write([]byte(body))
+	return d.Sum8()
+}
+
+func unhex(b byte) (byte, bool) {
+	switch {
+	case b >= '0' && b <= '9':
+		return b - '0', true
+	case b >= 'A' && b <= 'F':
+		return b - 'A' + 10, true
+	case b >= 'a' && b <= 'f':
+		return b - 'a' + 10, true
+	}
+	return 0, false
+}
+
+// Parse a sentence (a trailing CR LF is ignored), the checksum is required.
+// Returns ErrStart, ErrChecksumFormat, a ChecksumError, or a FieldError if the
+// address is invalid
+func Parse(line string) (Sentence, error) {
+	line = strings.TrimRight(line, "\r\n")
+	if len(line) < 1 || (line[0] != '$' && line[0] != '!') {
+		return Sentence{}, ErrStart
+	}
+	star := strings.LastIndexByte(line, '*')
+	if star < 0 || star != len(line)-3 {
+		return Sentence{}, ErrChecksumFormat
+	}
+	hi, ok1 := unhex(line[star+1])
+	lo, ok2 := unhex(line[star+2])
+	if !ok1 || !ok2 {
+		return Sentence{}, ErrChecksumFormat
+	}
+	body := line[1:star]
+	if expect, found := Checksum(body), hi<<4|lo; expect != found {
+		return Sentence{}, ChecksumError{expect, found}
+	}
+	parts := strings.Split(body, ",")
+	s := Sentence{Start: line[0], Fields: parts[1:]}
+	addr := parts[0]
+	switch {
+	case len(addr) >= 2 && addr[0] == 'P':
+		s.Talker, s.Type = "P", addr[1:]
+	case len(addr) == 5:
+		s.Talker, s.Type = addr[:2], addr[2:]
+	default:
+		return Sentence{}, FieldError{"address", AddressIndex, addr}
+	}
+	return s, nil
+}
+
+// The sentence with its checksum (without a trailing CR LF)
+func (s Sentence) String() string {
+	body := s.Talker + s.Type
+	if len(s.Fields) > 0 {
+		body += "," + strings.Join(s.Fields, ",")
+	}
+	start := s.Start
+	if start == 0 {
+		start = '$'
+	}
+	return fmt.Sprintf("%c%s*%02X", start, body, Checksum(body))
+}
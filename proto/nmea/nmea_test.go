@@ -0,0 +1,106 @@
+package nmea
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const (
+	gga = "$GPGGA,123519,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,*47"
+	rmc = "$GPRMC,123519,A,4807.038,N,01131.000,E,022.4,084.4,230394,003.1,W*6A"
+	vtg = "$GPVTG,054.7,T,034.4,M,005.5,N,010.2,K*48"
+	gsa = "$GPGSA,A,3,04,05,,09,12,,,24,,,,,2.5,1.3,2.1*39"
+	gsv = "$GPGSV,2,1,08,01,40,083,46,02,17,308,41,12,07,344,39,14,22,228,45*75"
+	vdm = "!AIVDM,1,1,,B,177KQJ5000G?tO`K>RA1wUbN0TKH,0*5C"
+)
+
+func TestChecksum(t *testing.T) {
+	tests := []struct {
+		body   string
+		expect byte
+	}{
+		{"GPGGA,123519,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,", 0x47},
+		{"GPVTG,054.7,T,034.4,M,005.5,N,010.2,K", 0x48},
+		{"", 0},
+	}
+	for _, rec := range tests {
+		found := Checksum(rec.body)
+		if found != rec.expect {
+			t.Errorf("Checksum(%q) expecting %02X, got %02X", rec.body, rec.expect, found)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		line   string
+		expect Sentence
+	}{
+		{gga + "\r\n", Sentence{'$', "GP", "GGA", []string{"123519", "4807.038", "N", "01131.000", "E", "1", "08", "0.9", "545.4", "M", "46.9", "M", "", ""}}},
+		{vtg, Sentence{'$', "GP", "VTG", []string{"054.7", "T", "034.4", "M", "005.5", "N", "010.2", "K"}}},
+		{vdm, Sentence{'!', "AI", "VDM", []string{"1", "1", "", "B", "177KQJ5000G?tO`K>RA1wUbN0TKH", "0"}}},
+		//Lower case checksum, proprietary address
+		{"$PGRME,15.0,M,45.0,M,25.0,M*1c", Sentence{'$', "P", "GRME", []string{"15.0", "M", "45.0", "M", "25.0", "M"}}},
+	}
+	for _, rec := range tests {
+		found, err := Parse(rec.line)
+		if err != nil {
+			t.Errorf("Parse(%q) unexpected error %v", rec.line, err)
+			continue
+		}
+		if !reflect.DeepEqual(found, rec.expect) {
+			t.Errorf("Parse(%q) expecting %v, got %v", rec.line, rec.expect, found)
+		}
+	}
+}
+
+func TestParseError(t *testing.T) {
+	tests := []struct {
+		line   string
+		expect error
+	}{
+		{"GPVTG,054.7,T,034.4,M,005.5,N,010.2,K*48", ErrStart},
+		{"", ErrStart},
+		{"$GPVTG,054.7,T,034.4,M,005.5,N,010.2,K", ErrChecksumFormat},
+		{"$GPVTG,054.7,T,034.4,M,005.5,N,010.2,K*4", ErrChecksumFormat},
+		{"$GPVTG,054.7,T,034.4,M,005.5,N,010.2,K*4G", ErrChecksumFormat},
+		{"$GPVTG,054.7,T,034.4,M,005.5,N,010.2,K*49", ErrChecksum},
+		{"$GPVTG,054.7,T,034.4,M,005.6,N,010.2,K*48", ErrChecksum},
+		{"$GPVT,054.7*11", ErrField},
+	}
+	for _, rec := range tests {
+		_, err := Parse(rec.line)
+		if !errors.Is(err, rec.expect) {
+			t.Errorf("Parse(%q) expecting %v, got %v", rec.line, rec.expect, err)
+		}
+	}
+	var ce ChecksumError
+	if _, err := Parse("$GPVTG,054.7,T,034.4,M,005.5,N,010.2,K*49"); !errors.As(err, &ce) || ce.Expect != 0x48 || ce.Found != 0x49 {
+		t.Errorf("Parse expecting ChecksumError{48,49}, got %v", err)
+	}
+	var fe FieldError
+	_, err := Parse("$GPVT,054.7*11")
+	if !errors.As(err, &fe) || fe.Index != AddressIndex {
+		t.Errorf("Parse expecting an address FieldError, got %v", err)
+	} else if found := err.Error(); found != `Invalid NMEA address: "GPVT"` {
+		t.Errorf("Unexpected message %q", found)
+	}
+}
+
+func TestString(t *testing.T) {
+	for _, line := range []string{gga, rmc, vtg, gsa, gsv, vdm} {
+		s, err := Parse(line)
+		if err != nil {
+			t.Errorf("Parse(%q) unexpected error %v", line, err)
+			continue
+		}
+		if found := s.String(); found != line {
+			t.Errorf("String() expecting %q, got %q", line, found)
+		}
+	}
+	s := Sentence{Talker: "GP", Type: "VTG", Fields: []string{"054.7", "T", "034.4", "M", "005.5", "N", "010.2", "K"}}
+	if found := s.String(); found != vtg {
+		t.Errorf("String() expecting %q, got %q", vtg, found)
+	}
+}
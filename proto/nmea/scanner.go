@@ -0,0 +1,82 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+package nmea
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+)
+
+// Longest line the scanner accepts, the standard allows 82 characters (including
+// CR LF) but some devices exceed it
+const MaxLine = 256
+
+// Reads sentences from a stream (a serial port, a log), lines that don't parse
+// are skipped so the scanner resyncs on the next good sentence
+type Scanner struct {
+	r       *bufio.Reader
+	s       Sentence
+	err     error
+	Skipped int //Lines (or line fragments) dropped because they didn't parse
+}
+
+func NewScanner(r io.Reader) *Scanner {
+	return &Scanner{r: bufio.NewReaderSize(r, MaxLine)}
+}
+
+// Advance to the next valid sentence, false at the end of input or on a read
+// error (see Err)
+func (sc *Scanner) Scan() bool {
+	for sc.err == nil {
+		line, err := sc.r.ReadSlice('\n')
+		if err == bufio.ErrBufferFull {
+			//Overlong, drop the rest of the line
+			for err == bufio.ErrBufferFull {
+				_, err = sc.r.ReadSlice('\n')
+			}
+			sc.Skipped++
+			if err != nil {
+				sc.err = err
+			}
+			continue
+		}
+		if err != nil {
+			sc.err = err
+		}
+		line = bytes.TrimRight(line, "\r\n")
+		if len(line) == 0 {
+			continue
+		}
+		//A sentence may follow noise or a truncated sentence, start at the last
+		//start character
+		at := bytes.LastIndexAny(line, "$!")
+		if at < 0 {
+			sc.Skipped++
+			continue
+		}
+		if at > 0 {
+			sc.Skipped++
+		}
+		s, perr := Parse(string(line[at:]))
+		if perr != nil {
+			sc.Skipped++
+			continue
+		}
+		sc.s = s
+		return true
+	}
+	return false
+}
+
+// The sentence read by the last successful Scan
+func (sc *Scanner) Sentence() Sentence { return sc.s }
+
+// The first read error, nil at the end of input
+func (sc *Scanner) Err() error {
+	if sc.err == io.EOF {
+		return nil
+	}
+	return sc.err
+}
@@ -0,0 +1,48 @@
+package nmea
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestScanner(t *testing.T) {
+	in := strings.Join([]string{
+		gga,
+		"",
+		"garbage",
+		"$GPRMC,1235" + vtg, //Truncated sentence, then a good one
+		"$GPGSA,A,3,04,05,,09,12,,,24,,,,,2.5,1.3,2.1*38", //Bad checksum
+		"$" + strings.Repeat("X", MaxLine*2),
+		gsa,
+		vdm, //No trailing newline
+	}, "\r\n")
+	sc := NewScanner(strings.NewReader(in))
+	var found []string
+	for sc.Scan() {
+		found = append(found, sc.Sentence().String())
+	}
+	expect := []string{gga, vtg, gsa, vdm}
+	if strings.Join(found, "|") != strings.Join(expect, "|") {
+		t.Errorf("Scan expecting %v, got %v", expect, found)
+	}
+	if sc.Skipped != 4 {
+		t.Errorf("Skipped expecting 4, got %d", sc.Skipped)
+	}
+	if sc.Err() != nil {
+		t.Errorf("Err expecting nil, got %v", sc.Err())
+	}
+}
+
+func TestScannerReadError(t *testing.T) {
+	sc := NewScanner(io.MultiReader(strings.NewReader(gga+"\n"+rmc), iotest.ErrReader(iotest.ErrTimeout)))
+	n := 0
+	for sc.Scan() {
+		n++
+	}
+	if n != 2 || !errors.Is(sc.Err(), iotest.ErrTimeout) {
+		t.Errorf("expecting 2 sentences and %v, got %d and %v", iotest.ErrTimeout, n, sc.Err())
+	}
+}
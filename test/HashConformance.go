@@ -0,0 +1,179 @@
+// Copyright 2023 gnabgib
+// This Source Code Form is subject to the terms of the Mozilla Public License v2.0
+
+package test
+
+import (
+	"bytes"
+	"fmt"
+	"hash"
+	"io"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/gnabgib/gnablib-go/encoding/hex"
+)
+
+// An input and the expected hash (as upper case hex) of that input
+type HashVector struct {
+	In  string
+	Hex string
+}
+
+// Build `n` vectors for a checksum whose sum is a `size` byte integer (written
+// big endian by Sum), `vector` gives the input and expected sum of each
+func SumVectors(n, size int, vector func(i int) (in string, sum uint64)) []HashVector {
+	ret := make([]HashVector, n)
+	for i := range ret {
+		in, sum := vector(i)
+		ret[i] = HashVector{In: in, Hex: fmt.Sprintf("%0*X", 2*size, sum)}
+	}
+	return ret
+}
+
+// Number of random-chunk passes made over each vector
+const randomChunkPasses = 8
+
+// Confirm a hash produces the expected output for every vector no matter how the
+// input is chunked, and that it behaves as `hash.Hash` describes:
+//   - The input is split at every byte boundary, and into random chunks
+//   - `Sum` appends to its argument, and doesn't change the state (it can be
+//     called repeatedly, and writing can continue after)
+//   - `Reset` restores the initial state
+//   - `Size` matches the length of the output, `BlockSize` is positive
+func HashConformance(t *testing.T, factory func() hash.Hash, vectors []HashVector) {
+	//Seeded so failures can be reproduced
+	rnd := rand.New(rand.NewSource(0x676e6162))
+	for _, rec := range vectors {
+		in := []byte(rec.In)
+		title := "Hash(" + Abbr(rec.In) + ")"
+
+		//Split at every boundary (including 0 and n, which are empty writes)
+		for i := 0; i <= len(in); i++ {
+			h := factory()
+			h.Write(in[:i])
+			h.Write(in[i:])
+			if !hashMatch(t, title, rec.Hex, h.Sum(nil)) {
+				t.Errorf("%s: failed when split at %d", title, i)
+				break
+			}
+		}
+
+		//Random chunks
+		for pass := 0; pass < randomChunkPasses; pass++ {
+			h := factory()
+			writeRandomChunks(h, in, rnd)
+			if !hashMatch(t, title, rec.Hex, h.Sum(nil)) {
+				t.Errorf("%s: failed in random chunk pass %d", title, pass)
+				break
+			}
+		}
+
+		h := factory()
+		h.Write(in)
+
+		//Sum must append to the argument, and leave it intact
+		prefix := []byte("prefix")
+		found := h.Sum(prefix)
+		if !bytes.Equal(found[:len(prefix)], []byte("prefix")) {
+			t.Errorf("%s: Sum modified the prefix, found %x", title, found[:len(prefix)])
+		}
+		hashMatch(t, title+" after prefix", rec.Hex, found[len(prefix):])
+
+		//Sum must not modify the state.. a second sum should be the same
+		hashMatch(t, title+" second Sum", rec.Hex, h.Sum(nil))
+
+		//Writing after Sum should be the same as writing without Sum
+		h.Write(in)
+		ref := factory()
+		ref.Write(in)
+		ref.Write(in)
+		if !bytes.Equal(h.Sum(nil), ref.Sum(nil)) {
+			t.Errorf("%s: Write after Sum differs from uninterrupted Write", title)
+		}
+
+		//Reset should take us back to the initial state
+		h.Reset()
+		h.Write(in)
+		hashMatch(t, title+" after Reset", rec.Hex, h.Sum(nil))
+
+		//Size should match the output (with or without content)
+		if n := len(h.Sum(nil)); n != h.Size() {
+			t.Errorf("%s: Size()=%d but Sum returned %d bytes", title, h.Size(), n)
+		}
+		if h.BlockSize() <= 0 {
+			t.Errorf("%s: BlockSize()=%d should be positive", title, h.BlockSize())
+		}
+	}
+
+	//An unused hash must also match its size
+	h := factory()
+	if n := len(h.Sum(nil)); n != h.Size() {
+		t.Errorf("Size()=%d but empty Sum returned %d bytes", h.Size(), n)
+	}
+}
+
+// Hash a long message made by repeating `pattern` `count` times, without holding
+// the message in memory (the classic "one million 'a'" vector)
+func HashLongTest(t *testing.T, h hash.Hash, pattern string, count int, expectHex string) {
+	_, err := io.Copy(h, RepeatReader([]byte(pattern), count))
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+		return
+	}
+	found := hex.FromBytes(h.Sum([]byte{}))
+	StringMatchTitle(t, "HashLong("+Abbr(pattern)+"x"+strconv.Itoa(count)+")", "", expectHex, found)
+}
+
+// Compare a sum to an expected hex, reports a mismatch and returns whether they matched
+func hashMatch(t *testing.T, title, expectHex string, found []byte) bool {
+	foundHex := hex.FromBytes(found)
+	if foundHex == expectHex {
+		return true
+	}
+	StringMatchTitle(t, title, "", expectHex, foundHex)
+	return false
+}
+
+// Write `in` to `h` in random sized chunks (including empty ones)
+func writeRandomChunks(h hash.Hash, in []byte, rnd *rand.Rand) {
+	//Chunks can be up to a few blocks in size
+	maxChunk := h.BlockSize()*3 + 1
+	for len(in) > 0 {
+		n := rnd.Intn(maxChunk)
+		if n > len(in) {
+			n = len(in)
+		}
+		h.Write(in[:n])
+		in = in[n:]
+	}
+}
+
+type repeatReader struct {
+	pattern []byte
+	pos     int   //Position in pattern
+	remain  int64 //Bytes remaining
+}
+
+// A reader that produces `pattern` repeated `count` times, and then EOF. Only
+// the pattern is held in memory
+func RepeatReader(pattern []byte, count int) io.Reader {
+	return &repeatReader{pattern: pattern, remain: int64(len(pattern)) * int64(count)}
+}
+
+func (r *repeatReader) Read(p []byte) (n int, err error) {
+	if r.remain <= 0 {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > r.remain {
+		p = p[:r.remain]
+	}
+	for n < len(p) {
+		c := copy(p[n:], r.pattern[r.pos:])
+		n += c
+		r.pos = (r.pos + c) % len(r.pattern)
+	}
+	r.remain -= int64(n)
+	return
+}
@@ -0,0 +1,55 @@
+package test
+
+import (
+	"io"
+	"testing"
+)
+
+var repeatReaderTests = []struct {
+	pattern string
+	count   int
+	expect  string
+}{
+	{"a", 0, ""},
+	{"a", 1, "a"},
+	{"a", 5, "aaaaa"},
+	{"abc", 3, "abcabcabc"},
+	{"", 10, ""},
+}
+
+func TestRepeatReader(t *testing.T) {
+	for _, rec := range repeatReaderTests {
+		found, err := io.ReadAll(RepeatReader([]byte(rec.pattern), rec.count))
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		StringMatch(t, rec.expect, string(found))
+	}
+}
+
+func TestRepeatReader_smallBuffer(t *testing.T) {
+	r := RepeatReader([]byte("abcd"), 3)
+	found := []byte{}
+	buf := make([]byte, 5)
+	for {
+		n, err := r.Read(buf)
+		found = append(found, buf[:n]...)
+		if err == io.EOF {
+			break
+		}
+	}
+	StringMatch(t, "abcdabcdabcd", string(found))
+}
+
+func TestSumVectors(t *testing.T) {
+	sums := []uint64{0x5, 0xABCD}
+	found := SumVectors(len(sums), 4, func(i int) (string, uint64) {
+		return string(rune('a' + i)), sums[i]
+	})
+	expect := []HashVector{{"a", "00000005"}, {"b", "0000ABCD"}}
+	for i := range expect {
+		if found[i] != expect[i] {
+			t.Errorf("Expecting %v, got %v", expect[i], found[i])
+		}
+	}
+}